@@ -31,7 +31,7 @@ func NewDense(rows, cols int, elements []float64) *Dense {
 		panic("mat: elements cannot be nil. Use NewEmptyDense() instead.")
 	}
 	if len(elements) != rows*cols {
-		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", rows*cols))
+		panic(&ShapeError{Op: "NewDense", Shapes: [][]int{{rows, cols}, {len(elements)}}})
 	}
 	d := GetDenseWorkspace(rows, cols)
 	_ = append(d.data[:0], elements...)
@@ -69,7 +69,7 @@ func NewEmptyDense(rows, cols int) *Dense {
 // NewEmptyVecDense returns a new one-hot vector of the given size.
 func OneHotVecDense(size int, oneAt int) *Dense {
 	if oneAt >= size {
-		panic(fmt.Sprintf("mat: impossible to set the one at index %d. The size is: %d", oneAt, size))
+		panic(&IndexError{Index: []int{oneAt}, Shape: []int{size, 1}})
 	}
 	vec := NewEmptyVecDense(size)
 	vec.SetVec(oneAt, 1.0)
@@ -94,7 +94,7 @@ func NewInitVecDense(size int, val float64) *Dense {
 // SetData sets the data
 func (d *Dense) SetData(data []float64) {
 	if len(data) != d.size {
-		panic(fmt.Sprintf("mat: data size must be: %d", d.size))
+		panic(&ShapeError{Op: "SetData", Shapes: [][]int{{d.rows, d.cols}, {len(data)}}})
 	}
 	_ = append(d.data[:0], data...)
 }
@@ -122,19 +122,15 @@ func (d *Dense) Clone() Matrix {
 // Copy copies the data to the receiver.
 func (d *Dense) Copy(other Matrix) {
 	if !SameDims(d, other) {
-		panic("mat: incompatible matrix dimensions.")
-	}
-	if other, ok := other.(*Dense); !ok {
-		panic("mat: incompatible matrix types.")
-	} else {
-		_ = append(d.data[:0], other.data...)
+		panic(NewShapeError("Copy", d, other))
 	}
+	_ = append(d.data[:0], asDense(other).data...)
 }
 
 // View returns a new Matrix sharing the same underlying data.
 func (d *Dense) View(rows, cols int) *Dense {
 	if d.Size() != rows*cols {
-		panic("mat: incompatible sizes.")
+		panic(&ShapeError{Op: "View", Shapes: [][]int{{d.rows, d.cols}, {rows, cols}}})
 	}
 	return &Dense{
 		rows:     rows,
@@ -197,7 +193,7 @@ func (d *Dense) IsScalar() bool {
 // Scalar returns the scalar. It panics if the matrix contains more elements.
 func (d *Dense) Scalar() float64 {
 	if !d.IsScalar() {
-		panic("mat: expected scalar but the matrix contains more elements.")
+		panic(&ShapeError{Op: "Scalar", Shapes: [][]int{{d.rows, d.cols}, {1, 1}}})
 	}
 	return d.data[0]
 }
@@ -205,10 +201,10 @@ func (d *Dense) Scalar() float64 {
 // Set sets the value v at row i and column j.
 func (d *Dense) Set(i int, j int, v float64) {
 	if i >= d.rows {
-		panic("mat: 'i' argument out of range.")
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
 	}
 	if j >= d.cols {
-		panic("mat: 'j' argument out of range")
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
 	}
 	d.data[i*d.cols+j] = v
 }
@@ -216,10 +212,10 @@ func (d *Dense) Set(i int, j int, v float64) {
 // At returns the value at row i and column j.
 func (d *Dense) At(i int, j int) float64 {
 	if i >= d.rows {
-		panic("mat: 'i' argument out of range.")
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
 	}
 	if j >= d.cols {
-		panic("mat: 'j' argument out of range")
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
 	}
 	return d.data[i*d.cols+j]
 }
@@ -228,10 +224,10 @@ func (d *Dense) At(i int, j int) float64 {
 // It panics if not IsVector().
 func (d *Dense) SetVec(i int, v float64) {
 	if !(d.IsVector()) {
-		panic("mat: expected vector")
+		panic(NewShapeError("SetVec", d))
 	}
 	if i >= d.rows {
-		panic("mat: 'i' argument out of range.")
+		panic(&IndexError{Index: []int{i}, Shape: []int{d.rows, d.cols}})
 	}
 	d.data[i] = v
 }
@@ -240,10 +236,10 @@ func (d *Dense) SetVec(i int, v float64) {
 // It panics if not IsVector().
 func (d *Dense) AtVec(i int) float64 {
 	if !(d.IsVector()) {
-		panic("mat: expected vector")
+		panic(NewShapeError("AtVec", d))
 	}
 	if i >= d.rows {
-		panic("mat: 'i' argument out of range.")
+		panic(&IndexError{Index: []int{i}, Shape: []int{d.rows, d.cols}})
 	}
 	return d.data[i]
 }
@@ -251,7 +247,7 @@ func (d *Dense) AtVec(i int) float64 {
 // ExtractRow returns a copy of the i-th row of the matrix.
 func (d *Dense) ExtractRow(i int) Matrix {
 	if i >= d.Rows() {
-		panic("mat: index out of range")
+		panic(&IndexError{Index: []int{i}, Shape: []int{d.rows, d.cols}})
 	}
 	out := NewVecDense(d.data[i*d.cols : i*d.cols+d.cols])
 	return out
@@ -260,7 +256,7 @@ func (d *Dense) ExtractRow(i int) Matrix {
 // ExtractRow returns a copy of the i-th column of the matrix.
 func (d *Dense) ExtractColumn(i int) Matrix {
 	if i >= d.Columns() {
-		panic("mat: index out of range")
+		panic(&IndexError{Index: []int{i}, Shape: []int{d.rows, d.cols}})
 	}
 	//out := NewEmptyVecDense(d.rows)
 	out := GetDenseWorkspace(d.rows, 1)
@@ -290,7 +286,7 @@ func (d *Dense) T() Matrix {
 // Reshape returns a copy of the matrix. It panics if the dimensions are not compatible.
 func (d *Dense) Reshape(r, c int) Matrix {
 	if d.Size() != r*c {
-		panic("mat: incompatible sizes.")
+		panic(&ShapeError{Op: "Reshape", Shapes: [][]int{{d.rows, d.cols}, {r, c}}})
 	}
 	return NewDense(r, c, d.data)
 }
@@ -298,7 +294,7 @@ func (d *Dense) Reshape(r, c int) Matrix {
 // ApplyWithAlpha executes the unary function fn, taking additional parameters alpha.
 func (d *Dense) ApplyWithAlpha(fn func(i, j int, v float64, alpha ...float64) float64, a Matrix, alpha ...float64) {
 	if !SameDims(d, a) {
-		panic("mat: incompatible matrix dimensions.")
+		panic(NewShapeError("ApplyWithAlpha", d, a))
 	}
 	for i := 0; i < d.rows; i++ {
 		for j := 0; j < d.cols; j++ {
@@ -310,7 +306,7 @@ func (d *Dense) ApplyWithAlpha(fn func(i, j int, v float64, alpha ...float64) fl
 // Apply execute the unary function fn.
 func (d *Dense) Apply(fn func(i, j int, v float64) float64, a Matrix) {
 	if !SameDims(d, a) {
-		panic("mat: incompatible matrix dimensions.")
+		panic(NewShapeError("Apply", d, a))
 	}
 	dData := d.data
 	r := 0
@@ -377,7 +373,7 @@ func (d *Dense) ProdScalarInPlace(n float64) Matrix {
 
 // ProdMatrixScalarInPlace multiply a matrix with a float, storing the result in the receiver.
 func (d *Dense) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
-	f64.ScalUnitaryTo(d.data, n, m.(*Dense).data)
+	f64.ScalUnitaryTo(d.data, n, asDense(m).data)
 	return d
 }
 
@@ -393,9 +389,9 @@ func (d *Dense) Add(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("Add", d, other))
 	}
-	b := other.(*Dense)
+	b := asDense(other)
 	out := d.ZerosLike().(*Dense)
 	f64.AxpyUnitaryTo(out.data, 1.0, b.data, d.data)
 	return out
@@ -406,9 +402,9 @@ func (d *Dense) AddInPlace(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("AddInPlace", d, other))
 	}
-	b := other.(*Dense)
+	b := asDense(other)
 	f64.AxpyUnitary(1.0, b.data, d.data)
 	return d
 }
@@ -418,10 +414,10 @@ func (d *Dense) Sub(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("Sub", d, other))
 	}
 	out := d.ZerosLike().(*Dense)
-	b := other.(*Dense)
+	b := asDense(other)
 	f64.AxpyUnitaryTo(out.data, -1.0, b.data, d.data)
 	return out
 }
@@ -431,7 +427,7 @@ func (d *Dense) SubInPlace(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("SubInPlace", d, other))
 	}
 	switch other := other.(type) {
 	case *Dense:
@@ -440,6 +436,8 @@ func (d *Dense) SubInPlace(other Matrix) Matrix {
 		other.DoNonZero(func(i, j int, k float64) {
 			d.Set(i, j, d.At(i, j)-k)
 		})
+	default:
+		f64.AxpyUnitary(-1.0, asDense(other).data, d.data)
 	}
 	return d
 }
@@ -449,11 +447,11 @@ func (d *Dense) Prod(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("Prod", d, other))
 	}
 
 	out := GetDenseWorkspace(d.Dims())
-	b := other.(*Dense)
+	b := asDense(other)
 
 	// Avoid bounds checks in loop
 	dData := d.data
@@ -476,9 +474,9 @@ func (d *Dense) ProdInPlace(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("ProdInPlace", d, other))
 	}
-	b := other.(*Dense)
+	b := asDense(other)
 	bData := b.data
 	dData := d.data
 	for i, val := range bData {
@@ -492,10 +490,10 @@ func (d *Dense) Div(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("Div", d, other))
 	}
 	out := d.ZerosLike().(*Dense)
-	f64.DivTo(out.data, d.data, other.(*Dense).data)
+	f64.DivTo(out.data, d.data, asDense(other).data)
 	return out
 }
 
@@ -504,9 +502,9 @@ func (d *Dense) DivInPlace(other Matrix) Matrix {
 	if !(SameDims(d, other) ||
 		(other.Columns() == 1 && other.Rows() == d.Rows()) ||
 		(other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("DivInPlace", d, other))
 	}
-	b := other.(*Dense)
+	b := asDense(other)
 	for i, val := range b.data {
 		d.data[i] *= 1.0 / val
 	}
@@ -517,7 +515,7 @@ func (d *Dense) DivInPlace(other Matrix) Matrix {
 // if A is an r x c Matrix, and B is j X k, c = j the resulting Matrix C will be r x k
 func (d *Dense) Mul(other Matrix) Matrix {
 	if d.Columns() != other.Rows() {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("Mul", d, other))
 	}
 	out := GetEmptyDenseWorkspace(d.Rows(), other.Columns())
 
@@ -580,6 +578,9 @@ func (d *Dense) Mul(other Matrix) Matrix {
 				out.Set(i, j, out.At(i, j)+d.At(i, k)*v)
 			}
 		})
+	default:
+		ReleaseDense(out)
+		return d.Mul(asDense(other))
 	}
 	return out
 }
@@ -588,12 +589,11 @@ func (d *Dense) Mul(other Matrix) Matrix {
 // if A is an r x c Matrix, and B is j x k, r = j the resulting Matrix C will be c x k
 func (d *Dense) MulT(other Matrix) Matrix {
 	if d.Rows() != other.Rows() {
-		panic("mat: matrices with not compatible size")
+		panic(NewShapeError("MulT", d, other))
 	}
-	out := GetEmptyDenseWorkspace(d.Columns(), other.Columns())
-
 	switch b := other.(type) {
 	case *Dense:
+		out := GetEmptyDenseWorkspace(d.Columns(), other.Columns())
 		if out.cols == 1 {
 			f64.GemvT(
 				uintptr(d.rows), // m
@@ -608,19 +608,19 @@ func (d *Dense) MulT(other Matrix) Matrix {
 				1.0,             // incY
 			)
 		} else {
-			panic("mat: matrices with not compatible size")
+			panic(NewShapeError("MulT", d, other))
 		}
 		return out
 	case *Sparse:
-		panic("mat: matrices not compatible")
+		panic(NewShapeError("MulT", d, other))
 	}
-	return out
+	return d.MulT(asDense(other))
 }
 
 // DotUnitary returns the dot product of two vectors.
 func (d *Dense) DotUnitary(other Matrix) float64 {
 	if d.Size() != other.Size() {
-		panic("mat: incompatible sizes.")
+		panic(NewShapeError("DotUnitary", d, other))
 	}
 	return f64.DotUnitary(d.data, other.Data())
 }
@@ -734,7 +734,7 @@ func (d *Dense) Norm(pow float64) float64 {
 // Maximum returns a new matrix containing the element-wise maxima.
 func (d *Dense) Maximum(other Matrix) *Dense {
 	if d.Columns() != other.Columns() && d.Rows() != other.Rows() {
-		panic("mat: matrix with not compatible size")
+		panic(NewShapeError("Maximum", d, other))
 	}
 	out := NewEmptyDense(d.rows, d.cols)
 	for i := 0; i < d.rows; i++ {
@@ -754,7 +754,7 @@ func (d *Dense) Maximum(other Matrix) *Dense {
 // Augment places the identity matrix at the end of the original matrix
 func (d *Dense) Augment() Matrix {
 	if d.Columns() != d.Rows() {
-		panic("mat: matrix must be square")
+		panic(NewShapeError("Augment", d))
 	}
 	out := NewEmptyDense(d.rows, d.rows+d.cols)
 	for i := 0; i < d.rows; i++ {
@@ -769,10 +769,10 @@ func (d *Dense) Augment() Matrix {
 // SwapInPlace swaps two rows of the matrix in place
 func (d Dense) SwapInPlace(r1, r2 int) {
 	if d.IsVector() {
-		panic("mat: input must be a matrix")
+		panic(NewShapeError("SwapInPlace", &d))
 	}
 	if r1 >= d.rows || r2 >= d.rows {
-		panic("mat: index out of range")
+		panic(&IndexError{Index: []int{r1, r2}, Shape: []int{d.rows, d.cols}})
 	}
 
 	for j := 0; j < d.cols; j++ {
@@ -785,7 +785,7 @@ func (d Dense) SwapInPlace(r1, r2 int) {
 // Considerate square sub-matrix from element (offset, offset).
 func (d *Dense) Pivoting(row int) (Matrix, bool, []int) {
 	if d.Columns() != d.Rows() {
-		panic("mat: matrix must be square")
+		panic(NewShapeError("Pivoting", d))
 	}
 	pv := make([]int, d.cols)
 	positions := make([]int, 2)
@@ -827,7 +827,7 @@ func I(size int) *Dense {
 // LU performs lower–upper (LU) decomposition of a square matrix D such as PLU = D, L is lower diagonal and U is upper diagonal, p are pivots.
 func (d *Dense) LU() (l, u, p *Dense) {
 	if d.Columns() != d.Rows() {
-		panic("mat: matrix must be square")
+		panic(NewShapeError("LU", d))
 	}
 	u = d.Clone().(*Dense)
 	p = I(d.cols)
@@ -855,7 +855,7 @@ func (d *Dense) LU() (l, u, p *Dense) {
 // Inverse returns the inverse of the matrix.
 func (d Dense) Inverse() Matrix {
 	if d.Columns() != d.Rows() {
-		panic("mat: matrix must be square")
+		panic(NewShapeError("Inverse", &d))
 	}
 	out := NewEmptyDense(d.cols, d.cols)
 	s := NewEmptyDense(d.cols, d.cols)
@@ -881,6 +881,14 @@ func (d Dense) Inverse() Matrix {
 	return out
 }
 
+// asDense returns the matrix itself if it is a Dense, otherwise a new Dense copying its values.
+func asDense(m Matrix) *Dense {
+	if m, ok := m.(*Dense); ok {
+		return m
+	}
+	return NewDense(m.Rows(), m.Columns(), m.Data())
+}
+
 // String returns the string representation of the data.
 func (d *Dense) String() string {
 	return fmt.Sprintf("%v", d.data)
@@ -993,3 +1001,49 @@ func maxInt(a, b int) int {
 	}
 	return b
 }
+
+// AddMulInPlace adds the matrix product a·b to the receiver (d += a·b) and returns it.
+// If a and b are *Dense, the product is accumulated directly into the receiver, without allocating it.
+func (d *Dense) AddMulInPlace(a, b Matrix) *Dense {
+	if a.Columns() != b.Rows() || d.rows != a.Rows() || d.cols != b.Columns() {
+		panic(NewShapeError("AddMulInPlace", d, a, b))
+	}
+	ad, ok1 := a.(*Dense)
+	bd, ok2 := b.(*Dense)
+	if !(ok1 && ok2) {
+		p := a.Mul(b)
+		defer ReleaseMatrix(p)
+		d.AddInPlace(p)
+		return d
+	}
+	if d.cols == 1 {
+		f64.GemvN(
+			uintptr(ad.rows), // m
+			uintptr(ad.cols), // n
+			1.0,              // alpha
+			ad.data,          // a
+			uintptr(ad.cols), // lda
+			bd.data,          // x
+			1.0,              // incX
+			1.0,              // beta
+			d.data,           // y
+			1.0,              // incY
+		)
+	} else {
+		f64.DgemmSerial(
+			false,
+			false,
+			ad.rows, // m
+			bd.cols, // n
+			ad.cols, // k
+			ad.data, // a
+			ad.cols, // lda
+			bd.data, // b
+			bd.cols, // ldb
+			d.data,  // c
+			d.cols,  // ldc
+			1.0,     // alpha
+		)
+	}
+	return d
+}
@@ -0,0 +1,521 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/pkg/mat/internal/asm/f32"
+)
+
+var _ Matrix = &Dense32{}
+
+// Dense32 is a dense matrix storing its values as float32.
+// It satisfies the Matrix interface, converting the values from and to float64 at the boundaries, so that it can
+// be used in place of a Dense to halve the memory needed by large models (e.g. embeddings and transformers).
+// The operations between two Dense32 are performed in single precision.
+type Dense32 struct {
+	rows     int
+	cols     int
+	size     int // rows*cols
+	data     []float32
+	fromPool bool
+}
+
+// NewDense32 returns a new rows x cols float32 matrix populated with a copy of the elements.
+// The elements cannot be nil, panic otherwise. Use NewEmptyDense32 to initialize an empty matrix.
+func NewDense32(rows, cols int, elements []float64) *Dense32 {
+	if elements == nil {
+		panic("mat: elements cannot be nil. Use NewEmptyDense32() instead.")
+	}
+	if len(elements) != rows*cols {
+		panic(&ShapeError{Op: "NewDense32", Shapes: [][]int{{rows, cols}, {len(elements)}}})
+	}
+	d := GetDense32Workspace(rows, cols)
+	for i, v := range elements {
+		d.data[i] = float32(v)
+	}
+	return d
+}
+
+// NewVecDense32 returns a new column vector populated with a copy of the elements.
+// The elements cannot be nil, panic otherwise. Use NewEmptyVecDense32 to initialize an empty matrix.
+func NewVecDense32(elements []float64) *Dense32 {
+	if elements == nil {
+		panic("mat: elements cannot be nil. Use NewEmptyVecDense32() instead.")
+	}
+	return NewDense32(len(elements), 1, elements)
+}
+
+// NewEmptyVecDense32 returns a new vector of the given size, initialized to zeros.
+func NewEmptyVecDense32(size int) *Dense32 {
+	return GetEmptyDense32Workspace(size, 1)
+}
+
+// NewEmptyDense32 returns a new rows x cols matrix initialized to zeros.
+func NewEmptyDense32(rows, cols int) *Dense32 {
+	return GetEmptyDense32Workspace(rows, cols)
+}
+
+// ToDense32 returns a new float32 matrix copying the values of the receiver.
+func (d *Dense) ToDense32() *Dense32 {
+	return NewDense32(d.rows, d.cols, d.data)
+}
+
+// ToDense returns a new float64 matrix copying the values of the receiver.
+func (d *Dense32) ToDense() *Dense {
+	out := GetDenseWorkspace(d.rows, d.cols)
+	for i, v := range d.data {
+		out.data[i] = float64(v)
+	}
+	return out
+}
+
+// Data32 returns the underlying float32 data.
+func (d *Dense32) Data32() []float32 {
+	return d.data
+}
+
+// SetData sets the data, converting them to float32.
+func (d *Dense32) SetData(data []float64) {
+	if len(data) != d.size {
+		panic(&ShapeError{Op: "SetData", Shapes: [][]int{{d.rows, d.cols}, {len(data)}}})
+	}
+	for i, v := range data {
+		d.data[i] = float32(v)
+	}
+}
+
+// ZerosLike returns a new Dense32 with of the same dimensions of the receiver, initialized with zeros.
+func (d *Dense32) ZerosLike() Matrix {
+	return NewEmptyDense32(d.rows, d.cols)
+}
+
+// OnesLike returns a new Dense32 with of the same dimensions of the receiver, initialized to ones.
+func (d *Dense32) OnesLike() Matrix {
+	out := GetDense32Workspace(d.Dims())
+	for i := range out.data {
+		out.data[i] = 1.0
+	}
+	return out
+}
+
+// Clone returns a new matrix copying the values of the receiver.
+func (d *Dense32) Clone() Matrix {
+	out := GetDense32Workspace(d.rows, d.cols)
+	copy(out.data, d.data)
+	return out
+}
+
+// Copy copies the data to the receiver.
+func (d *Dense32) Copy(other Matrix) {
+	if !SameDims(d, other) {
+		panic(NewShapeError("Copy", d, other))
+	}
+	copy(d.data, data32(other))
+}
+
+// Zeros set all the values to zeros.
+func (d *Dense32) Zeros() {
+	zero32(d.data)
+}
+
+// Dims returns the number of rows and columns.
+func (d *Dense32) Dims() (r, c int) {
+	return d.rows, d.cols
+}
+
+// Rows returns the number of rows.
+func (d *Dense32) Rows() int {
+	return d.rows
+}
+
+// Columns returns the number of columns.
+func (d *Dense32) Columns() int {
+	return d.cols
+}
+
+// Size returns the size of the matrix (rows * cols).
+func (d *Dense32) Size() int {
+	return d.size
+}
+
+// LastIndex returns the last index.
+func (d *Dense32) LastIndex() int {
+	return d.size - 1
+}
+
+// Data returns a float64 copy of the underlying data, which must be considered read-only (see Matrix.Data).
+// Differently from Dense, modifying the returned slice doesn't affect the matrix; use SetData() or Data32() instead.
+func (d *Dense32) Data() []float64 {
+	out := make([]float64, d.size)
+	for i, v := range d.data {
+		out[i] = float64(v)
+	}
+	return out
+}
+
+// IsVector returns whether the matrix has one row or one column, or not.
+func (d *Dense32) IsVector() bool {
+	return d.rows == 1 || d.cols == 1
+}
+
+// IsScalar returns whether the matrix contains a scalar, or not.
+func (d *Dense32) IsScalar() bool {
+	return d.size == 1
+}
+
+// Scalar returns the scalar. It panics if the matrix contains more elements.
+func (d *Dense32) Scalar() float64 {
+	if !d.IsScalar() {
+		panic(&ShapeError{Op: "Scalar", Shapes: [][]int{{d.rows, d.cols}, {1, 1}}})
+	}
+	return float64(d.data[0])
+}
+
+// Set sets the value v at row i and column j.
+func (d *Dense32) Set(i int, j int, v float64) {
+	if i >= d.rows {
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
+	}
+	if j >= d.cols {
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
+	}
+	d.data[i*d.cols+j] = float32(v)
+}
+
+// At returns the value at row i and column j.
+func (d *Dense32) At(i int, j int) float64 {
+	if i >= d.rows {
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
+	}
+	if j >= d.cols {
+		panic(&IndexError{Index: []int{i, j}, Shape: []int{d.rows, d.cols}})
+	}
+	return float64(d.data[i*d.cols+j])
+}
+
+// SetVec sets the value v at position i of a vector.
+// It panics if not IsVector().
+func (d *Dense32) SetVec(i int, v float64) {
+	if !(d.IsVector()) {
+		panic(NewShapeError("SetVec", d))
+	}
+	if i >= d.size {
+		panic(&IndexError{Index: []int{i}, Shape: []int{d.rows, d.cols}})
+	}
+	d.data[i] = float32(v)
+}
+
+// AtVec returns the value at position i of a vector
+// It panics if not IsVector().
+func (d *Dense32) AtVec(i int) float64 {
+	if !(d.IsVector()) {
+		panic(NewShapeError("AtVec", d))
+	}
+	if i >= d.size {
+		panic(&IndexError{Index: []int{i}, Shape: []int{d.rows, d.cols}})
+	}
+	return float64(d.data[i])
+}
+
+// T returns the transpose of the matrix.
+func (d *Dense32) T() Matrix {
+	m := GetDense32Workspace(d.cols, d.rows)
+	for i := 0; i < d.rows; i++ {
+		for j := 0; j < d.cols; j++ {
+			m.data[j*d.rows+i] = d.data[i*d.cols+j]
+		}
+	}
+	return m
+}
+
+// Reshape returns a copy of the matrix. It panics if the dimensions are not compatible.
+func (d *Dense32) Reshape(r, c int) Matrix {
+	if d.Size() != r*c {
+		panic(&ShapeError{Op: "Reshape", Shapes: [][]int{{d.rows, d.cols}, {r, c}}})
+	}
+	out := GetDense32Workspace(r, c)
+	copy(out.data, d.data)
+	return out
+}
+
+// ApplyWithAlpha executes the unary function fn, taking additional parameters alpha.
+func (d *Dense32) ApplyWithAlpha(fn func(i, j int, v float64, alpha ...float64) float64, a Matrix, alpha ...float64) {
+	if !SameDims(d, a) {
+		panic(NewShapeError("ApplyWithAlpha", d, a))
+	}
+	for i := 0; i < d.rows; i++ {
+		for j := 0; j < d.cols; j++ {
+			d.data[i*d.cols+j] = float32(fn(i, j, a.At(i, j), alpha...))
+		}
+	}
+}
+
+// Apply execute the unary function fn.
+func (d *Dense32) Apply(fn func(i, j int, v float64) float64, a Matrix) {
+	if !SameDims(d, a) {
+		panic(NewShapeError("Apply", d, a))
+	}
+	for i := 0; i < d.rows; i++ {
+		for j := 0; j < d.cols; j++ {
+			d.data[i*d.cols+j] = float32(fn(i, j, a.At(i, j)))
+		}
+	}
+}
+
+// AddScalar performs an addition between the Matrix and a float.
+func (d *Dense32) AddScalar(n float64) Matrix {
+	return d.Clone().AddScalarInPlace(n)
+}
+
+// SubScalar performs a subtraction between the Matrix and a float.
+func (d *Dense32) SubScalar(n float64) Matrix {
+	return d.Clone().AddScalarInPlace(-n)
+}
+
+// AddScalarInPlace adds the scalar to the receiver.
+func (d *Dense32) AddScalarInPlace(n float64) Matrix {
+	v := float32(n)
+	for i := range d.data {
+		d.data[i] += v
+	}
+	return d
+}
+
+// SubScalarInPlace subtracts the scalar to the receiver.
+func (d *Dense32) SubScalarInPlace(n float64) Matrix {
+	return d.AddScalarInPlace(-n)
+}
+
+// ProdScalarInPlace multiply a float with the receiver in place.
+func (d *Dense32) ProdScalarInPlace(n float64) Matrix {
+	f32.ScalUnitary(float32(n), d.data)
+	return d
+}
+
+// ProdMatrixScalarInPlace multiply a matrix with a float, storing the result in the receiver.
+func (d *Dense32) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
+	f32.ScalUnitaryTo(d.data, float32(n), data32(m))
+	return d
+}
+
+// ProdScalar returns the multiplication of the float with the receiver.
+func (d *Dense32) ProdScalar(n float64) Matrix {
+	out := GetDense32Workspace(d.Dims())
+	f32.ScalUnitaryTo(out.data, float32(n), d.data)
+	return out
+}
+
+// Add returns the addition with a matrix with the receiver.
+func (d *Dense32) Add(other Matrix) Matrix {
+	d.checkCompatible(other)
+	out := GetDense32Workspace(d.Dims())
+	f32.AxpyUnitaryTo(out.data, 1.0, data32(other), d.data)
+	return out
+}
+
+// AddInPlace performs the addition with the other matrix in place.
+func (d *Dense32) AddInPlace(other Matrix) Matrix {
+	d.checkCompatible(other)
+	f32.AxpyUnitary(1.0, data32(other), d.data)
+	return d
+}
+
+// Sub returns the subtraction with a matrix with the receiver.
+func (d *Dense32) Sub(other Matrix) Matrix {
+	d.checkCompatible(other)
+	out := GetDense32Workspace(d.Dims())
+	f32.AxpyUnitaryTo(out.data, -1.0, data32(other), d.data)
+	return out
+}
+
+// SubInPlace performs the subtraction with the other matrix in place.
+func (d *Dense32) SubInPlace(other Matrix) Matrix {
+	d.checkCompatible(other)
+	f32.AxpyUnitary(-1.0, data32(other), d.data)
+	return d
+}
+
+// Prod performs the element-wise product with the receiver.
+func (d *Dense32) Prod(other Matrix) Matrix {
+	d.checkCompatible(other)
+	out := GetDense32Workspace(d.Dims())
+	b := data32(other)
+	for i, v := range d.data {
+		out.data[i] = v * b[i]
+	}
+	return out
+}
+
+// ProdInPlace performs the element-wise product with the receiver in place.
+func (d *Dense32) ProdInPlace(other Matrix) Matrix {
+	d.checkCompatible(other)
+	for i, v := range data32(other) {
+		d.data[i] *= v
+	}
+	return d
+}
+
+// Div returns the result of the element-wise division.
+func (d *Dense32) Div(other Matrix) Matrix {
+	d.checkCompatible(other)
+	out := GetDense32Workspace(d.Dims())
+	b := data32(other)
+	for i, v := range d.data {
+		out.data[i] = v / b[i]
+	}
+	return out
+}
+
+// DivInPlace performs the result of the element-wise division in place.
+func (d *Dense32) DivInPlace(other Matrix) Matrix {
+	d.checkCompatible(other)
+	for i, v := range data32(other) {
+		d.data[i] /= v
+	}
+	return d
+}
+
+// Mul performs the multiplication row by column. AB = C
+// if A is an r x c Matrix, and B is j X k, c = j the resulting Matrix C will be r x k
+func (d *Dense32) Mul(other Matrix) Matrix {
+	if d.Columns() != other.Rows() {
+		panic(NewShapeError("Mul", d, other))
+	}
+	b := data32(other)
+	bCols := other.Columns()
+	out := GetEmptyDense32Workspace(d.rows, bCols)
+	if bCols == 1 {
+		for i := 0; i < d.rows; i++ {
+			out.data[i] = f32.DotUnitary(d.data[i*d.cols:(i+1)*d.cols], b)
+		}
+		return out
+	}
+	for i := 0; i < d.rows; i++ {
+		outRow := out.data[i*bCols : (i+1)*bCols]
+		for k, v := range d.data[i*d.cols : (i+1)*d.cols] {
+			f32.AxpyUnitary(v, b[k*bCols:(k+1)*bCols], outRow)
+		}
+	}
+	return out
+}
+
+// DotUnitary returns the dot product of two vectors.
+func (d *Dense32) DotUnitary(other Matrix) float64 {
+	if d.Size() != other.Size() {
+		panic(NewShapeError("DotUnitary", d, other))
+	}
+	return float64(f32.DotUnitary(d.data, data32(other)))
+}
+
+// ClipInPlace performs the clip in place.
+// If element k of Matrix if k > max, k = max and if k < min, k = min
+func (d *Dense32) ClipInPlace(min, max float64) Matrix {
+	lo, hi := float32(min), float32(max)
+	for i, v := range d.data {
+		if v < lo {
+			d.data[i] = lo
+		} else if v > hi {
+			d.data[i] = hi
+		}
+	}
+	return d
+}
+
+// Abs returns a new matrix applying the abs function to all elements.
+func (d *Dense32) Abs() Matrix {
+	out := GetDense32Workspace(d.Dims())
+	for i, v := range d.data {
+		out.data[i] = float32(math.Abs(float64(v)))
+	}
+	return out
+}
+
+// Pow returns a new matrix applying the power v (applying the pow function) to all elements.
+func (d *Dense32) Pow(power float64) Matrix {
+	out := GetDense32Workspace(d.Dims())
+	for i, v := range d.data {
+		out.data[i] = float32(math.Pow(float64(v), power))
+	}
+	return out
+}
+
+// Sqrt returns a new matrix applying the sqrt function to all elements.
+func (d *Dense32) Sqrt() Matrix {
+	out := GetDense32Workspace(d.Dims())
+	for i, v := range d.data {
+		out.data[i] = float32(math.Sqrt(float64(v)))
+	}
+	return out
+}
+
+// Sum returns the sum of all values of the matrix.
+// The sum is accumulated in double precision.
+func (d *Dense32) Sum() float64 {
+	sum := 0.0
+	for _, v := range d.data {
+		sum += float64(v)
+	}
+	return sum
+}
+
+// Max returns the max value of the matrix.
+func (d *Dense32) Max() float64 {
+	max := math.Inf(-1)
+	for _, v := range d.data {
+		if float64(v) > max {
+			max = float64(v)
+		}
+	}
+	return max
+}
+
+// Min returns the min value of the matrix.
+func (d *Dense32) Min() float64 {
+	min := math.Inf(1)
+	for _, v := range d.data {
+		if float64(v) < min {
+			min = float64(v)
+		}
+	}
+	return min
+}
+
+// Norm returns the vector norm.  Use pow = 2.0 for Euclidean.
+func (d *Dense32) Norm(pow float64) float64 {
+	s := 0.0
+	for _, x := range d.data {
+		s += math.Pow(float64(x), pow)
+	}
+	return math.Pow(s, 1/pow)
+}
+
+// String returns the string representation of the data.
+func (d *Dense32) String() string {
+	return fmt.Sprintf("%v", d.data)
+}
+
+// checkCompatible panics if the element-wise operations between the receiver and the other matrix are not allowed.
+func (d *Dense32) checkCompatible(other Matrix) {
+	if !(SameDims(d, other) || (other.IsVector() && d.IsVector() && other.Size() == d.Size())) {
+		panic(NewShapeError("element-wise operation", d, other))
+	}
+}
+
+// data32 returns the values of the matrix as float32.
+// If m is a Dense32 the underlying data are returned, otherwise a converted copy.
+func data32(m Matrix) []float32 {
+	if m, ok := m.(*Dense32); ok {
+		return m.data
+	}
+	data := m.Data()
+	out := make([]float32, len(data))
+	for i, v := range data {
+		out[i] = float32(v)
+	}
+	return out
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestDense32_Add(t *testing.T) {
+	a := NewVecDense32([]float64{0.1, 0.2, 0.3, 0.0})
+	b := NewVecDense32([]float64{0.4, 0.3, 0.5, 0.7})
+	c := a.Add(b)
+
+	if _, ok := c.(*Dense32); !ok {
+		t.Error("The result is not a Dense32")
+	}
+	if !floats.EqualApprox(c.Data(), []float64{0.5, 0.5, 0.8, 0.7}, 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+}
+
+func TestDense32_AddInPlaceMixed(t *testing.T) {
+	a := NewVecDense32([]float64{0.1, 0.2, 0.3, 0.0})
+	a.AddInPlace(NewVecDense([]float64{0.4, 0.3, 0.5, 0.7}))
+
+	if !floats.EqualApprox(a.Data(), []float64{0.5, 0.5, 0.8, 0.7}, 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+
+	b := NewVecDense([]float64{0.4, 0.3, 0.5, 0.7})
+	b.AddInPlace(NewVecDense32([]float64{0.1, 0.2, 0.3, 0.0}))
+
+	if !floats.EqualApprox(b.Data(), []float64{0.5, 0.5, 0.8, 0.7}, 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+}
+
+func TestDense32_Mul(t *testing.T) {
+	a := NewDense32(2, 3, []float64{
+		0.1, 0.2, 0.3,
+		0.4, 0.5, -0.6,
+	})
+	v := a.Mul(NewVecDense32([]float64{-0.8, -0.9, -0.9}))
+
+	if !floats.EqualApprox(v.Data(), []float64{-0.53, -0.23}, 1.0e-6) {
+		t.Error("The matrix-vector product doesn't match the expected values")
+	}
+
+	m := a.Mul(NewDense32(3, 2, []float64{
+		1.0, 0.5,
+		-1.0, 0.2,
+		0.0, 0.1,
+	}))
+
+	if r, c := m.Dims(); r != 2 || c != 2 {
+		t.Error("The dimensions don't match the expected values")
+	}
+	if !floats.EqualApprox(m.Data(), []float64{
+		-0.1, 0.12,
+		-0.1, 0.24,
+	}, 1.0e-6) {
+		t.Error("The matrix-matrix product doesn't match the expected values")
+	}
+}
+
+func TestDense32_T(t *testing.T) {
+	a := NewDense32(2, 3, []float64{
+		0.1, 0.2, 0.3,
+		0.4, 0.5, 0.6,
+	})
+	b := a.T()
+
+	if r, c := b.Dims(); r != 3 || c != 2 {
+		t.Error("The dimensions don't match the expected values")
+	}
+	if !floats.EqualApprox(b.Data(), []float64{
+		0.1, 0.4,
+		0.2, 0.5,
+		0.3, 0.6,
+	}, 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+}
+
+func TestDense32_MarshalBinary(t *testing.T) {
+	a := NewDense32(2, 2, []float64{0.1, 0.2, 0.3, 0.4})
+	var buf bytes.Buffer
+	if _, err := MarshalBinaryTo(a, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, _ := MarshalBinaryTo(NewDense(2, 2, a.Data()), &bytes.Buffer{}); buf.Len() != n-4*4 {
+		t.Error("The values of a Dense32 should be written as float32")
+	}
+
+	b := NewEmptyDense32(2, 2)
+	if _, err := UnmarshalBinaryFrom(b, bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if !floats.EqualApprox(b.Data(), []float64{0.1, 0.2, 0.3, 0.4}, 1.0e-6) {
+		t.Error("The Dense32 doesn't match the expected values")
+	}
+
+	c, _, err := NewUnmarshalBinaryFrom(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floats.EqualApprox(c.Data(), []float64{0.1, 0.2, 0.3, 0.4}, 1.0e-6) {
+		t.Error("The Dense doesn't match the expected values")
+	}
+}
+
+func TestDense32_UnmarshalFloat64(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := MarshalBinaryTo(NewDense(2, 2, []float64{0.1, 0.2, 0.3, 0.4}), &buf); err != nil {
+		t.Fatal(err)
+	}
+	b := NewEmptyDense32(2, 2)
+	if _, err := UnmarshalBinaryFrom(b, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !floats.EqualApprox(b.Data(), []float64{0.1, 0.2, 0.3, 0.4}, 1.0e-6) {
+		t.Error("The Dense32 doesn't match the expected values")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	a := NewVecDense([]float64{0.1, 0.2, 0.3})
+	b := Convert(a, Float32)
+	if DTypeOf(b) != Float32 {
+		t.Error("The data type doesn't match the expected value")
+	}
+	c := Convert(b, Float64)
+	if DTypeOf(c) != Float64 {
+		t.Error("The data type doesn't match the expected value")
+	}
+	if !floats.EqualApprox(c.Data(), a.Data(), 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+}
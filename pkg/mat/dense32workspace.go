@@ -0,0 +1,76 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sync"
+)
+
+// Each pool element i returns slices capped at 1<<i (see densePool).
+var dense32Pool [63]sync.Pool
+
+func init() {
+	for i := range dense32Pool {
+		length := 1 << uint(i)
+		dense32Pool[i].New = func() interface{} {
+			countWorkspaceMiss()
+			return &Dense32{
+				rows:     -1,
+				cols:     -1,
+				size:     -1,
+				data:     make([]float32, length),
+				fromPool: true,
+			}
+		}
+	}
+}
+
+// GetDense32Workspace returns a *Dense32 of size r×c and a data slice with a cap that is less than 2*r*c.
+// Warning, the values may not be at zero. If you need a ready-to-use matrix you can call GetEmptyDense32Workspace().
+func GetDense32Workspace(r, c int) *Dense32 {
+	countWorkspaceGet()
+	size := r * c
+	w := dense32Pool[bits(uint64(size))].Get().(*Dense32)
+	w.data = w.data[:size]
+	w.rows = r
+	w.cols = c
+	w.size = size
+	return w
+}
+
+// GetEmptyDense32Workspace returns a *Dense32 of size r×c and a data slice with a cap that is less than 2*r*c.
+// The returned matrix is ready-to-use (with all the values set to zeros).
+func GetEmptyDense32Workspace(r, c int) *Dense32 {
+	countWorkspaceGet()
+	size := r * c
+	w := dense32Pool[bits(uint64(size))].Get().(*Dense32)
+	isNew := w.size == -1 // only a new matrix has size -1
+	w.data = w.data[:size]
+	w.rows = r
+	w.cols = c
+	w.size = size
+	if !isNew {
+		zero32(w.data)
+	}
+	return w
+}
+
+// ReleaseDense32 replaces a used *Dense32 into the appropriate size
+// workspace pool. ReleaseDense32 must not be called with a matrix
+// where references to the underlying data slice have been kept.
+func ReleaseDense32(w *Dense32) {
+	if !w.fromPool {
+		panic("mat: only matrices originated from the workspace can return to it")
+	}
+	countWorkspaceRelease()
+	dense32Pool[bits(uint64(cap(w.data)))].Put(w)
+}
+
+// zero32 zeros the given slice's elements.
+func zero32(f []float32) {
+	for i := range f {
+		f[i] = 0.0
+	}
+}
@@ -193,6 +193,36 @@ func TestDense_MulMatrixVector(t *testing.T) {
 	}
 }
 
+func TestDense_AddMulInPlace(t *testing.T) {
+	a := NewDense(3, 4, []float64{
+		0.1, 0.2, 0.3, 0.0,
+		0.4, 0.5, -0.6, 0.7,
+		-0.5, 0.8, -0.8, -0.1,
+	})
+	c := NewVecDense([]float64{1.0, 2.0, 3.0})
+	c.AddMulInPlace(a, NewVecDense([]float64{-0.8, -0.9, -0.9, 1.0}))
+
+	if !floats.EqualApprox(c.Data(), []float64{0.47, 2.47, 3.3}, 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+
+	d := NewInitDense(3, 2, 1.0)
+	d.AddMulInPlace(a, NewDense(4, 2, []float64{
+		-0.8, 0.1,
+		-0.9, 0.2,
+		-0.9, 0.3,
+		1.0, 0.4,
+	}))
+
+	if !floats.EqualApprox(d.Data(), []float64{
+		0.47, 1.14,
+		1.47, 1.24,
+		1.3, 0.83,
+	}, 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+}
+
 func TestDense_Pow(t *testing.T) {
 	a := NewVecDense([]float64{0.1, 0.2, 0.3, 0.0})
 	b := a.Pow(3.0)
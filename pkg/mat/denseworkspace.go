@@ -15,6 +15,7 @@ package mat
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
 // Each pool element i returns slices capped at 1<<i.
@@ -28,6 +29,7 @@ func init() {
 		length := 1 << uint(i)
 		//densePool[i] = utils.NewPool(10000) // enable if you're using utils.Pool
 		densePool[i].New = func() interface{} {
+			countWorkspaceMiss()
 			// Return a pointer type, since it can be put into
 			// the return interface value without an allocation.
 			return &Dense{
@@ -45,6 +47,7 @@ func init() {
 // GetDenseWorkspace returns a *Dense of size r×c and a data slice with a cap that is less than 2*r*c.
 // Warning, the values may not be at zero. If you need a ready-to-use matrix you can call GetEmptyDenseWorkspace().
 func GetDenseWorkspace(r, c int) *Dense {
+	countWorkspaceGet()
 	size := r * c
 	w := densePool[bits(uint64(size))].Get().(*Dense)
 	w.data = w.data[:size]
@@ -57,6 +60,7 @@ func GetDenseWorkspace(r, c int) *Dense {
 // GetDenseWorkspace returns a *Dense of size r×c and a data slice with a cap that is less than 2*r*c.
 // The returned matrix is ready-to-use (with all the values set to zeros).
 func GetEmptyDenseWorkspace(r, c int) *Dense {
+	countWorkspaceGet()
 	size := r * c
 	i := bits(uint64(size))
 	w := densePool[i].Get().(*Dense)
@@ -78,9 +82,91 @@ func ReleaseDense(w *Dense) {
 	if !w.fromPool {
 		panic("mat: only matrices originated from the workspace can return to it")
 	}
+	countWorkspaceRelease()
 	densePool[bits(uint64(cap(w.data)))].Put(w)
 }
 
+// ReleaseMatrix returns the matrix to the workspace pool it originated from, whatever its type.
+// Matrices not supported by a workspace (e.g. Sparse) are ignored.
+func ReleaseMatrix(m Matrix) {
+	switch m := m.(type) {
+	case *Dense:
+		ReleaseDense(m)
+	case *Dense32:
+		ReleaseDense32(m)
+	}
+}
+
+// WorkspaceStats contains the usage statistics of the workspace pools of the dense matrices, Dense and Dense32.
+type WorkspaceStats struct {
+	// Gets is the number of matrices taken from the workspace (see GetDenseWorkspace)
+	Gets int64
+	// Misses is the number of matrices that have been allocated because the workspace had none to reuse
+	Misses int64
+	// Releases is the number of matrices returned to the workspace (see ReleaseMatrix)
+	Releases int64
+}
+
+// Hits returns the number of matrices that have been reused.
+func (s WorkspaceStats) Hits() int64 {
+	return s.Gets - s.Misses
+}
+
+// Sub returns the statistics collected since the previous ones.
+func (s WorkspaceStats) Sub(prev WorkspaceStats) WorkspaceStats {
+	return WorkspaceStats{
+		Gets:     s.Gets - prev.Gets,
+		Misses:   s.Misses - prev.Misses,
+		Releases: s.Releases - prev.Releases,
+	}
+}
+
+var (
+	workspaceStatsEnabled int32 // the number of requests enabling the statistics
+	workspaceStats        WorkspaceStats
+)
+
+// EnableWorkspaceStats enables or disables the collection of the statistics of the workspace pool
+// (see ReadWorkspaceStats). They are disabled by default, to avoid the synchronization costs.
+// The requests are counted, so that independent users (e.g. several profilers) can enable the statistics at the
+// same time: each call enabling them must be paired with a call disabling them, which are collected until the
+// last one is disabled.
+func EnableWorkspaceStats(enable bool) {
+	if enable {
+		atomic.AddInt32(&workspaceStatsEnabled, 1)
+	} else if atomic.AddInt32(&workspaceStatsEnabled, -1) < 0 {
+		atomic.AddInt32(&workspaceStatsEnabled, 1)
+		panic("mat: the workspace statistics are not enabled")
+	}
+}
+
+// ReadWorkspaceStats returns the statistics of the workspace pool collected while enabled.
+func ReadWorkspaceStats() WorkspaceStats {
+	return WorkspaceStats{
+		Gets:     atomic.LoadInt64(&workspaceStats.Gets),
+		Misses:   atomic.LoadInt64(&workspaceStats.Misses),
+		Releases: atomic.LoadInt64(&workspaceStats.Releases),
+	}
+}
+
+func countWorkspaceMiss() {
+	if atomic.LoadInt32(&workspaceStatsEnabled) != 0 {
+		atomic.AddInt64(&workspaceStats.Misses, 1)
+	}
+}
+
+func countWorkspaceRelease() {
+	if atomic.LoadInt32(&workspaceStatsEnabled) != 0 {
+		atomic.AddInt64(&workspaceStats.Releases, 1)
+	}
+}
+
+func countWorkspaceGet() {
+	if atomic.LoadInt32(&workspaceStatsEnabled) != 0 {
+		atomic.AddInt64(&workspaceStats.Gets, 1)
+	}
+}
+
 var tab64 = [64]byte{
 	0x3f, 0x00, 0x3a, 0x01, 0x3b, 0x2f, 0x35, 0x02,
 	0x3c, 0x27, 0x30, 0x1b, 0x36, 0x21, 0x2a, 0x03,
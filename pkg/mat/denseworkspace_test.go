@@ -73,3 +73,34 @@ func assertLenCap(t *testing.T, slice []float64, l, c int) {
 		t.Errorf("expected cap %d, actual %d", c, cap(slice))
 	}
 }
+
+func TestWorkspaceStats(t *testing.T) {
+	EnableWorkspaceStats(true)
+	defer EnableWorkspaceStats(false)
+	prev := ReadWorkspaceStats()
+
+	// the pool of this size is not used elsewhere, so the first matrix must be allocated
+	ReleaseDense(GetDenseWorkspace(1<<17, 1))
+	ReleaseDense(GetEmptyDenseWorkspace(1<<17, 1))
+
+	stats := ReadWorkspaceStats().Sub(prev)
+	if stats.Gets != 2 || stats.Releases != 2 || stats.Misses < 1 || stats.Hits()+stats.Misses != 2 {
+		t.Errorf("Unexpected statistics %+v", stats)
+	}
+}
+
+func TestWorkspaceStats_Dense32(t *testing.T) {
+	EnableWorkspaceStats(true)
+	EnableWorkspaceStats(true)
+	EnableWorkspaceStats(false) // still enabled by the first request
+	defer EnableWorkspaceStats(false)
+	prev := ReadWorkspaceStats()
+
+	ReleaseDense32(GetDense32Workspace(1<<17, 1))
+	ReleaseMatrix(GetEmptyDense32Workspace(1<<17, 1))
+
+	stats := ReadWorkspaceStats().Sub(prev)
+	if stats.Gets != 2 || stats.Releases != 2 || stats.Misses < 1 || stats.Hits()+stats.Misses != 2 {
+		t.Errorf("Unexpected statistics %+v", stats)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "fmt"
+
+// DType is the data type of the values stored in a matrix.
+type DType int
+
+const (
+	// Float64 is the data type of Dense.
+	Float64 DType = iota
+	// Float32 is the data type of Dense32.
+	Float32
+)
+
+// DTypeOf returns the data type of the matrix. Matrices other than Dense32 are considered Float64.
+func DTypeOf(m Matrix) DType {
+	if _, ok := m.(*Dense32); ok {
+		return Float32
+	}
+	return Float64
+}
+
+// Convert returns a new matrix copying the values of m, stored with the given data type.
+func Convert(m Matrix, t DType) Matrix {
+	switch t {
+	case Float64:
+		return NewDense(m.Rows(), m.Columns(), m.Data())
+	case Float32:
+		if m, ok := m.(*Dense32); ok {
+			return m.Clone()
+		}
+		return NewDense32(m.Rows(), m.Columns(), m.Data())
+	default:
+		panic(fmt.Errorf("mat: invalid data type %d", t))
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrShapeMismatch is matched (see errors.Is) by the errors of the operations among matrices or tensors
+	// with incompatible dimensions. Use errors.As with a *ShapeError to get the offending dimensions.
+	ErrShapeMismatch = errors.New("mat: incompatible dimensions")
+	// ErrIndexOutOfRange is matched (see errors.Is) by the errors of the accesses out of the bounds of
+	// a matrix or a tensor. Use errors.As with a *IndexError to get the offending indices.
+	ErrIndexOutOfRange = errors.New("mat: index out of range")
+)
+
+// ShapeError reports an operation among matrices or tensors with incompatible dimensions.
+// The operations of the package panic with a *ShapeError, which can be recovered with Recover.
+type ShapeError struct {
+	// Op is the name of the operation (e.g. "Add").
+	Op string
+	// Shapes contains the dimensions of the operands, or the required dimensions for the operations
+	// with a single operand (e.g. "Reshape").
+	Shapes [][]int
+}
+
+func (e *ShapeError) Error() string {
+	shapes := make([]string, len(e.Shapes))
+	for i, shape := range e.Shapes {
+		shapes[i] = formatShape(shape)
+	}
+	return fmt.Sprintf("mat: %s: incompatible dimensions %s", e.Op, strings.Join(shapes, ", "))
+}
+
+// Is reports whether the target is ErrShapeMismatch.
+func (e *ShapeError) Is(target error) bool {
+	return target == ErrShapeMismatch
+}
+
+// IndexError reports an access out of the bounds of a matrix or a tensor.
+// The operations of the package panic with a *IndexError, which can be recovered with Recover.
+type IndexError struct {
+	// Index contains the offending indices.
+	Index []int
+	// Shape contains the dimensions of the matrix or of the tensor.
+	Shape []int
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("mat: index %v out of range for dimensions %s", e.Index, formatShape(e.Shape))
+}
+
+// Is reports whether the target is ErrIndexOutOfRange.
+func (e *IndexError) Is(target error) bool {
+	return target == ErrIndexOutOfRange
+}
+
+// Recover converts a panic raised with an error (e.g. a *ShapeError) into the error returned by the function
+// deferring it, making the function error-returning instead of terminating the process:
+//
+//	func safeMul(a, b mat.Matrix) (out mat.Matrix, err error) {
+//		defer mat.Recover(&err)
+//		return a.Mul(b), nil
+//	}
+//
+// The panics raised with other values, and the run-time errors (e.g. a nil pointer dereference), which reveal a
+// bug rather than an invalid input, are propagated.
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		if _, ok := r.(runtime.Error); ok {
+			panic(r)
+		}
+		if e, ok := r.(error); ok {
+			*err = e
+			return
+		}
+		panic(r)
+	}
+}
+
+// NewShapeError returns the error of an operation among the given matrices with incompatible dimensions.
+func NewShapeError(op string, ms ...Matrix) *ShapeError {
+	shapes := make([][]int, len(ms))
+	for i, m := range ms {
+		shapes[i] = []int{m.Rows(), m.Columns()}
+	}
+	return &ShapeError{Op: op, Shapes: shapes}
+}
+
+func formatShape(shape []int) string {
+	s := make([]string, len(shape))
+	for i, d := range shape {
+		s[i] = fmt.Sprint(d)
+	}
+	return strings.Join(s, "×")
+}
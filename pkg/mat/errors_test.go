@@ -0,0 +1,137 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestRecover_ShapeError(t *testing.T) {
+	add := func(a, b Matrix) (out Matrix, err error) {
+		defer Recover(&err)
+		return a.Add(b), nil
+	}
+	_, err := add(NewEmptyDense(2, 3), NewEmptyDense(3, 2))
+	if !errors.Is(err, ErrShapeMismatch) {
+		t.Fatalf("Expected ErrShapeMismatch, found %v", err)
+	}
+	var shapeErr *ShapeError
+	if !errors.As(err, &shapeErr) {
+		t.Fatal("Expected a *ShapeError")
+	}
+	if shapeErr.Op != "Add" || !reflect.DeepEqual(shapeErr.Shapes, [][]int{{2, 3}, {3, 2}}) {
+		t.Errorf("Unexpected error %v", shapeErr)
+	}
+	if err.Error() != "mat: Add: incompatible dimensions 2×3, 3×2" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+func TestRecover_IndexError(t *testing.T) {
+	at := func(m Matrix, i, j int) (v float64, err error) {
+		defer Recover(&err)
+		return m.At(i, j), nil
+	}
+	_, err := at(NewEmptyDense(2, 3), 1, 3)
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("Expected ErrIndexOutOfRange, found %v", err)
+	}
+	var indexErr *IndexError
+	if !errors.As(err, &indexErr) || !reflect.DeepEqual(indexErr.Index, []int{1, 3}) {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if v, err := at(NewEmptyDense(2, 3), 1, 2); err != nil || v != 0 {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestRecover_OtherPanics(t *testing.T) {
+	defer func() {
+		if recover() != "other" {
+			t.Errorf("The panics not raised with an error should be propagated.")
+		}
+	}()
+	func() (err error) {
+		defer Recover(&err)
+		panic("other")
+	}()
+}
+
+func TestRecover_RuntimeError(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(runtime.Error); !ok {
+			t.Errorf("The run-time errors should be propagated.")
+		}
+	}()
+	func() (err error) {
+		defer Recover(&err)
+		var m *Dense
+		m.Rows()
+		return nil
+	}()
+}
+
+func TestRecover_DenseShapeErrors(t *testing.T) {
+	for _, f := range []func(){
+		func() { NewEmptyDense(2, 3).Scalar() },
+		func() { NewEmptyDense(2, 3).AtVec(0) },
+		func() { NewEmptyDense(2, 3).Inverse() },
+		func() { NewEmptyDense32(2, 3).SetVec(0, 1) },
+	} {
+		err := func() (err error) {
+			defer Recover(&err)
+			f()
+			return nil
+		}()
+		if !errors.Is(err, ErrShapeMismatch) {
+			t.Errorf("Expected ErrShapeMismatch, found %v", err)
+		}
+	}
+	err := func() (err error) {
+		defer Recover(&err)
+		OneHotVecDense(3, 3)
+		return nil
+	}()
+	if !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("Expected ErrIndexOutOfRange, found %v", err)
+	}
+}
+
+func TestRecover_TensorErrors(t *testing.T) {
+	tensor := NewEmptyTensor(2, 3, 4)
+	for _, c := range []struct {
+		f      func()
+		target error
+	}{
+		{func() { NewEmptyTensor(2, 0) }, ErrShapeMismatch},
+		{func() { tensor.Permute(0, 1) }, ErrShapeMismatch},
+		{func() { tensor.Permute(0, 1, 1) }, ErrShapeMismatch},
+		{func() { tensor.Permute(0, 1, 3) }, ErrIndexOutOfRange},
+		{func() { tensor.Transpose(0, 3) }, ErrIndexOutOfRange},
+		{func() { tensor.Slice(1, 2, 4) }, ErrIndexOutOfRange},
+		{func() { tensor.Slice(3, 0, 1) }, ErrIndexOutOfRange},
+		{func() { tensor.Sum(-1) }, ErrIndexOutOfRange},
+	} {
+		err := func() (err error) {
+			defer Recover(&err)
+			c.f()
+			return nil
+		}()
+		if !errors.Is(err, c.target) {
+			t.Errorf("Expected %v, found %v", c.target, err)
+		}
+	}
+	err := func() (err error) {
+		defer Recover(&err)
+		Convert(NewEmptyDense(2, 3), DType(-1))
+		return nil
+	}()
+	if err == nil {
+		t.Error("Expected an error for an invalid data type")
+	}
+}
@@ -21,6 +21,10 @@ type header struct {
 // maxLen is the biggest slice/array len one can create on a 32/64b platform.
 const maxLen = int(^uint(0) >> 1)
 
+// float32Flag is set in the rows of the header of the matrices whose values are encoded as float32.
+// The matrices written before the introduction of Dense32 have the flag unset.
+const float32Flag int64 = 1 << 62
+
 var (
 	headerSize    = binary.Size(header{})
 	errTooBig     = errors.New("mat: resulting data slice too big")
@@ -56,8 +60,26 @@ func (s *header) unmarshalBinaryFrom(r io.Reader) (int, error) {
 
 // MarshalBinaryTo encodes the receiver into a binary form and writes it into w.
 // MarshalBinaryTo returns the number of bytes written into w and an error, if any.
+// The values of a Dense32 are encoded as float32, the others as float64. Both forms can be decoded into any matrix.
 func MarshalBinaryTo(m Matrix, w io.Writer) (int, error) {
 	h := header{Rows: int64(m.Rows()), Cols: int64(m.Columns())}
+	if m, ok := m.(*Dense32); ok {
+		h.Rows |= float32Flag
+		n, err := h.marshalBinaryTo(w)
+		if err != nil {
+			return n, err
+		}
+		var b [4]byte
+		for _, num := range m.data {
+			binary.LittleEndian.PutUint32(b[:], math.Float32bits(num))
+			nn, err := w.Write(b[:])
+			n += nn
+			if err != nil {
+				return n, err
+			}
+		}
+		return n, nil
+	}
 	n, err := h.marshalBinaryTo(w)
 	if err != nil {
 		return n, err
@@ -74,79 +96,93 @@ func MarshalBinaryTo(m Matrix, w io.Writer) (int, error) {
 	return n, nil
 }
 
-//
-func NewUnmarshalBinaryFrom(r io.Reader) (*Dense, int, error) {
+// readHeader reads the header of a matrix, returning its dimensions and whether the values are encoded as float32.
+func readHeader(r io.Reader) (rows, cols int, is32 bool, n int, err error) {
 	var h header
-	n, err := h.unmarshalBinaryFrom(r)
+	n, err = h.unmarshalBinaryFrom(r)
 	if err != nil {
-		return nil, n, err
+		return
 	}
-	rows := int(h.Rows)
-	cols := int(h.Cols)
+	is32 = h.Rows >= 0 && h.Rows&float32Flag != 0
+	if is32 {
+		h.Rows &^= float32Flag
+	}
+	rows, cols = int(h.Rows), int(h.Cols)
 	if rows < 0 || cols < 0 {
-		return nil, n, errBadSize
+		err = errBadSize
+		return
 	}
 	size := rows * cols
 	if size == 0 {
-		return nil, n, errZeroLength
+		err = errZeroLength
+		return
 	}
 	if size < 0 || size > maxLen {
-		return nil, n, errTooBig
+		err = errTooBig
 	}
+	return
+}
 
-	data := make([]float64, rows*cols)
+// readData reads the values of a matrix, encoded as float64 or float32, into data.
+func readData(r io.Reader, data []float64, is32 bool) (n int, err error) {
 	var b [8]byte
+	width := 8
+	if is32 {
+		width = 4
+	}
 	for i := range data {
-		nn, err := utils.ReadFull(r, b[:])
+		nn, err := utils.ReadFull(r, b[:width])
 		n += nn
 		if err != nil {
 			if err == io.EOF {
-				return nil, n, io.ErrUnexpectedEOF
+				return n, io.ErrUnexpectedEOF
 			}
-			return nil, n, err
+			return n, err
+		}
+		if is32 {
+			data[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b[:4])))
+		} else {
+			data[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
 		}
-		data[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
 	}
+	return n, nil
+}
 
-	m := NewDense(rows, cols, data)
-	return m, n, nil
+// NewUnmarshalBinaryFrom reads a matrix written by MarshalBinaryTo into a new Dense.
+func NewUnmarshalBinaryFrom(r io.Reader) (*Dense, int, error) {
+	rows, cols, is32, n, err := readHeader(r)
+	if err != nil {
+		return nil, n, err
+	}
+	data := make([]float64, rows*cols)
+	nn, err := readData(r, data, is32)
+	n += nn
+	if err != nil {
+		return nil, n, err
+	}
+	return NewDense(rows, cols, data), n, nil
 }
 
-//
+// UnmarshalBinaryFrom reads a matrix written by MarshalBinaryTo into m, which must have the same dimensions.
 func UnmarshalBinaryFrom(m Matrix, r io.Reader) (int, error) {
-	var h header
-	n, err := h.unmarshalBinaryFrom(r)
+	rows, cols, is32, n, err := readHeader(r)
 	if err != nil {
 		return n, err
 	}
-	rows := int(h.Rows)
-	cols := int(h.Cols)
-	if rows < 0 || cols < 0 {
-		return n, errBadSize
-	}
-	size := rows * cols
-	if size == 0 {
-		return n, errZeroLength
-	}
-	if size < 0 || size > maxLen {
-		return n, errTooBig
-	}
 	if rows != m.Rows() || cols != m.Columns() {
 		return n, errBadSize
 	}
-	var b [8]byte
-	data := m.Data()
-	for i := range data {
-		nn, err := utils.ReadFull(r, b[:])
-		n += nn
-		if err != nil {
-			if err == io.EOF {
-				return n, io.ErrUnexpectedEOF
-			}
-			return n, err
-		}
-		data[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+	if d, ok := m.(*Dense); ok {
+		nn, err := readData(r, d.Data(), is32) // the data of a Dense are read in place
+		return n + nn, err
+	}
+	data := make([]float64, rows*cols)
+	nn, err := readData(r, data, is32)
+	n += nn
+	if err != nil {
+		return n, err
 	}
+	m.SetData(data)
 	return n, nil
 }
 
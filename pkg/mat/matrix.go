@@ -15,6 +15,9 @@ type Matrix interface {
 	Columns() int
 	Size() int
 	LastIndex() int
+	// Data returns the values in row-major order. The slice must be considered read-only, since it is the
+	// underlying data of a Dense but a converted copy for a Dense32: use SetData, Set or the in-place operations
+	// to modify the values of any matrix.
 	Data() []float64
 	IsVector() bool
 	IsScalar() bool
@@ -64,7 +67,7 @@ func ConcatV(vs ...Matrix) Matrix {
 	data := make([]float64, 0, cup)
 	for _, v := range vs {
 		if v.Columns() != 1 {
-			panic("mat: required vector, found matrix.")
+			panic(NewShapeError("ConcatV", v))
 		}
 		data = append(data, v.Data()...)
 	}
@@ -82,3 +85,36 @@ func ConcatH(ms ...Matrix) *Dense {
 	}
 	return out
 }
+
+// Batch returns a new matrix having the vectors as columns, in the same order.
+// This is the mini-batch layout expected by the operators of the computational graph: a batch of B examples
+// of size N is a N x B matrix, with one example per column.
+func Batch(vs ...Matrix) *Dense {
+	rows := vs[0].Size()
+	cols := len(vs)
+	out := GetDenseWorkspace(rows, cols)
+	for j, v := range vs {
+		if v.Size() != rows {
+			panic(NewShapeError("Batch", vs[0], v))
+		}
+		for i, val := range v.Data() {
+			out.data[i*cols+j] = val
+		}
+	}
+	return out
+}
+
+// Unbatch returns the columns of the mini-batch matrix as new vectors.
+// You can think of this method as the inverse of Batch.
+func Unbatch(m Matrix) []Matrix {
+	rows, cols := m.Dims()
+	out := make([]Matrix, cols)
+	for j := range out {
+		v := GetDenseWorkspace(rows, 1)
+		for i := range v.data {
+			v.data[i] = m.At(i, j)
+		}
+		out[j] = v
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	b := Batch(
+		NewVecDense([]float64{0.1, 0.2, 0.3}),
+		NewVecDense([]float64{0.4, 0.5, 0.6}),
+	)
+	if r, c := b.Dims(); r != 3 || c != 2 {
+		t.Error("The dimensions don't match the expected values")
+	}
+	if !floats.EqualApprox(b.Data(), []float64{0.1, 0.4, 0.2, 0.5, 0.3, 0.6}, 1.0e-6) {
+		t.Error("The result doesn't match the expected values")
+	}
+	vs := Unbatch(b)
+	if len(vs) != 2 {
+		t.Fatal("The number of vectors doesn't match the expected value")
+	}
+	if !floats.EqualApprox(vs[0].Data(), []float64{0.1, 0.2, 0.3}, 1.0e-6) {
+		t.Error("The first vector doesn't match the expected values")
+	}
+	if !floats.EqualApprox(vs[1].Data(), []float64{0.4, 0.5, 0.6}, 1.0e-6) {
+		t.Error("The second vector doesn't match the expected values")
+	}
+}
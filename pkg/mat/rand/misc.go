@@ -7,10 +7,52 @@ package rand
 import (
 	"github.com/nlpodyssey/spago/pkg/utils"
 	"golang.org/x/exp/rand"
+	"sync"
 )
 
+var (
+	globalMu sync.RWMutex
+	// global replaces the global random, if not nil (see SetGlobal)
+	global *LockedRand
+)
+
+// SetGlobal sets the generator used in place of the global random by the functions of this package which don't
+// take a generator (e.g. WeightedChoice), so that they can be reproduced. A nil generator restores the global random.
+func SetGlobal(generator *LockedRand) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = generator
+}
+
+// getGlobal returns the generator set with SetGlobal, if any.
+func getGlobal() *LockedRand {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	return global
+}
+
+// Float64 returns, as a float64, a pseudo-random number in [0.0,1.0) from the global random (see SetGlobal).
+func Float64() float64 {
+	if generator := getGlobal(); generator != nil {
+		return generator.Float64()
+	}
+	return rand.Float64()
+}
+
+// Intn returns, as an int, a non-negative pseudo-random number in [0,n) from the global random (see SetGlobal).
+// It panics if n <= 0.
+func Intn(n int) int {
+	if generator := getGlobal(); generator != nil {
+		return generator.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
 func ShuffleInPlace(xs []int, generator *LockedRand) []int {
 	swap := func(i, j int) { xs[i], xs[j] = xs[j], xs[i] }
+	if generator == nil {
+		generator = getGlobal()
+	}
 	if generator != nil {
 		generator.Shuffle(len(xs), swap)
 	} else {
@@ -20,9 +62,9 @@ func ShuffleInPlace(xs []int, generator *LockedRand) []int {
 }
 
 // WeightedChoice performs a random generation of the indices based of the probability distribution itself.
-// Please note that it uses the global random.
+// Please note that it uses the global random (see SetGlobal).
 func WeightedChoice(dist []float64) int {
-	rnd := rand.Float64() // // Warning: use global rand
+	rnd := Float64() // Warning: use global rand
 	cumulativeProb := 0.0
 	for i, prob := range dist {
 		cumulativeProb += prob
@@ -38,9 +80,9 @@ func WeightedChoice(dist []float64) int {
 func GetUniqueRandomInt(n, max int, valid func(r int) bool) []int {
 	a := make([]int, n)
 	for i := 0; i < n; i++ {
-		r := rand.Intn(max) // Warning: use global rand
+		r := Intn(max) // Warning: use global rand
 		for !valid(r) || utils.ContainsInt(a, r) {
-			r = rand.Intn(max) // Warning: use global rand
+			r = Intn(max) // Warning: use global rand
 		}
 		a[i] = r
 	}
@@ -0,0 +1,341 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tensor is a n-dimensional array of float64.
+// The values are addressed by means of strides, so that permutations, transpositions and slices are views
+// sharing the same underlying data, without copying them.
+// The graph nodes don't hold tensors: the n-dimensional operators (see ag.Graph.Permute) view the 2-D values of the
+// nodes as tensors of a shape given by the caller.
+type Tensor struct {
+	shape   []int
+	strides []int
+	offset  int
+	data    []float64
+}
+
+// NewTensor returns a new tensor of the given shape populated with a copy of the elements, in row-major order.
+func NewTensor(shape []int, elements []float64) *Tensor {
+	t := NewEmptyTensor(shape...)
+	if len(elements) != len(t.data) {
+		panic(&ShapeError{Op: "NewTensor", Shapes: [][]int{shape, {len(elements)}}})
+	}
+	copy(t.data, elements)
+	return t
+}
+
+// NewEmptyTensor returns a new tensor of the given shape initialized to zeros.
+func NewEmptyTensor(shape ...int) *Tensor {
+	size := 1
+	for _, d := range shape {
+		if d <= 0 {
+			panic(&ShapeError{Op: "NewEmptyTensor", Shapes: [][]int{shape}})
+		}
+		size *= d
+	}
+	return &Tensor{
+		shape:   append([]int(nil), shape...),
+		strides: contiguousStrides(shape),
+		offset:  0,
+		data:    make([]float64, size),
+	}
+}
+
+// TensorOf returns a tensor of the given shape with the values of the matrix, in row-major order.
+// If no shape is given, the tensor has the same dimensions of the matrix.
+// The data are shared with the matrix if it is a Dense, otherwise they are copied.
+func TensorOf(m Matrix, shape ...int) *Tensor {
+	if len(shape) == 0 {
+		shape = []int{m.Rows(), m.Columns()}
+	}
+	t := &Tensor{
+		shape:   append([]int(nil), shape...),
+		strides: contiguousStrides(shape),
+		offset:  0,
+		data:    m.Data(),
+	}
+	if t.Size() != len(t.data) {
+		panic(&ShapeError{Op: "TensorOf", Shapes: [][]int{{m.Rows(), m.Columns()}, shape}})
+	}
+	return t
+}
+
+// contiguousStrides returns the strides of a tensor of the given shape, with values in row-major order.
+func contiguousStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= shape[i]
+	}
+	return strides
+}
+
+// Shape returns the size of each dimension.
+func (t *Tensor) Shape() []int {
+	return append([]int(nil), t.shape...)
+}
+
+// Rank returns the number of dimensions.
+func (t *Tensor) Rank() int {
+	return len(t.shape)
+}
+
+// Size returns the number of elements.
+func (t *Tensor) Size() int {
+	size := 1
+	for _, d := range t.shape {
+		size *= d
+	}
+	return size
+}
+
+// Strides returns the number of elements to skip in the underlying data to move along each dimension.
+func (t *Tensor) Strides() []int {
+	return append([]int(nil), t.strides...)
+}
+
+// index returns the position in the underlying data of the element at the given indices.
+func (t *Tensor) index(idx []int) int {
+	if len(idx) != len(t.shape) {
+		panic(&IndexError{Index: idx, Shape: t.shape})
+	}
+	pos := t.offset
+	for i, k := range idx {
+		if k < 0 || k >= t.shape[i] {
+			panic(&IndexError{Index: idx, Shape: t.shape})
+		}
+		pos += k * t.strides[i]
+	}
+	return pos
+}
+
+// At returns the value at the given indices.
+func (t *Tensor) At(idx ...int) float64 {
+	return t.data[t.index(idx)]
+}
+
+// Set sets the value v at the given indices.
+func (t *Tensor) Set(v float64, idx ...int) {
+	t.data[t.index(idx)] = v
+}
+
+// IsContiguous returns whether the values of the tensor are stored in row-major order without gaps.
+func (t *Tensor) IsContiguous() bool {
+	if t.offset != 0 || len(t.data) != t.Size() {
+		return false
+	}
+	for i, s := range contiguousStrides(t.shape) {
+		if t.shape[i] > 1 && t.strides[i] != s {
+			return false
+		}
+	}
+	return true
+}
+
+// Contiguous returns the tensor itself if it is contiguous, otherwise a contiguous copy of it.
+func (t *Tensor) Contiguous() *Tensor {
+	if t.IsContiguous() {
+		return t
+	}
+	return t.Clone()
+}
+
+// Clone returns a new contiguous tensor copying the values of the receiver.
+func (t *Tensor) Clone() *Tensor {
+	out := NewEmptyTensor(t.shape...)
+	i := 0
+	t.forEach(func(pos int, _ []int) {
+		out.data[i] = t.data[pos]
+		i++
+	})
+	return out
+}
+
+// Data returns the values of the tensor in row-major order.
+// If the tensor is contiguous, the underlying data are returned, otherwise a copy.
+func (t *Tensor) Data() []float64 {
+	return t.Contiguous().data
+}
+
+// forEach calls fn for each element in row-major order, passing the position in the underlying data
+// and the indices of the element. The indices must not be retained.
+func (t *Tensor) forEach(fn func(pos int, idx []int)) {
+	idx := make([]int, len(t.shape))
+	pos := t.offset
+	for {
+		fn(pos, idx)
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			pos += t.strides[k]
+			if idx[k] < t.shape[k] {
+				break
+			}
+			pos -= t.strides[k] * idx[k]
+			idx[k] = 0
+		}
+		if k < 0 {
+			return
+		}
+	}
+}
+
+// Apply executes the unary function fn on each element, in place.
+func (t *Tensor) Apply(fn func(v float64) float64) *Tensor {
+	t.forEach(func(pos int, _ []int) {
+		t.data[pos] = fn(t.data[pos])
+	})
+	return t
+}
+
+// Permute returns a view of the tensor with the dimensions reordered according to the axes.
+// The i-th dimension of the result is the axes[i]-th dimension of the receiver.
+func (t *Tensor) Permute(axes ...int) *Tensor {
+	if len(axes) != len(t.shape) {
+		panic(&ShapeError{Op: "Permute", Shapes: [][]int{t.shape, axes}})
+	}
+	seen := make([]bool, len(axes))
+	shape := make([]int, len(axes))
+	strides := make([]int, len(axes))
+	for i, a := range axes {
+		if a < 0 || a >= len(axes) {
+			panic(&IndexError{Index: []int{a}, Shape: []int{len(axes)}})
+		}
+		if seen[a] {
+			panic(&ShapeError{Op: "Permute", Shapes: [][]int{t.shape, axes}}) // not a permutation
+		}
+		seen[a] = true
+		shape[i] = t.shape[a]
+		strides[i] = t.strides[a]
+	}
+	return &Tensor{shape: shape, strides: strides, offset: t.offset, data: t.data}
+}
+
+// Transpose returns a view of the tensor with the axes a and b swapped.
+func (t *Tensor) Transpose(a, b int) *Tensor {
+	t.checkAxis(a)
+	t.checkAxis(b)
+	axes := make([]int, len(t.shape))
+	for i := range axes {
+		axes[i] = i
+	}
+	axes[a], axes[b] = axes[b], axes[a]
+	return t.Permute(axes...)
+}
+
+// Reshape returns a tensor with the same values and the given shape.
+// It returns a view if the receiver is contiguous, a copy otherwise.
+func (t *Tensor) Reshape(shape ...int) *Tensor {
+	c := t.Contiguous()
+	out := &Tensor{
+		shape:   append([]int(nil), shape...),
+		strides: contiguousStrides(shape),
+		offset:  0,
+		data:    c.data,
+	}
+	if out.Size() != c.Size() {
+		panic(&ShapeError{Op: "Reshape", Shapes: [][]int{t.shape, shape}})
+	}
+	return out
+}
+
+// checkAxis panics with an *IndexError if the axis is not in the range [0, rank).
+func (t *Tensor) checkAxis(axis int) {
+	if axis < 0 || axis >= len(t.shape) {
+		panic(&IndexError{Index: []int{axis}, Shape: []int{len(t.shape)}})
+	}
+}
+
+// Slice returns a view of the tensor restricted to the indices in [start, end) along the axis.
+func (t *Tensor) Slice(axis, start, end int) *Tensor {
+	t.checkAxis(axis)
+	if start < 0 || end > t.shape[axis] || start >= end {
+		panic(&IndexError{Index: []int{start, end}, Shape: []int{t.shape[axis]}})
+	}
+	shape := t.Shape()
+	shape[axis] = end - start
+	return &Tensor{
+		shape:   shape,
+		strides: t.Strides(),
+		offset:  t.offset + start*t.strides[axis],
+		data:    t.data,
+	}
+}
+
+// Select returns a view of the tensor at index i along the axis; the result has one dimension less.
+func (t *Tensor) Select(axis, i int) *Tensor {
+	s := t.Slice(axis, i, i+1)
+	s.shape = append(s.shape[:axis], s.shape[axis+1:]...)
+	s.strides = append(s.strides[:axis], s.strides[axis+1:]...)
+	return s
+}
+
+// reduce returns a new tensor without the given axis, whose elements are obtained reducing the values along it.
+func (t *Tensor) reduce(axis int, init float64, fn func(acc, v float64) float64) *Tensor {
+	t.checkAxis(axis)
+	outShape := append(append([]int(nil), t.shape[:axis]...), t.shape[axis+1:]...)
+	if len(outShape) == 0 {
+		outShape = []int{1}
+	}
+	out := NewEmptyTensor(outShape...)
+	for i := range out.data {
+		out.data[i] = init
+	}
+	outStrides := append(append([]int(nil), out.strides[:axis]...), append([]int{0}, out.strides[axis:]...)...)
+	if len(t.shape) == 1 {
+		outStrides = []int{0}
+	}
+	t.forEach(func(pos int, idx []int) {
+		k := 0
+		for i, v := range idx {
+			k += v * outStrides[i]
+		}
+		out.data[k] = fn(out.data[k], t.data[pos])
+	})
+	return out
+}
+
+// Sum returns a new tensor with the sum of the values along the axis, which is removed.
+func (t *Tensor) Sum(axis int) *Tensor {
+	return t.reduce(axis, 0.0, func(acc, v float64) float64 { return acc + v })
+}
+
+// Mean returns a new tensor with the mean of the values along the axis, which is removed.
+func (t *Tensor) Mean(axis int) *Tensor {
+	n := float64(t.shape[axis])
+	return t.Sum(axis).Apply(func(v float64) float64 { return v / n })
+}
+
+// Max returns a new tensor with the max of the values along the axis, which is removed.
+func (t *Tensor) Max(axis int) *Tensor {
+	return t.reduce(axis, math.Inf(-1), math.Max)
+}
+
+// Min returns a new tensor with the min of the values along the axis, which is removed.
+func (t *Tensor) Min(axis int) *Tensor {
+	return t.reduce(axis, math.Inf(1), math.Min)
+}
+
+// ToDense returns a new matrix copying the values of the tensor.
+// The last dimension becomes the columns, while the others are flattened into the rows.
+// A tensor of rank 1 becomes a column vector.
+func (t *Tensor) ToDense() *Dense {
+	if len(t.shape) == 1 {
+		return NewVecDense(t.Data())
+	}
+	cols := t.shape[len(t.shape)-1]
+	return NewDense(t.Size()/cols, cols, t.Data())
+}
+
+// String returns the string representation of the tensor.
+func (t *Tensor) String() string {
+	return fmt.Sprintf("Tensor%v%v", t.shape, t.Data())
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"reflect"
+	"testing"
+)
+
+func newTestTensor() *Tensor {
+	return NewTensor([]int{2, 3, 2}, []float64{
+		0, 1, 2, 3, 4, 5,
+		6, 7, 8, 9, 10, 11,
+	})
+}
+
+func TestTensor_At(t *testing.T) {
+	x := newTestTensor()
+	if x.Rank() != 3 || x.Size() != 12 {
+		t.Error("The rank or the size doesn't match the expected values")
+	}
+	if !reflect.DeepEqual(x.Strides(), []int{6, 2, 1}) {
+		t.Error("The strides don't match the expected values")
+	}
+	if x.At(1, 2, 0) != 10 {
+		t.Error("The value doesn't match the expected value")
+	}
+	x.Set(-1, 1, 2, 0)
+	if x.Data()[10] != -1 {
+		t.Error("The value hasn't been set")
+	}
+}
+
+func TestTensor_Permute(t *testing.T) {
+	x := newTestTensor()
+	y := x.Permute(2, 0, 1)
+	if !reflect.DeepEqual(y.Shape(), []int{2, 2, 3}) {
+		t.Error("The shape doesn't match the expected values")
+	}
+	if y.IsContiguous() {
+		t.Error("The permutation must be a view")
+	}
+	if !floats.Equal(y.Data(), []float64{0, 2, 4, 6, 8, 10, 1, 3, 5, 7, 9, 11}) {
+		t.Error("The result doesn't match the expected values")
+	}
+	y.Set(-1, 1, 0, 0)
+	if x.At(0, 0, 1) != -1 {
+		t.Error("The view doesn't share the data with the tensor")
+	}
+}
+
+func TestTensor_TransposeReshape(t *testing.T) {
+	y := newTestTensor().Transpose(0, 2).Reshape(4, 3)
+	if !floats.Equal(y.Data(), []float64{0, 6, 2, 8, 4, 10, 1, 7, 3, 9, 5, 11}) {
+		t.Error("The result doesn't match the expected values")
+	}
+	m := y.ToDense()
+	if m.Rows() != 4 || m.Columns() != 3 {
+		t.Error("The dimensions of the matrix don't match the expected values")
+	}
+}
+
+func TestTensor_SliceSelect(t *testing.T) {
+	x := newTestTensor()
+	s := x.Slice(1, 1, 3)
+	if !reflect.DeepEqual(s.Shape(), []int{2, 2, 2}) {
+		t.Error("The shape doesn't match the expected values")
+	}
+	if !floats.Equal(s.Data(), []float64{2, 3, 4, 5, 8, 9, 10, 11}) {
+		t.Error("The slice doesn't match the expected values")
+	}
+	r := x.Select(0, 1)
+	if !reflect.DeepEqual(r.Shape(), []int{3, 2}) {
+		t.Error("The shape doesn't match the expected values")
+	}
+	if !floats.Equal(r.Data(), []float64{6, 7, 8, 9, 10, 11}) {
+		t.Error("The selection doesn't match the expected values")
+	}
+}
+
+func TestTensor_Reductions(t *testing.T) {
+	x := newTestTensor()
+	if y := x.Sum(1); !reflect.DeepEqual(y.Shape(), []int{2, 2}) || !floats.Equal(y.Data(), []float64{6, 9, 24, 27}) {
+		t.Error("The sum doesn't match the expected values")
+	}
+	if y := x.Mean(2); !floats.Equal(y.Data(), []float64{0.5, 2.5, 4.5, 6.5, 8.5, 10.5}) {
+		t.Error("The mean doesn't match the expected values")
+	}
+	if y := x.Max(0); !floats.Equal(y.Data(), []float64{6, 7, 8, 9, 10, 11}) {
+		t.Error("The max doesn't match the expected values")
+	}
+	if y := x.Permute(1, 0, 2).Min(0); !floats.Equal(y.Data(), []float64{0, 1, 6, 7}) {
+		t.Error("The min doesn't match the expected values")
+	}
+	if y := NewTensor([]int{3}, []float64{1, 2, 3}).Sum(0); !floats.Equal(y.Data(), []float64{6}) {
+		t.Error("The sum of the vector doesn't match the expected value")
+	}
+}
+
+func TestTensorOf(t *testing.T) {
+	m := NewDense(2, 6, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11})
+	x := TensorOf(m, 2, 3, 2)
+	if x.At(1, 1, 1) != 9 {
+		t.Error("The value doesn't match the expected value")
+	}
+	if y := TensorOf(m); !reflect.DeepEqual(y.Shape(), []int{2, 6}) {
+		t.Error("The shape doesn't match the matrix dimensions")
+	}
+}
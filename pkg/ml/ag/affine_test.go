@@ -0,0 +1,90 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+type affineParams struct {
+	b, w1, x1, w2, x2 Node
+}
+
+func newAffineParams(g *Graph) affineParams {
+	return affineParams{
+		b:  g.NewVariable(mat.NewVecDense([]float64{0.1, -0.2, 0.3}), true),
+		w1: g.NewVariable(mat.NewDense(3, 2, []float64{0.1, -0.2, 0.3, 0.4, -0.5, 0.6}), true),
+		x1: g.NewVariable(mat.NewDense(2, 2, []float64{0.7, -0.8, 0.9, 0.2}), true),
+		w2: g.NewVariable(mat.NewDense(3, 3, []float64{0.5, 0.2, -0.1, 0.3, -0.4, 0.8, 0.6, 0.1, -0.7}), true),
+		x2: g.NewVariable(mat.NewDense(3, 2, []float64{0.2, 0.4, -0.6, 0.5, 0.3, -0.9}), true),
+	}
+}
+
+func (p affineParams) nodes() []Node {
+	return []Node{p.b, p.w1, p.x1, p.w2, p.x2}
+}
+
+func TestGraph_AffineMatchesUnfused(t *testing.T) {
+	for _, activation := range []OpName{OpIdentity, OpTanh, OpSigmoid, OpReLU, OpSoftsign, OpGELU, OpGELUTanh, OpSoftmax, OpSparsemax} {
+		g1 := NewGraph()
+		p1 := newAffineParams(g1)
+		y1 := g1.Affine(activation, p1.nodes()...)
+		g1.Backward(y1, mat.NewDense(3, 2, []float64{0.3, -0.1, 0.2, 0.5, -0.4, 0.6}))
+
+		g2 := NewGraph()
+		p2 := newAffineParams(g2)
+		y2 := g2.Invoke(activation, g2.Add(g2.Add(p2.b, g2.Mul(p2.w1, p2.x1)), g2.Mul(p2.w2, p2.x2)))
+		g2.Backward(y2, mat.NewDense(3, 2, []float64{0.3, -0.1, 0.2, 0.5, -0.4, 0.6}))
+
+		if !floats.EqualApprox(y1.Value().Data(), y2.Value().Data(), 1.0e-12) {
+			t.Errorf("%s: the output doesn't match the unfused operators", activation)
+		}
+		for i, n := range p1.nodes() {
+			if !floats.EqualApprox(n.Grad().Data(), p2.nodes()[i].Grad().Data(), 1.0e-12) {
+				t.Errorf("%s: the gradients of the operand %d don't match the unfused operators", activation, i)
+			}
+		}
+	}
+}
+
+func TestGraph_AffineSkipsNilPairs(t *testing.T) {
+	g := NewGraph()
+	p := newAffineParams(g)
+	y := g.Affine(OpTanh, nil, p.w1, p.x1, p.w2, nil)
+	expected := g.Tanh(g.Mul(p.w1, p.x1))
+	if !floats.EqualApprox(y.Value().Data(), expected.Value().Data(), 1.0e-12) {
+		t.Error("The output doesn't match the expected values")
+	}
+}
+
+func TestGraph_AffineGradientsAndJVP(t *testing.T) {
+	g := NewGraph()
+	p := newAffineParams(g)
+	y := g.ReduceSum(g.Affine(OpTanh, p.nodes()...))
+
+	tangents := []mat.Matrix{
+		mat.NewVecDense([]float64{0.2, -0.7, 0.1}),
+		mat.NewDense(3, 2, []float64{0.3, 0.1, -0.2, 0.5, 0.4, -0.1}),
+		mat.NewDense(2, 2, []float64{-0.6, 0.2, 0.1, 0.3}),
+		mat.NewDense(3, 3, []float64{0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, -0.9}),
+		mat.NewDense(3, 2, []float64{0.5, -0.5, 0.2, 0.1, -0.3, 0.4}),
+	}
+	ty := g.JVP([]Node{y}, p.nodes(), tangents)[0]
+	gxs := g.Gradients(y, p.nodes())
+
+	g.Backward(y)
+	expected := 0.0
+	for i, n := range p.nodes() {
+		if !floats.EqualApprox(gxs[i].Value().Data(), n.Grad().Data(), 1.0e-12) {
+			t.Errorf("The gradients of the operand %d don't match the back-propagation", i)
+		}
+		expected += floats.Dot(n.Grad().Data(), tangents[i].Data())
+	}
+	if !floats.EqualApprox(ty.Data(), []float64{expected}, 1.0e-12) {
+		t.Error("The Jacobian-vector product doesn't match the back-propagation")
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentBackward enables the concurrent execution of the backward pass on a pool of the given number of workers.
+// If workers is less than 1, the number of logical CPUs is used.
+//
+// An operator is processed as soon as all the operators using it have been processed, so independent subgraphs
+// (e.g. the heads of an attention, or the sequences of a mini-batch) are back-propagated in parallel.
+// The operators propagating gradients to the same node are still processed one at a time, in the same order of the
+// sequential back-propagation, so the gradients are accumulated deterministically and the results are identical.
+// The graphs containing checkpoints (see Checkpoint) are back-propagated sequentially.
+func ConcurrentBackward(workers int) GraphOption {
+	return func(g *Graph) {
+		if workers < 1 {
+			workers = runtime.NumCPU()
+		}
+		g.backwardWorkers = workers
+	}
+}
+
+// backPropagate performs the backward of the operators among the nodes, in reverse topological order.
+func (g *Graph) backPropagate(nodes []Node) {
+	if g.backwardWorkers > 1 && !hasCheckpoints(nodes) {
+		g.concurrentBackPropagation(nodes)
+		return
+	}
+	r := &recomputer{graph: g}
+	defer r.release()
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if node, ok := nodes[i].(*operator); ok {
+			r.backward(node)
+		}
+	}
+}
+
+func hasCheckpoints(nodes []Node) bool {
+	for _, node := range nodes {
+		if op, ok := node.(*operator); ok && op.checkpoint != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// backwardTask is an operator to process during the concurrent back-propagation.
+type backwardTask struct {
+	op *operator
+	// pending is the number of tasks to wait for
+	pending int32
+	// next contains the tasks waiting for this one
+	next []*backwardTask
+}
+
+// concurrentBackPropagation performs the backward of the operators among the nodes on the pool of workers.
+// Each task waits for the last task propagating gradients to its operator, and for the last task propagating
+// gradients to each of its operands, so the tasks sharing a node are chained in descending id order.
+func (g *Graph) concurrentBackPropagation(nodes []Node) {
+	tasks := make([]*backwardTask, 0, len(nodes))
+	last := make(map[interface{}]*backwardTask) // the last task propagating gradients to a node
+	link := func(t *backwardTask, key interface{}) {
+		if prev, ok := last[key]; ok && prev != t {
+			prev.next = append(prev.next, t)
+			t.pending++
+		}
+		last[key] = t
+	}
+	for i := len(nodes) - 1; i >= 0; i-- {
+		op, ok := nodes[i].(*operator)
+		if !ok || op.function == nil || !op.requiresGrad {
+			continue // no gradients to propagate
+		}
+		t := &backwardTask{op: op}
+		if prev, ok := last[gradKey(op)]; ok {
+			prev.next = append(prev.next, t)
+			t.pending++
+		}
+		for _, x := range op.operands {
+			link(t, gradKey(x))
+		}
+		tasks = append(tasks, t)
+	}
+	if len(tasks) == 0 {
+		return
+	}
+
+	workers := g.backwardWorkers
+	if workers > len(tasks) {
+		workers = len(tasks)
+	}
+	ready := make(chan *backwardTask, len(tasks))
+	for _, t := range tasks {
+		if t.pending == 0 {
+			ready <- t
+		}
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(tasks))
+	var mu sync.Mutex
+	var failure interface{}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for t := range ready {
+				if r := t.run(); r != nil {
+					mu.Lock()
+					if failure == nil {
+						failure = r
+					}
+					mu.Unlock()
+				}
+				for _, next := range t.next {
+					if atomic.AddInt32(&next.pending, -1) == 0 {
+						ready <- next
+					}
+				}
+				wg.Done()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ready)
+	if failure != nil {
+		panic(failure) // re-panic on the caller's goroutine, as the sequential back-propagation would do
+	}
+}
+
+// run performs the backward of the operator, recovering from a panic.
+func (t *backwardTask) run() (failure interface{}) {
+	defer func() {
+		failure = recover()
+	}()
+	t.op.backward()
+	return nil
+}
+
+// gradKey returns the identity of the accumulator of the gradients of the node.
+// Different wrappers of the same value (e.g. a parameter wrapped by several processors) share the accumulator.
+func gradKey(node Node) interface{} {
+	if w, ok := node.(*wrapper); ok && reflect.TypeOf(w.GradValue).Kind() == reflect.Ptr {
+		return w.GradValue
+	}
+	return node
+}
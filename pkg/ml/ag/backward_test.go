@@ -0,0 +1,101 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"testing"
+)
+
+// batchLoss builds independent branches sharing the parameters w and b, summing their outputs.
+func batchLoss(g *Graph) (w, b Node, xs []Node, loss Node) {
+	r := rand.NewLockedRand(7)
+	randVec := func(size int) mat.Matrix {
+		data := make([]float64, size)
+		for i := range data {
+			data[i] = r.Float64()*2 - 1
+		}
+		return mat.NewVecDense(data)
+	}
+	w = g.NewVariable(mat.NewDense(4, 4, randVec(16).Data()), true)
+	b = g.NewVariable(randVec(4), true)
+	for i := 0; i < 16; i++ {
+		x := g.NewVariable(randVec(4), true)
+		xs = append(xs, x)
+		h := x
+		for j := 0; j < 3; j++ {
+			h = g.Tanh(g.Add(g.Mul(w, h), b))
+		}
+		y := g.ReduceSum(g.Prod(h, x))
+		if loss == nil {
+			loss = y
+		} else {
+			loss = g.Add(loss, y)
+		}
+	}
+	return
+}
+
+func TestConcurrentBackward(t *testing.T) {
+	g1 := NewGraph()
+	w1, b1, xs1, loss1 := batchLoss(g1)
+	g1.Backward(loss1)
+
+	g2 := NewGraph(ConcurrentBackward(8))
+	w2, b2, xs2, loss2 := batchLoss(g2)
+	g2.Backward(loss2)
+
+	assertSameData := func(name string, a, b mat.Matrix) {
+		for i, v := range a.Data() {
+			if b.Data()[i] != v {
+				t.Errorf("The gradients of %s are not identical to the sequential ones.", name)
+				return
+			}
+		}
+	}
+	assertSameData("w", w1.Grad(), w2.Grad())
+	assertSameData("b", b1.Grad(), b2.Grad())
+	for i := range xs1 {
+		assertSameData("x", xs1[i].Grad(), xs2[i].Grad())
+	}
+}
+
+func TestConcurrentBackward_Truncated(t *testing.T) {
+	g := NewGraph(ConcurrentBackward(4))
+	x := g.NewVariable(mat.NewScalar(2), true)
+	y := g.Square(x)
+	g.IncTimeStep()
+	z := g.Prod(y, y)
+	g.TBackward(z, 1)
+	if x.HasGrad() {
+		t.Errorf("The back-propagation should have stopped before x.")
+	}
+	if y.Grad().Scalar() != 8 {
+		t.Errorf("The gradients of y don't match the expected value.")
+	}
+}
+
+// failingBackward is a function whose backward panics.
+type failingBackward struct {
+	x fn.Operand
+}
+
+func (r *failingBackward) Forward() mat.Matrix { return r.x.Value().Clone() }
+
+func (r *failingBackward) Backward(gy mat.Matrix) { panic("backward failure") }
+
+func TestConcurrentBackward_Panic(t *testing.T) {
+	g := NewGraph(ConcurrentBackward(4))
+	x := g.NewVariable(mat.NewVecDense([]float64{1, 2}), true)
+	y := g.Add(g.Tanh(x), g.NewOperator(&failingBackward{x: x}, x))
+	defer func() {
+		if recover() != "backward failure" {
+			t.Errorf("The panic of the backward should be propagated to the caller.")
+		}
+	}()
+	g.Backward(y)
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"sync"
+	"sync/atomic"
+)
+
+// checkpoint is a segment of the graph whose values are released after the forward pass.
+type checkpoint struct {
+	// start and end are the ids of the first and the last node of the segment
+	start int64
+	end   int64
+	// mu avoids concurrent recomputations of the segment
+	mu sync.Mutex
+}
+
+// Checkpoint executes the callback, which builds a segment of the graph, trading computation for memory:
+// at the end of the callback, the values of the operators created within it are released, except the ones of
+// the returned nodes. The values are recomputed during the back-propagation, one segment at a time, and released
+// again as soon as the back-propagation leaves the segment.
+//
+// If the value of a released operator is accessed afterwards (e.g. it is used as operand by a new operator),
+// the segment is recomputed and the value is kept from then on.
+// The functions within a checkpoint must be deterministic (e.g. no dropout), since they are recomputed.
+// Nested checkpoints are merged into the outermost one. The nodes created concurrently by other goroutines
+// during the callback become part of the segment.
+//
+// A typical use is to wrap each step of a recurrent network, or each layer of a deep network, to process
+// sequences that wouldn't fit in memory otherwise.
+func (g *Graph) Checkpoint(callback func() []Node) []Node {
+	start := atomic.LoadInt64(&g.maxId) + 1
+	ys := callback()
+	end := atomic.LoadInt64(&g.maxId)
+	if end < start {
+		return ys
+	}
+	c := &checkpoint{start: start, end: end}
+	g.mu.Lock()
+	nodes := g.nodes[start : end+1]
+	g.mu.Unlock()
+	for _, node := range nodes {
+		if op, ok := node.(*operator); ok {
+			op.checkpoint = c
+		}
+	}
+	for _, y := range ys {
+		if op, ok := y.(*operator); ok && op.checkpoint == c {
+			op.pinned = true
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	g.releaseCheckpoint(c)
+	return ys
+}
+
+// restore recomputes the segment the operator belongs to, keeping its value from now on.
+func (g *Graph) restore(op *operator) {
+	c := op.checkpoint
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if op.value != nil {
+		return // already recomputed concurrently
+	}
+	g.recompute(c)
+	op.pinned = true
+	g.releaseCheckpoint(c)
+}
+
+// recompute computes the missing values of the operators of the segment, in topological order.
+func (g *Graph) recompute(c *checkpoint) {
+	g.mu.Lock()
+	nodes := g.nodes[c.start : c.end+1]
+	g.mu.Unlock()
+	for _, node := range nodes {
+		if op, ok := node.(*operator); ok && op.value == nil && op.function != nil {
+			op.value = g.forward(op.opName, op.function, op.operands)
+		}
+	}
+}
+
+// releaseCheckpoint releases the values of the operators of the segment that are not pinned, along with the memory
+// kept by their functions for the backward (see fn.Releaser), which is recomputed with the values.
+// The values of the operators created in no-grad mode are kept, since they cannot be recomputed.
+func (g *Graph) releaseCheckpoint(c *checkpoint) {
+	g.mu.Lock()
+	nodes := g.nodes[c.start : c.end+1]
+	g.mu.Unlock()
+	for _, node := range nodes {
+		if op, ok := node.(*operator); ok && !op.pinned && op.function != nil {
+			g.releaseValue(op)
+			if f, ok := op.function.(fn.Releaser); ok {
+				f.Release()
+			}
+		}
+	}
+}
+
+// recomputer performs the backward of the operators, recomputing the values of the segments
+// of the checkpoints as they are entered.
+type recomputer struct {
+	graph  *Graph
+	active *checkpoint
+}
+
+func (r *recomputer) backward(op *operator) {
+	if op.hasGrad && op.checkpoint != nil && op.checkpoint != r.active {
+		r.release()
+		r.active = op.checkpoint
+		r.active.mu.Lock()
+		r.graph.recompute(r.active)
+	}
+	op.backward()
+}
+
+// release releases the values of the active segment, if any.
+func (r *recomputer) release() {
+	if r.active == nil {
+		return
+	}
+	r.graph.releaseCheckpoint(r.active)
+	r.active.mu.Unlock()
+	r.active = nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"gonum.org/v1/gonum/floats"
+	"reflect"
+	"testing"
+)
+
+// rnnChain builds a simple recurrent network h(t) = tanh(w·h(t-1) + x(t)), optionally checkpointing each step.
+func rnnChain(g *Graph, checkpoint bool) (w Node, h0 Node, hs []Node) {
+	w = g.NewVariable(mat.NewDense(2, 2, []float64{0.5, -0.3, 0.2, 0.8}), true)
+	h0 = g.NewVariable(mat.NewVecDense([]float64{0.1, -0.2}), true)
+	xs := [][]float64{{0.3, 0.1}, {-0.4, 0.6}, {0.9, -0.7}, {0.2, 0.2}}
+	h := h0
+	for _, x := range xs {
+		x := g.NewVariable(mat.NewVecDense(x), false)
+		step := func() []Node {
+			return []Node{g.Tanh(g.Add(g.Mul(w, h), x))}
+		}
+		if checkpoint {
+			h = g.Checkpoint(step)[0]
+		} else {
+			h = step()[0]
+		}
+		hs = append(hs, h)
+	}
+	return
+}
+
+func TestGraph_Checkpoint(t *testing.T) {
+	g1 := NewGraph()
+	w1, h01, hs1 := rnnChain(g1, false)
+	g2 := NewGraph()
+	w2, h02, hs2 := rnnChain(g2, true)
+
+	for i, h := range hs2 {
+		if !floats.EqualApprox(h.Value().Data(), hs1[i].Value().Data(), 1.0e-12) {
+			t.Errorf("The output of the step %d doesn't match the expected value.", i)
+		}
+	}
+	released := 0
+	for _, node := range g2.nodes {
+		if op, ok := node.(*operator); ok && op.value == nil {
+			released++
+		}
+	}
+	if released != 2*len(hs2) {
+		t.Errorf("Expected %d released values, found %d.", 2*len(hs2), released)
+	}
+
+	g1.Backward(hs1[len(hs1)-1])
+	g2.Backward(hs2[len(hs2)-1])
+	if !floats.EqualApprox(w2.Grad().Data(), w1.Grad().Data(), 1.0e-12) {
+		t.Errorf("The gradients of w don't match the expected values.")
+	}
+	if !floats.EqualApprox(h02.Grad().Data(), h01.Grad().Data(), 1.0e-12) {
+		t.Errorf("The gradients of h0 don't match the expected values.")
+	}
+	for _, node := range g2.nodes {
+		if op, ok := node.(*operator); ok && !op.pinned && op.value != nil {
+			t.Errorf("The value of the operator %d has not been released after the backward.", op.id)
+		}
+	}
+}
+
+func TestGraph_CheckpointRestore(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1, 2, 3}), true)
+	var inner Node
+	y := g.Checkpoint(func() []Node {
+		inner = g.Square(x)
+		return []Node{g.ReduceSum(inner)}
+	})[0]
+	if inner.(*operator).value != nil {
+		t.Fatal("The value of the inner operator should have been released.")
+	}
+	if !floats.EqualApprox(inner.Value().Data(), []float64{1, 4, 9}, 1.0e-12) {
+		t.Errorf("The restored value doesn't match the expected value.")
+	}
+	if !inner.(*operator).pinned {
+		t.Errorf("The restored value should be kept.")
+	}
+	if y.ScalarValue() != 14 {
+		t.Errorf("The output doesn't match the expected value.")
+	}
+}
+
+func TestGraph_CheckpointReleaseFunctions(t *testing.T) {
+	build := func(g *Graph, checkpoint bool) (p affineParams, y Node) {
+		p = newAffineParams(g)
+		step := func() []Node {
+			return []Node{g.Prod(g.Affine(OpTanh, p.nodes()...), g.Affine(OpSigmoid, p.nodes()...))}
+		}
+		if checkpoint {
+			return p, g.Checkpoint(step)[0]
+		}
+		return p, step()[0]
+	}
+	g1 := NewGraph()
+	p1, y1 := build(g1, false)
+	g2 := NewGraph()
+	p2, y2 := build(g2, true)
+
+	released := func() int {
+		n := 0
+		for _, node := range g2.nodes {
+			if op, ok := node.(*operator); ok {
+				if f, ok := op.function.(*fn.Affine); ok && reflect.ValueOf(f).Elem().FieldByName("z").IsNil() {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if n := released(); n != 2 {
+		t.Errorf("The pre-activations of the affine transformations should be released, found %d released", n)
+	}
+
+	g1.Backward(y1)
+	g2.Backward(y2)
+	if !floats.EqualApprox(p2.w1.Grad().Data(), p1.w1.Grad().Data(), 1.0e-12) {
+		t.Error("The gradients of w1 don't match the expected values.")
+	}
+	if n := released(); n != 2 {
+		t.Errorf("The pre-activations should be released again after the backward, found %d released", n)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"sort"
+	"sync"
+)
+
+// CompiledGraph is a traced portion of a graph that can be executed repeatedly with new input values,
+// without rebuilding the nodes. It is meant for the inference of fixed-shape models.
+type CompiledGraph struct {
+	// to avoid concurrent runs
+	mu      sync.Mutex
+	graph   *Graph
+	inputs  map[string]*variable
+	outputs map[string]Node
+	// dims contains the dimensions of the traced inputs
+	dims map[string][]int
+	// plan contains the operators computing the outputs, in topological order
+	plan []*operator
+	// release contains, for each step of the plan, the operators whose values are no longer needed after it
+	release [][]*operator
+}
+
+// Compile traces the operators computing the outputs from the inputs, which must be variables
+// (e.g. the placeholders given to the Forward of a processor). The graph must not be cleared afterwards.
+//
+// During each run only the operators the outputs depend on are recomputed, in topological order, and the value of
+// each intermediate operator is released as soon as its last user has been computed, so that its buffer is reused
+// by the following operators (see mat.GetDenseWorkspace) and the memory stays bounded across runs.
+// The operators created in no-grad mode cannot be recomputed, so they are treated as constants: the graph must be
+// traced outside of no-grad mode. The processors should be traced in inference mode (e.g. without dropout).
+func (g *Graph) Compile(inputs map[string]Node, outputs map[string]Node) *CompiledGraph {
+	c := &CompiledGraph{
+		graph:   g,
+		inputs:  make(map[string]*variable, len(inputs)),
+		outputs: outputs,
+		dims:    make(map[string][]int, len(inputs)),
+	}
+	for name, x := range inputs {
+		v, ok := x.(*variable)
+		if !ok || x.Graph() != g {
+			panic(fmt.Sprintf("ag: the input %q must be a variable of the graph", name))
+		}
+		c.inputs[name] = v
+		c.dims[name] = []int{v.value.Rows(), v.value.Columns()}
+	}
+
+	visited := make(map[*operator]bool)
+	var visit func(node Node)
+	visit = func(node Node) {
+		op, ok := node.(*operator)
+		if !ok || visited[op] || op.function == nil {
+			return
+		}
+		visited[op] = true
+		c.plan = append(c.plan, op)
+		for _, x := range op.operands {
+			visit(x)
+		}
+	}
+	isOutput := make(map[*operator]bool)
+	for _, y := range outputs {
+		if y.Graph() != g {
+			panic(ErrGraphMismatch)
+		}
+		if op, ok := y.(*operator); ok {
+			isOutput[op] = true
+		}
+		visit(y)
+	}
+	sort.Slice(c.plan, func(i, j int) bool { return c.plan[i].id < c.plan[j].id })
+
+	lastUse := make(map[*operator]int, len(c.plan))
+	for i, op := range c.plan {
+		for _, x := range op.operands {
+			if x, ok := x.(*operator); ok && visited[x] {
+				lastUse[x] = i
+			}
+		}
+	}
+	c.release = make([][]*operator, len(c.plan))
+	for op, i := range lastUse {
+		if !isOutput[op] {
+			c.release[i] = append(c.release[i], op)
+		}
+	}
+	return c
+}
+
+// Run sets the values of the inputs and computes the outputs. The inputs must have the same dimensions of the
+// traced ones; it panics with a *mat.ShapeError otherwise.
+// The input matrices are used as they are, without copying them. The returned outputs belong to the compiled graph
+// and are valid until the next run: make a copy of them to use them afterwards.
+func (c *CompiledGraph) Run(inputs map[string]mat.Matrix) map[string]mat.Matrix {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, x := range c.inputs {
+		value, ok := inputs[name]
+		if !ok {
+			panic(fmt.Sprintf("ag: missing input %q", name))
+		}
+		if value.Rows() != c.dims[name][0] || value.Columns() != c.dims[name][1] {
+			panic(&mat.ShapeError{Op: "Run", Shapes: [][]int{c.dims[name], {value.Rows(), value.Columns()}}})
+		}
+		x.value = value
+	}
+	if len(inputs) != len(c.inputs) {
+		for name := range inputs {
+			if _, ok := c.inputs[name]; !ok {
+				panic(fmt.Sprintf("ag: unknown input %q", name))
+			}
+		}
+	}
+
+	for _, op := range c.plan {
+		c.graph.releaseValue(op) // the values of the previous run
+	}
+	for i, op := range c.plan {
+		op.value = c.graph.forward(op.opName, op.function, op.operands)
+		if c.graph.checkNumerics {
+			c.graph.checkForward(op, op.function, op.operands)
+		}
+		for _, x := range c.release[i] {
+			c.graph.releaseValue(x)
+		}
+	}
+
+	out := make(map[string]mat.Matrix, len(c.outputs))
+	for name, y := range c.outputs {
+		out[name] = y.Value()
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func newTestMLP(g *Graph, x Node) (h, y Node) {
+	w1 := g.NewVariable(mat.NewDense(3, 2, []float64{0.1, -0.2, 0.3, 0.4, -0.5, 0.6}), true)
+	b1 := g.NewVariable(mat.NewVecDense([]float64{0.1, 0.0, -0.1}), true)
+	w2 := g.NewVariable(mat.NewDense(2, 3, []float64{0.7, -0.8, 0.9, -0.1, 0.2, -0.3}), true)
+	h = g.Tanh(g.Add(g.Mul(w1, x), b1))
+	y = g.Softmax(g.Mul(w2, h))
+	return
+}
+
+func TestGraph_Compile(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewEmptyVecDense(2), false)
+	h, y := newTestMLP(g, x)
+	unused := g.Exp(h)
+	c := g.Compile(map[string]Node{"x": x}, map[string]Node{"h": h, "y": y})
+
+	for _, input := range [][]float64{{0.5, -1.0}, {2.0, 0.3}, {-0.7, 0.1}} {
+		out := c.Run(map[string]mat.Matrix{"x": mat.NewVecDense(input)})
+
+		g2 := NewGraph()
+		h2, y2 := newTestMLP(g2, g2.NewVariable(mat.NewVecDense(input), false))
+		if !floats.EqualApprox(out["y"].Data(), y2.Value().Data(), 1.0e-12) {
+			t.Errorf("The output y doesn't match the expected values.")
+		}
+		if !floats.EqualApprox(out["h"].Data(), h2.Value().Data(), 1.0e-12) {
+			t.Errorf("The output h doesn't match the expected values.")
+		}
+	}
+
+	if len(c.plan) != 5 {
+		t.Errorf("Expected 5 operators in the plan, found %d.", len(c.plan))
+	}
+	for _, op := range c.plan {
+		if op != h && op != y && op.value != nil {
+			t.Errorf("The value of the intermediate operator %d has not been released.", op.id)
+		}
+	}
+	if unused.(*operator).value == nil {
+		t.Errorf("The operators the outputs don't depend on should not be touched.")
+	}
+}
+
+func TestCompiledGraph_InvalidInputs(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewEmptyVecDense(2), false)
+	_, y := newTestMLP(g, x)
+	c := g.Compile(map[string]Node{"x": x}, map[string]Node{"y": y})
+
+	err := Try(func() {
+		c.Run(map[string]mat.Matrix{"x": mat.NewEmptyVecDense(3)})
+	})
+	if !errors.Is(err, mat.ErrShapeMismatch) {
+		t.Errorf("Expected a shape mismatch, found %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Running without the inputs should panic.")
+			}
+		}()
+		c.Run(map[string]mat.Matrix{})
+	}()
+}
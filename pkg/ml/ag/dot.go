@@ -0,0 +1,141 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"io"
+	"math"
+	"reflect"
+)
+
+type dotConfig struct {
+	highlightRequiresGrad bool
+	highlightNaN          bool
+}
+
+type DOTOption func(*dotConfig)
+
+// HighlightRequiresGrad fills the nodes that require gradients.
+func HighlightRequiresGrad() DOTOption {
+	return func(c *dotConfig) {
+		c.highlightRequiresGrad = true
+	}
+}
+
+// HighlightNaN fills the nodes whose value or gradients contain NaN or infinite numbers.
+func HighlightNaN() DOTOption {
+	return func(c *dotConfig) {
+		c.highlightNaN = true
+	}
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language.
+// Each node is labeled with its id, its kind (the OpName for the operators created by the graph methods or by
+// Invoke, the function type for those created by NewOperator, the type of the wrapped value for the wrappers),
+// the dimensions of its value and its time-step. The edges go from the operands to the operators using them.
+func (g *Graph) WriteDOT(w io.Writer, opts ...DOTOption) error {
+	config := &dotConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var b bytes.Buffer
+	b.WriteString("digraph G {\n")
+	b.WriteString("\tnode [fontname=\"Helvetica\"];\n")
+	for _, node := range g.nodes {
+		kind, shape := dotKind(node)
+		value := dotValue(node)
+		fmt.Fprintf(&b, "\tn%d [label=\"#%d %s\\n%s\\nt=%d\", shape=%s", node.Id(), node.Id(), kind,
+			dotDims(value), node.getTimeStep(), shape)
+		switch {
+		case config.highlightNaN && (hasNaN(value) || hasNaN(node.Grad())):
+			b.WriteString(", style=filled, fillcolor=\"#ff8080\"")
+		case config.highlightRequiresGrad && node.RequiresGrad():
+			b.WriteString(", style=filled, fillcolor=\"#a0d0ff\"")
+		}
+		b.WriteString("];\n")
+	}
+	for _, node := range g.nodes {
+		if op, ok := node.(*operator); ok {
+			for _, x := range op.operands {
+				fmt.Fprintf(&b, "\tn%d -> n%d;\n", x.Id(), op.id)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// dotKind returns the description and the DOT shape of the node.
+func dotKind(node Node) (kind, shape string) {
+	switch node := node.(type) {
+	case *variable:
+		return "variable", "ellipse"
+	case *wrapper:
+		return fmt.Sprintf("wrapper (%s)", typeName(node.GradValue)), "ellipse"
+	case *operator:
+		switch {
+		case node.opName != opUnknown && node.function == nil:
+			return fmt.Sprintf("%s (no-grad)", node.opName), "box"
+		case node.function == nil:
+			return "no-grad", "box"
+		default:
+			return operatorName(node.opName, node.function), "box"
+		}
+	default:
+		return typeName(node), "ellipse"
+	}
+}
+
+// operatorName returns the name of the operator created by op, if known, or the name of the type of its function.
+func operatorName(op OpName, f fn.Function) string {
+	if op != opUnknown {
+		return op.String()
+	}
+	return typeName(f)
+}
+
+// typeName returns the name of the type of i, without the package and the pointer indirection.
+func typeName(i interface{}) string {
+	t := reflect.TypeOf(i)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// dotValue returns the value of the node, without recomputing the values released by a checkpoint.
+func dotValue(node Node) mat.Matrix {
+	if op, ok := node.(*operator); ok {
+		return op.value
+	}
+	return node.Value()
+}
+
+func dotDims(m mat.Matrix) string {
+	if m == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d×%d", m.Rows(), m.Columns())
+}
+
+// hasNaN reports whether the matrix contains NaN or infinite values.
+func hasNaN(m mat.Matrix) bool {
+	if m == nil {
+		return false
+	}
+	for _, v := range m.Data() {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGraph_WriteDOT(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1.0, 2.0}), true)
+	w := g.NewVariable(mat.NewDense(1, 2, []float64{0.5, math.NaN()}), false)
+	g.IncTimeStep()
+	g.Tanh(g.Mul(w, x))
+
+	var b strings.Builder
+	if err := g.WriteDOT(&b, HighlightRequiresGrad(), HighlightNaN()); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, expected := range []string{
+		"digraph G {",
+		`n0 [label="#0 variable\n2×1\nt=0", shape=ellipse, style=filled, fillcolor="#a0d0ff"];`,
+		`n1 [label="#1 variable\n1×2\nt=0", shape=ellipse, style=filled, fillcolor="#ff8080"];`,
+		`n2 [label="#2 Mul\n1×1\nt=1", shape=box, style=filled, fillcolor="#ff8080"];`,
+		`n3 [label="#3 Tanh\n1×1\nt=1", shape=box`,
+		"n1 -> n2;",
+		"n0 -> n2;",
+		"n2 -> n3;",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("The output doesn't contain %q", expected)
+		}
+	}
+}
+
+func TestGraph_WriteDOT_Labels(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1.0, 2.0}), true)
+	g.Invoke(opTestCube, x)
+	g.NewOperator(fn.NewSigmoid(x), x)
+	g.WithNoGrad(func() {
+		g.ReLU(x)
+	})
+
+	var b strings.Builder
+	if err := g.WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, expected := range []string{
+		`n1 [label="#1 TestCube\n2×1\nt=0", shape=box`,
+		`n2 [label="#2 UnaryElementwise\n2×1\nt=0", shape=box`,
+		`n3 [label="#3 ReLU (no-grad)\n2×1\nt=0", shape=box`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("The output doesn't contain %q", expected)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"io"
+)
+
+// dumpMagic identifies the format written by Dump.
+const dumpMagic = "spago/ag.Graph/2"
+
+const (
+	dumpVariable byte = iota
+	dumpOperator
+)
+
+// Dump writes the nodes of the graph into w, so that it can be inspected or replayed offline (see LoadGraph):
+// for each node its kind, time-step and value (see mat.MarshalBinaryTo), and, for the operators, the name of the
+// operator (see OpName.MarshalJSON) and the function with its operands and the matrices kept for the backward. If withGrads is true, the gradients are written too.
+//
+// The wrappers (e.g. the parameters of a model) are written as variables, with their values and gradients.
+// Only the functions of the fn package can be written; it returns an error otherwise, e.g. for the functions
+// given to NewOperator or returned by the factories of the custom operators (see RegisterOp), unless they are
+// functions of the fn package.
+// The hooks and the checkpoints are not written; the custom operators must be registered before loading the graph.
+// The values released by a checkpoint are written as absent rather than recomputed (see Checkpoint): call
+// ForwardAll on the loaded graph to compute them again.
+func (g *Graph) Dump(w io.Writer, withGrads bool) error {
+	g.mu.Lock()
+	nodes := g.nodes[:len(g.nodes):len(g.nodes)]
+	timeStep := g.curTimeStep
+	g.mu.Unlock()
+	bw := bufio.NewWriter(w)
+	e := &dumpEncoder{w: bw}
+	e.writeString(dumpMagic)
+	e.writeInt(timeStep)
+	e.writeInt(int64(len(nodes)))
+	for _, node := range nodes {
+		e.writeNode(node, withGrads)
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+// LoadGraph reads a graph written by Dump. The options are applied to the new graph (e.g. Rand, Profile or
+// CheckNumerics), so that ForwardAll and Backward can be re-run on it.
+// The loaded variables require gradients as the original nodes did.
+func LoadGraph(r io.Reader, opts ...GraphOption) (*Graph, error) {
+	g := NewGraph(opts...)
+	d := &dumpDecoder{r: bufio.NewReader(r), g: g}
+	if magic := d.readString(len(dumpMagic)); d.err == nil && magic != dumpMagic {
+		return nil, errors.New("ag: invalid graph dump")
+	}
+	g.curTimeStep = d.readInt()
+	n := d.readInt()
+	for i := int64(0); i < n && d.err == nil; i++ {
+		d.readNode()
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return g, nil
+}
+
+type dumpEncoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *dumpEncoder) writeNode(node Node, withGrads bool) {
+	op, isOperator := node.(*operator)
+	if isOperator {
+		e.write([]byte{dumpOperator})
+	} else {
+		e.write([]byte{dumpVariable})
+	}
+	e.writeInt(node.getTimeStep())
+	e.writeBool(node.RequiresGrad())
+	if isOperator {
+		e.writeMatrix(op.value) // not restored if released by a checkpoint
+	} else {
+		e.writeMatrix(node.Value())
+	}
+	if withGrads && node.HasGrad() {
+		e.writeMatrix(node.Grad())
+	} else {
+		e.writeMatrix(nil)
+	}
+	if !isOperator {
+		return
+	}
+	e.writeOpName(op.opName)
+	e.writeBool(op.function != nil) // operators created in no-grad mode
+	if op.function == nil || e.err != nil {
+		return
+	}
+	e.writeInt(int64(len(op.operands)))
+	for _, x := range op.operands {
+		e.writeInt(x.Id())
+	}
+	e.fail(fn.Encode(e.w, op.function, func(x fn.Operand) int64 {
+		if x, ok := x.(Node); ok && x.Graph() == op.graph {
+			return x.Id()
+		}
+		e.fail(fmt.Errorf("ag: the function of node %d has an operand not belonging to the graph", op.id))
+		return -1
+	}))
+}
+
+func (e *dumpEncoder) writeMatrix(m mat.Matrix) {
+	e.writeBool(m != nil)
+	if m != nil && e.err == nil {
+		_, e.err = mat.MarshalBinaryTo(m, e.w)
+	}
+}
+
+// writeOpName writes the name of the operator as encoded in JSON, i.e. a number for the built-in operators and
+// a string for the custom ones; the operators created with NewOperator are written as -1.
+func (e *dumpEncoder) writeOpName(op OpName) {
+	if op == opUnknown {
+		e.writeString("-1")
+		return
+	}
+	data, err := op.MarshalJSON()
+	if err != nil {
+		e.fail(err)
+		return
+	}
+	e.writeString(string(data))
+}
+
+func (e *dumpEncoder) writeString(s string) {
+	e.writeInt(int64(len(s)))
+	e.write([]byte(s))
+}
+
+func (e *dumpEncoder) writeBool(b bool) {
+	if b {
+		e.write([]byte{1})
+	} else {
+		e.write([]byte{0})
+	}
+}
+
+func (e *dumpEncoder) writeInt(n int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(n))
+	e.write(b[:])
+}
+
+func (e *dumpEncoder) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+func (e *dumpEncoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+type dumpDecoder struct {
+	r   io.Reader
+	g   *Graph
+	err error
+}
+
+func (d *dumpDecoder) readNode() {
+	var kind [1]byte
+	d.read(kind[:])
+	timeStep := d.readInt()
+	requiresGrad := d.readBool()
+	value := d.readMatrix(false)
+	grad := d.readMatrix(true)
+	if d.err != nil {
+		return
+	}
+	id := d.g.newId()
+	switch kind[0] {
+	case dumpVariable:
+		d.g.nodes = append(d.g.nodes, &variable{
+			graph:        d.g,
+			timeStep:     timeStep,
+			id:           id,
+			value:        value,
+			grad:         grad,
+			hasGrad:      grad != nil,
+			requiresGrad: requiresGrad,
+		})
+	case dumpOperator:
+		if value != nil {
+			value = toWorkspace(value) // the values of the operators are released by the graph
+		}
+		op := &operator{
+			graph:        d.g,
+			timeStep:     timeStep,
+			id:           id,
+			opName:       d.readOpName(),
+			value:        value,
+			grad:         grad,
+			hasGrad:      grad != nil,
+			requiresGrad: requiresGrad,
+		}
+		d.g.nodes = append(d.g.nodes, op)
+		if d.readBool() && d.err == nil {
+			d.readFunction(op)
+		}
+	default:
+		d.fail(fmt.Errorf("ag: invalid kind of node %d", id))
+	}
+}
+
+func (d *dumpDecoder) readFunction(op *operator) {
+	n := d.readInt()
+	if d.err == nil && (n < 0 || n > op.id) {
+		d.fail(fmt.Errorf("ag: invalid number of operands of node %d", op.id))
+	}
+	if d.err != nil {
+		return
+	}
+	op.operands = make([]Node, n)
+	for i := range op.operands {
+		x, err := d.node(d.readInt(), op.id)
+		if err != nil {
+			d.fail(err)
+			return
+		}
+		op.operands[i] = x
+	}
+	f, err := fn.Decode(d.r, func(id int64) (fn.Operand, error) { return d.node(id, op.id) }, d.g.randGen)
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	op.function = f
+}
+
+// node returns the node with the given id, which must precede the operator.
+func (d *dumpDecoder) node(id, operatorId int64) (Node, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if id < 0 || id >= operatorId {
+		return nil, fmt.Errorf("ag: invalid operand %d of node %d", id, operatorId)
+	}
+	return d.g.nodes[id], nil
+}
+
+// readMatrix reads a matrix, if present. The gradients are copied into the workspace, since they are released
+// by the graph (see ZeroGrad).
+func (d *dumpDecoder) readMatrix(workspace bool) mat.Matrix {
+	if !d.readBool() || d.err != nil {
+		return nil
+	}
+	m, _, err := mat.NewUnmarshalBinaryFrom(d.r)
+	if err != nil {
+		d.fail(err)
+		return nil
+	}
+	if workspace {
+		return toWorkspace(m)
+	}
+	return m
+}
+
+func toWorkspace(m mat.Matrix) mat.Matrix {
+	w := mat.GetDenseWorkspace(m.Dims())
+	w.SetData(m.Data())
+	return w
+}
+
+// maxOpNameLen is the maximum length of the name of an operator in a dump.
+const maxOpNameLen = 1024
+
+func (d *dumpDecoder) readOpName() OpName {
+	data := d.readString(maxOpNameLen)
+	if d.err != nil {
+		return opUnknown
+	}
+	var op OpName
+	if err := op.UnmarshalJSON([]byte(data)); err != nil {
+		d.fail(err)
+		return opUnknown
+	}
+	return op
+}
+
+// readString reads a string of at most max bytes.
+func (d *dumpDecoder) readString(max int) string {
+	n := d.readInt()
+	if d.err == nil && (n < 0 || n > int64(max)) {
+		d.fail(errors.New("ag: invalid graph dump"))
+	}
+	if d.err != nil {
+		return ""
+	}
+	b := make([]byte, n)
+	d.read(b)
+	return string(b)
+}
+
+func (d *dumpDecoder) readBool() bool {
+	var b [1]byte
+	d.read(b[:])
+	return b[0] != 0
+}
+
+func (d *dumpDecoder) readInt() int64 {
+	var b [8]byte
+	d.read(b[:])
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+func (d *dumpDecoder) read(b []byte) {
+	if d.err != nil {
+		return
+	}
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		d.err = err
+	}
+}
+
+func (d *dumpDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"bytes"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+	"time"
+)
+
+func TestGraph_DumpAndLoad(t *testing.T) {
+	g := NewGraph()
+	p := newAffineParams(g)
+	h := g.Affine(OpTanh, p.nodes()...)
+	g.IncTimeStep()
+	var y Node
+	g.WithNoGrad(func() {
+		y = g.ReduceSum(g.Dropout(h, 0.0))
+	})
+	y = g.Add(g.ReduceSum(g.Softmax(g.Pow(h, 2.0))), y)
+	g.Backward(y)
+
+	var buf bytes.Buffer
+	if err := g.Dump(&buf, true); err != nil {
+		t.Fatal(err)
+	}
+	g2, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g2.nodes) != len(g.nodes) || g2.TimeStep() != 1 {
+		t.Fatalf("The loaded graph doesn't match the dumped one.")
+	}
+	for i, node := range g.nodes {
+		node2 := g2.nodes[i]
+		if node2.getTimeStep() != node.getTimeStep() || node2.RequiresGrad() != node.RequiresGrad() {
+			t.Errorf("The node %d doesn't match the dumped one.", i)
+		}
+		if !floats.Equal(node2.Value().Data(), node.Value().Data()) {
+			t.Errorf("The value of the node %d doesn't match the dumped one.", i)
+		}
+		if node.HasGrad() && !floats.Equal(node2.Grad().Data(), node.Grad().Data()) {
+			t.Errorf("The gradients of the node %d don't match the dumped ones.", i)
+		}
+	}
+
+	// replay with new inputs
+	x := mat.NewDense(2, 2, []float64{0.1, 0.2, -0.3, 0.4})
+	for _, g := range []*Graph{g, g2} {
+		g.ReplaceValue(g.nodes[p.x1.Id()], x.Clone())
+		g.ZeroGrad()
+		g.ForwardAll()
+		g.Backward(g.nodes[y.Id()])
+	}
+	if !g2.nodes[p.w1.Id()].HasGrad() {
+		t.Fatalf("The loaded graph should back-propagate the gradients.")
+	}
+	for i, node := range g.nodes {
+		if !floats.EqualApprox(g2.nodes[i].Value().Data(), node.Value().Data(), 1.0e-12) {
+			t.Errorf("The recomputed value of the node %d doesn't match the expected one.", i)
+		}
+		if node.HasGrad() != g2.nodes[i].HasGrad() {
+			t.Errorf("The recomputed gradients of the node %d don't match the expected ones.", i)
+		} else if node.HasGrad() && !floats.EqualApprox(g2.nodes[i].Grad().Data(), node.Grad().Data(), 1.0e-12) {
+			t.Errorf("The recomputed gradients of the node %d don't match the expected ones.", i)
+		}
+	}
+}
+
+func TestGraph_DumpWithoutGrads(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1, 2}), true)
+	g.Backward(g.ReduceSum(g.Sigmoid(x)))
+
+	var buf bytes.Buffer
+	if err := g.Dump(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	g2, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range g2.nodes {
+		if node.HasGrad() {
+			t.Errorf("The gradients should not have been dumped.")
+		}
+	}
+}
+
+func TestGraph_DumpUnknownFunction(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1, 2}), true)
+	g.NewOperator(&failingBackward{x: x}, x)
+	if err := g.Dump(&bytes.Buffer{}, false); err == nil {
+		t.Errorf("Dumping a function not belonging to the fn package should fail.")
+	}
+	if _, err := LoadGraph(bytes.NewReader([]byte("invalid"))); err == nil {
+		t.Errorf("Loading an invalid dump should fail.")
+	}
+}
+
+func TestGraph_DumpCheckpoint(t *testing.T) {
+	g := NewGraph()
+	_, _, hs := rnnChain(g, true)
+
+	var buf bytes.Buffer
+	done := make(chan error)
+	go func() {
+		done <- g.Dump(&buf, false)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("The dump of a graph with checkpoints should not deadlock.")
+	}
+	for _, node := range g.nodes {
+		if op, ok := node.(*operator); ok && !op.pinned && op.value != nil {
+			t.Fatal("The dump should not restore the released values.")
+		}
+	}
+
+	g2, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	released := 0
+	for _, node := range g2.nodes {
+		if node.Value() == nil {
+			released++
+		}
+	}
+	if released != 2*len(hs) {
+		t.Errorf("Expected %d absent values, found %d.", 2*len(hs), released)
+	}
+	g2.ForwardAll()
+	for i, h := range hs {
+		if !floats.EqualApprox(g2.nodes[h.Id()].Value().Data(), h.Value().Data(), 1.0e-12) {
+			t.Errorf("The recomputed output of the step %d doesn't match the expected value.", i)
+		}
+	}
+}
+
+func TestGraph_DumpOpNames(t *testing.T) {
+	g := NewGraph()
+	p := newAffineParams(g)
+	h := g.Affine(OpTanh, p.nodes()...)
+	h = g.Add(g.IndexSelect(g.Tanh(h), 2, 0, 0), g.MaskedFill(g.Sqrt(g.Square(h)), mat.NewDense(3, 2, []float64{0, 1, 0, 0, 1, 0}), 0.0))
+	y := g.ReduceSum(g.NewOperator(fn.NewSquare(h), h))
+
+	var buf bytes.Buffer
+	if err := g.Dump(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	g2, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, node := range g.nodes {
+		if op, ok := node.(*operator); ok && g2.nodes[i].(*operator).opName != op.opName {
+			t.Errorf("The name of the operator %d doesn't match the dumped one.", i)
+		}
+	}
+
+	// the higher-order rules of the loaded operators
+	xs := []Node{p.w1, p.x2}
+	xs2 := []Node{g2.nodes[p.w1.Id()], g2.nodes[p.x2.Id()]}
+	gs := g.Gradients(y, xs)
+	gs2 := g2.Gradients(g2.nodes[y.Id()], xs2)
+	for i := range gs {
+		if !floats.EqualApprox(gs2[i].Value().Data(), gs[i].Value().Data(), 1.0e-12) {
+			t.Errorf("The gradients of the node %d don't match the expected ones.", i)
+		}
+	}
+}
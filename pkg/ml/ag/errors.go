@@ -0,0 +1,34 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+// ErrGraphMismatch is raised by the operations among nodes of different graphs.
+var ErrGraphMismatch = errors.New("ag: operations cannot be executed among nodes of different graphs. " +
+	"You may consider wrapping the nodes you need with NewWrap()")
+
+// Try executes the callback, e.g. the forward of a model, and returns the error raised within it instead of
+// terminating the process. The typed errors can be inspected with errors.Is and errors.As, for example:
+//
+//	err := ag.Try(func() {
+//		y = proc.Forward(x)[0]
+//	})
+//	var shapeErr *mat.ShapeError
+//	if errors.As(err, &shapeErr) {
+//		log.Printf("invalid input dimensions: %v", shapeErr.Shapes)
+//	}
+//
+// Only the panics raised with an error (e.g. mat.ErrShapeMismatch, mat.ErrIndexOutOfRange, ErrGraphMismatch)
+// are recovered. The panics raised by other goroutines, e.g. the ones of the concurrent processors, cannot be
+// recovered, so the concurrency should be disabled within the callback.
+func Try(callback func()) (err error) {
+	defer mat.Recover(&err)
+	callback()
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"testing"
+)
+
+func TestTry(t *testing.T) {
+	g := NewGraph()
+	w := g.NewVariable(mat.NewEmptyDense(3, 4), true)
+	x := g.NewVariable(mat.NewEmptyVecDense(5), false)
+	err := Try(func() {
+		g.Mul(w, x)
+	})
+	var shapeErr *mat.ShapeError
+	if !errors.As(err, &shapeErr) || !errors.Is(err, mat.ErrShapeMismatch) {
+		t.Fatalf("Expected a shape mismatch, found %v", err)
+	}
+	if shapeErr.Shapes[0][1] != 4 || shapeErr.Shapes[1][0] != 5 {
+		t.Errorf("The error doesn't report the offending dimensions: %v", err)
+	}
+
+	other := NewGraph().NewVariable(mat.NewEmptyVecDense(4), false)
+	if err := Try(func() { g.Mul(w, other) }); err != ErrGraphMismatch {
+		t.Errorf("Expected ErrGraphMismatch, found %v", err)
+	}
+	if err := Try(func() { g.Mul(w, g.NewVariable(mat.NewEmptyVecDense(4), false)) }); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestTry_IndexError(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewEmptyDense(3, 4), true)
+	for _, f := range []func(){
+		func() { g.RowView(x, 5) },
+		func() { g.RowView(x, -1) },
+		func() { g.ColView(x, 4) },
+	} {
+		if err := Try(f); !errors.Is(err, mat.ErrIndexOutOfRange) {
+			t.Errorf("Expected ErrIndexOutOfRange, found %v", err)
+		}
+	}
+	y := g.ReduceSum(x)
+	if err := Try(func() { y.(*operator).function.Backward(mat.NewEmptyVecDense(2)) }); !errors.Is(err, mat.ErrShapeMismatch) {
+		t.Errorf("Expected ErrShapeMismatch, found %v", err)
+	}
+}
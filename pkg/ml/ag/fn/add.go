@@ -8,7 +8,7 @@ import "github.com/nlpodyssey/spago/pkg/mat"
 
 var _ Function = &Add{}
 
-// Element-wise sum over two values.
+// Element-wise sum over two values, with broadcasting.
 // y = x1 + x2
 type Add struct {
 	x1 Operand
@@ -23,23 +23,23 @@ func NewAdd(x1, x2 Operand) *Add {
 func (r *Add) Forward() mat.Matrix {
 	x1v := r.x1.Value()
 	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, x2v) || mat.VectorsOfSameSize(x1v, x2v)) {
-		panic("fn: matrices with not compatible size")
-	}
-	return x1v.Add(x2v)
+	x1b, x2b := broadcast(x1v, x2v)
+	defer releaseBroadcast(x1b, x1v)
+	defer releaseBroadcast(x2b, x2v)
+	return x1b.Add(x2b)
 }
 
 func (r *Add) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, gy) || mat.VectorsOfSameSize(x1v, gy)) &&
-		!(mat.SameDims(x2v, gy) || mat.VectorsOfSameSize(x2v, gy)) {
-		panic("fn: matrices with not compatible size")
-	}
+	checkBroadcastGrad(r.x1.Value(), r.x2.Value(), gy)
 	if r.x1.RequiresGrad() {
-		r.x1.PropagateGrad(gy)
+		propagateBroadcastGrad(r.x1, gy)
 	}
 	if r.x2.RequiresGrad() {
-		r.x2.PropagateGrad(gy)
+		propagateBroadcastGrad(r.x2, gy)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Add) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewAdd(tangentOf(r.x1, tangent), tangentOf(r.x2, tangent)).Forward()
+}
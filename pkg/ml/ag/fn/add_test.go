@@ -39,3 +39,49 @@ func TestAdd_Forward(t *testing.T) {
 		t.Error("The x2-gradients don't match the expected values")
 	}
 }
+
+func TestAdd_ForwardBatch(t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewVecDense([]float64{0.1, 0.2, 0.3}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value: mat.NewDense(3, 2, []float64{
+			0.4, 0.3,
+			0.5, 0.7,
+			-0.1, 0.0,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewAdd(x1, x2)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		0.5, 0.4,
+		0.7, 0.9,
+		0.2, 0.3,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(3, 2, []float64{
+		-1.0, 0.5,
+		0.8, 0.0,
+		0.1, 0.2,
+	}))
+
+	if !floats.EqualApprox(x1.grad.Data(), []float64{-0.5, 0.8, 0.3}, 1.0e-6) {
+		t.Error("The x1-gradients don't match the expected values")
+	}
+
+	if !floats.EqualApprox(x2.grad.Data(), []float64{
+		-1.0, 0.5,
+		0.8, 0.0,
+		0.1, 0.2,
+	}, 1.0e-6) {
+		t.Error("The x2-gradients don't match the expected values")
+	}
+}
@@ -28,7 +28,7 @@ func (r *AddScalar) Forward() mat.Matrix {
 
 func (r *AddScalar) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x1.Value(), gy) || mat.VectorsOfSameSize(r.x1.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("AddScalar", r.x1.Value(), gy))
 	}
 	if r.x1.RequiresGrad() {
 		r.x1.PropagateGrad(gy)
@@ -39,3 +39,8 @@ func (r *AddScalar) Backward(gy mat.Matrix) {
 		r.x2.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *AddScalar) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewAddScalar(tangentOf(r.x1, tangent), tangentOf(r.x2, tangent)).Forward()
+}
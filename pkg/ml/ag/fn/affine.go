@@ -0,0 +1,191 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"reflect"
+)
+
+var _ Releaser = &Affine{}
+
+// Affine is a fused function computing y = f(b + w1·x1 + w2·x2 + ... + wn·xn), where f is an optional element-wise
+// activation and the bias b is broadcast to the dimensions of the products (e.g. over the columns of a mini-batch).
+// Unlike the composition of Mul, Add and the activation, the products are accumulated into a single matrix and
+// the activation is applied to it, so no intermediate matrices are allocated; the backward is performed at once.
+type Affine struct {
+	b  Operand   // the bias, optional
+	xs []Operand // the pairs w, x
+	f  func(i, j int, v float64) float64
+	df func(i, j int, v float64) float64
+	z  mat.Matrix // the pre-activation, kept for the backward if there is an activation
+}
+
+// NewAffine returns a new affine transformation without activation, with the (optional) bias b and the pairs
+// w1, x1, w2, x2, ... It panics if there are no pairs.
+func NewAffine(b Operand, xs ...Operand) *Affine {
+	if len(xs) == 0 || len(xs)%2 != 0 {
+		panic(fmt.Errorf("fn: the affine transformation requires pairs of operands, found %d operands", len(xs)))
+	}
+	return &Affine{b: b, xs: xs}
+}
+
+// Activation is an element-wise activation, i.e. a function along with its derivative, which can be fused in the
+// affine transformation (see NewFusedAffine).
+type Activation struct {
+	f  func(i, j int, v float64) float64
+	df func(i, j int, v float64) float64
+}
+
+// The activations of the constructors of UnaryElementwise with the same name (e.g. NewTanh).
+var (
+	TanhActivation        = Activation{f: tanh, df: tanhDeriv}
+	SigmoidActivation     = Activation{f: sigmoid, df: sigmoidDeriv}
+	HardSigmoidActivation = Activation{f: hardSigmoid, df: hardSigmoidDeriv}
+	HardTanhActivation    = Activation{f: hardTanh, df: hardTanhDeriv}
+	ReLUActivation        = Activation{f: relu, df: reluDeriv}
+	SoftsignActivation    = Activation{f: softsign, df: softsignDeriv}
+	MishActivation        = Activation{f: mish, df: mishDeriv}
+	GELUActivation        = Activation{f: gelu, df: geluDeriv}
+	GELUTanhActivation    = Activation{f: geluTanh, df: geluTanhDeriv}
+)
+
+// NewFusedAffine returns a new affine transformation followed by the element-wise activation (e.g. TanhActivation).
+func NewFusedAffine(activation Activation, b Operand, xs ...Operand) *Affine {
+	r := NewAffine(b, xs...)
+	r.f, r.df = activation.f, activation.df
+	return r
+}
+
+// Activation returns the activation of the affine transformation, if any.
+func (r *Affine) Activation() (activation Activation, ok bool) {
+	return Activation{f: r.f, df: r.df}, r.f != nil
+}
+
+// Equal returns whether the activations are the same, e.g. TanhActivation.
+func (a Activation) Equal(b Activation) bool {
+	return funcPointer(a.f) == funcPointer(b.f) && funcPointer(a.df) == funcPointer(b.df)
+}
+
+func funcPointer(f func(i, j int, v float64) float64) uintptr {
+	return reflect.ValueOf(f).Pointer()
+}
+
+// Forward computes the output of the function.
+func (r *Affine) Forward() mat.Matrix {
+	z := mat.GetEmptyDenseWorkspace(r.xs[0].Value().Rows(), r.xs[1].Value().Columns())
+	for i := 0; i < len(r.xs); i += 2 {
+		z.AddMulInPlace(r.xs[i].Value(), r.xs[i+1].Value())
+	}
+	if r.b != nil {
+		addBroadcastInPlace(z, r.b.Value())
+	}
+	if r.f == nil {
+		return z
+	}
+	if r.z != nil {
+		mat.ReleaseMatrix(r.z) // previous forward
+	}
+	r.z = z
+	y := mat.GetDenseWorkspace(z.Dims())
+	y.Apply(r.f, z)
+	return y
+}
+
+// Release returns the pre-activation kept for the backward to the workspace.
+func (r *Affine) Release() {
+	if r.z != nil {
+		mat.ReleaseMatrix(r.z)
+		r.z = nil
+	}
+}
+
+// Backward computes the backward pass.
+func (r *Affine) Backward(gy mat.Matrix) {
+	if rows, cols := r.xs[0].Value().Rows(), r.xs[1].Value().Columns(); gy.Size() != rows*cols {
+		panic(&mat.ShapeError{Op: "Affine", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {rows, cols}}})
+	}
+	gz := gy
+	if r.f != nil {
+		gz = mat.GetDenseWorkspace(r.z.Dims())
+		defer mat.ReleaseMatrix(gz)
+		gz.Apply(r.df, r.z)
+		gz.ProdInPlace(gy)
+	}
+	if r.b != nil && r.b.RequiresGrad() {
+		propagateBroadcastGrad(r.b, gz)
+	}
+	for i := 0; i < len(r.xs); i += 2 {
+		w, x := r.xs[i], r.xs[i+1]
+		if w.RequiresGrad() {
+			xt := x.Value().T()
+			gw := gz.Mul(xt)
+			w.PropagateGrad(gw)
+			mat.ReleaseMatrix(xt)
+			mat.ReleaseMatrix(gw)
+		}
+		if x.RequiresGrad() {
+			var gx mat.Matrix
+			if wv, ok := w.Value().(*mat.Dense); ok && gz.Columns() == 1 {
+				gx = wv.MulT(gz)
+			} else {
+				wt := w.Value().T()
+				gx = wt.Mul(gz)
+				mat.ReleaseMatrix(wt)
+			}
+			x.PropagateGrad(gx)
+			mat.ReleaseMatrix(gx)
+		}
+	}
+}
+
+// JVP computes the tangent of the output given the tangents of the operands.
+// tz = tb + tw1 x1 + w1 tx1 + ... + twn xn + wn txn
+// ty = f'(z) * tz
+func (r *Affine) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tz := mat.GetEmptyDenseWorkspace(r.xs[0].Value().Rows(), r.xs[1].Value().Columns())
+	for i := 0; i < len(r.xs); i += 2 {
+		w, x := r.xs[i], r.xs[i+1]
+		if tw := tangent(w); tw != nil {
+			tz.AddMulInPlace(tw, x.Value())
+		}
+		if tx := tangent(x); tx != nil {
+			tz.AddMulInPlace(w.Value(), tx)
+		}
+	}
+	if r.b != nil {
+		if tb := tangent(r.b); tb != nil {
+			addBroadcastInPlace(tz, tb)
+		}
+	}
+	if r.f != nil {
+		dz := mat.GetDenseWorkspace(r.z.Dims())
+		defer mat.ReleaseMatrix(dz)
+		dz.Apply(r.df, r.z)
+		tz.ProdInPlace(dz)
+	}
+	return tz
+}
+
+// addBroadcastInPlace adds b to m, broadcasting it to the dimensions of m.
+func addBroadcastInPlace(m *mat.Dense, b mat.Matrix) {
+	if rows, cols := broadcastDims(m, b); rows != m.Rows() || cols != m.Columns() {
+		panic(mat.NewShapeError("Affine", m, b))
+	}
+	if b.Size() == m.Size() {
+		m.AddInPlace(b)
+		return
+	}
+	rows, cols := m.Dims()
+	bRows, bCols := b.Dims()
+	data, bData := m.Data(), b.Data()
+	for i := 0; i < rows; i++ {
+		offset := (i % bRows) * bCols
+		for j := 0; j < cols; j++ {
+			data[i*cols+j] += bData[offset+j%bCols]
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"math"
+	"testing"
+)
+
+func TestAffine_ForwardBroadcastBias(t *testing.T) {
+	b := &variable{
+		value:        mat.NewVecDense([]float64{0.1, -0.2}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	w := &variable{
+		value:        mat.NewDense(2, 3, []float64{0.1, 0.2, 0.3, -0.4, 0.5, -0.6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x := &variable{
+		value:        mat.NewDense(3, 2, []float64{0.5, -0.1, 0.2, 0.3, -0.7, 0.4}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewFusedAffine(ReLUActivation, b, w, x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		0.0, 0.27,
+		0.12, 0.0,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(2, 2, []float64{
+		0.5, -1.0,
+		2.0, 0.3,
+	}))
+
+	if !floats.EqualApprox(b.grad.Data(), []float64{-1.0, 2.0}, 1.0e-6) {
+		t.Error("The b-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(w.grad.Data(), []float64{
+		0.1, -0.3, -0.4,
+		1.0, 0.4, -1.4,
+	}, 1.0e-6) {
+		t.Error("The w-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		-0.8, -0.1,
+		1.0, -0.2,
+		-1.2, -0.3,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestAffine_ForwardInvalidBias(t *testing.T) {
+	b := &variable{value: mat.NewVecDense([]float64{0.1, -0.2, 0.3})}
+	w := &variable{value: mat.NewDense(2, 2, []float64{0.1, 0.2, 0.3, -0.4})}
+	x := &variable{value: mat.NewVecDense([]float64{0.5, -0.1})}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, mat.ErrShapeMismatch) {
+			t.Errorf("Expected a shape mismatch, found %v", err)
+		}
+	}()
+	NewAffine(b, w, x).Forward()
+}
+
+func TestAffine_Release(t *testing.T) {
+	w := &variable{value: mat.NewDense(2, 2, []float64{0.1, 0.2, 0.3, -0.4}), requiresGrad: true}
+	x := &variable{value: mat.NewVecDense([]float64{0.5, -0.1}), requiresGrad: true}
+	f := NewFusedAffine(TanhActivation, nil, w, x)
+	f.Forward()
+	if f.z == nil {
+		t.Fatal("The pre-activation should be kept for the backward")
+	}
+	f.Release()
+	if f.z != nil {
+		t.Error("The pre-activation should be released")
+	}
+	if y := f.Forward(); !floats.EqualApprox(y.Data(), []float64{math.Tanh(0.03), math.Tanh(0.19)}, 1.0e-12) {
+		t.Error("The output doesn't match the expected values")
+	}
+}
@@ -33,3 +33,8 @@ func (r *At) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(dx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *At) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewAt(tangentOf(r.x, tangent), r.i, r.j).Forward()
+}
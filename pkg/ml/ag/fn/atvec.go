@@ -32,3 +32,8 @@ func (r *AtVec) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(dx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *AtVec) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewAtVec(tangentOf(r.x, tangent), r.i).Forward()
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &AvgPooling{}
+
+// AvgPooling takes the mean of each non-overlapping window of rows x cols values of the input, whose dimensions
+// must be multiple of the ones of the window.
+type AvgPooling struct {
+	x    Operand
+	rows int
+	cols int
+}
+
+func NewAvgPooling(x Operand, r, c int) *AvgPooling {
+	return &AvgPooling{x: x, rows: r, cols: c}
+}
+
+// Forward computes the output of the function.
+func (r *AvgPooling) Forward() mat.Matrix {
+	x := r.x.Value()
+	checkPooling("AvgPooling", x, r.rows, r.cols)
+	y := mat.GetEmptyDenseWorkspace(x.Rows()/r.rows, x.Columns()/r.cols)
+	n := float64(r.rows * r.cols)
+	for i := 0; i < x.Rows(); i++ {
+		for j := 0; j < x.Columns(); j++ {
+			row, col := i/r.rows, j/r.cols
+			y.Set(row, col, y.At(row, col)+x.At(i, j)/n)
+		}
+	}
+	return y
+}
+
+// Backward computes the backward pass, dividing the gradient of each window among its values.
+func (r *AvgPooling) Backward(gy mat.Matrix) {
+	x := r.x.Value()
+	if gy.Rows() != x.Rows()/r.rows || gy.Columns() != x.Columns()/r.cols {
+		panic(mat.NewShapeError("AvgPooling", x, gy))
+	}
+	if r.x.RequiresGrad() {
+		gx := mat.GetDenseWorkspace(x.Dims())
+		defer mat.ReleaseDense(gx)
+		n := float64(r.rows * r.cols)
+		for i := 0; i < x.Rows(); i++ {
+			for j := 0; j < x.Columns(); j++ {
+				gx.Set(i, j, gy.At(i/r.rows, j/r.cols)/n)
+			}
+		}
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *AvgPooling) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewAvgPooling(tangentOf(r.x, tangent), r.rows, r.cols).Forward()
+}
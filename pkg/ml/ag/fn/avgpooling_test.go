@@ -0,0 +1,47 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestAvgPooling_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 6, []float64{
+			0.4, 0.1, -0.9, -0.5, 0.3, 0.8,
+			-0.4, 0.3, 0.7, -0.3, 0.2, 0.6,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewAvgPooling(x, 2, 3)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.0333333, 0.1833333}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(1, 2, []float64{0.6, -1.2}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0.1, 0.1, 0.1, -0.2, -0.2, -0.2,
+		0.1, 0.1, 0.1, -0.2, -0.2, -0.2,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestAvgPooling_InvalidWindow(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("AvgPooling should panic if the window doesn't divide the input")
+		}
+	}()
+	x := &variable{value: mat.NewEmptyDense(2, 6)}
+	NewAvgPooling(x, 2, 4).Forward()
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/pkg/mat"
+
+// The element-wise binary functions (Add, Sub, Prod, Div) follow the NumPy broadcasting rules:
+// the dimensions of the operands must be equal, or one of them must be 1, in which case the operand is
+// stretched along it. For example, a column vector is added to each column of a matrix (e.g. the bias of an
+// affine transformation applied on a mini-batch, see mat.Batch), and a row vector to each row of it.
+// As an exception, a row vector and a column vector of the same size are treated as the same vector.
+// During the backward, the gradients of the stretched operands are summed along the broadcast dimensions.
+
+// broadcastDims returns the dimensions of the result of an element-wise operation between a and b.
+// It panics if the matrices cannot be broadcast together.
+func broadcastDims(a, b mat.Matrix) (rows, cols int) {
+	if mat.SameDims(a, b) || mat.VectorsOfSameSize(a, b) {
+		return a.Dims()
+	}
+	rows, ok1 := broadcastDim(a.Rows(), b.Rows())
+	cols, ok2 := broadcastDim(a.Columns(), b.Columns())
+	if !(ok1 && ok2) {
+		panic(mat.NewShapeError("broadcast", a, b))
+	}
+	return rows, cols
+}
+
+func broadcastDim(a, b int) (int, bool) {
+	switch {
+	case a == b || b == 1:
+		return a, true
+	case a == 1:
+		return b, true
+	default:
+		return 0, false
+	}
+}
+
+// broadcast returns the matrices a and b stretched to the same dimensions.
+// A stretched matrix is a new workspace that must be released with releaseBroadcast.
+func broadcast(a, b mat.Matrix) (mat.Matrix, mat.Matrix) {
+	rows, cols := broadcastDims(a, b)
+	return expand(a, rows, cols), expand(b, rows, cols)
+}
+
+// releaseBroadcast releases the matrix b, if it has been stretched from m.
+func releaseBroadcast(b, m mat.Matrix) {
+	if b != m {
+		mat.ReleaseMatrix(b)
+	}
+}
+
+// expand returns a new matrix of size rows×cols repeating the values of m along its dimensions of size 1,
+// or m itself if it doesn't need to be stretched.
+func expand(m mat.Matrix, rows, cols int) mat.Matrix {
+	if m.Size() == rows*cols {
+		return m
+	}
+	mRows, mCols := m.Dims()
+	mData := m.Data()
+	out := mat.GetDenseWorkspace(rows, cols)
+	outData := out.Data()
+	for i := 0; i < rows; i++ {
+		offset := (i % mRows) * mCols
+		for j := 0; j < cols; j++ {
+			outData[i*cols+j] = mData[offset+j%mCols]
+		}
+	}
+	return out
+}
+
+// reduceBroadcast returns the gradients g summed along the dimensions over which a matrix of size rows×cols
+// has been stretched, or g itself if it has not been stretched.
+func reduceBroadcast(g mat.Matrix, rows, cols int) mat.Matrix {
+	if g.Size() == rows*cols {
+		return g
+	}
+	gRows, gCols := g.Dims()
+	gData := g.Data()
+	out := mat.GetEmptyDenseWorkspace(rows, cols)
+	outData := out.Data()
+	for i := 0; i < gRows; i++ {
+		offset := (i % rows) * cols
+		for j := 0; j < gCols; j++ {
+			outData[offset+j%cols] += gData[i*gCols+j]
+		}
+	}
+	return out
+}
+
+// propagateBroadcastGrad propagates the gradients gx to x, reducing them if x has been stretched.
+func propagateBroadcastGrad(x Operand, gx mat.Matrix) {
+	g := reduceBroadcast(gx, x.Value().Rows(), x.Value().Columns())
+	defer releaseBroadcast(g, gx)
+	x.PropagateGrad(g)
+}
+
+// checkBroadcastGrad panics if the gradients gy don't match the result of an operation between x1 and x2.
+func checkBroadcastGrad(x1, x2, gy mat.Matrix) {
+	if rows, cols := broadcastDims(x1, x2); gy.Size() != rows*cols {
+		panic(mat.NewShapeError("broadcast", x1, x2, gy))
+	}
+}
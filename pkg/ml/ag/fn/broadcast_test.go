@@ -0,0 +1,145 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestAdd_ForwardBroadcastRow(t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(1, 3, []float64{10, 20, 30}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewAdd(x1, x2)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{11, 22, 33, 14, 25, 36}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}))
+
+	if !floats.EqualApprox(x1.grad.Data(), []float64{1, 2, 3, 4, 5, 6}, 1.0e-6) {
+		t.Error("The x1-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(x2.grad.Data(), []float64{5, 7, 9}, 1.0e-6) {
+		t.Error("The x2-gradients don't match the expected values")
+	}
+	if x2.grad.Rows() != 1 || x2.grad.Columns() != 3 {
+		t.Error("The rows and columns of the x2-gradients are not correct")
+	}
+}
+
+func TestSub_ForwardBroadcastScalar(t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewScalar(1),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(2, 2, []float64{1, 2, 3, 4}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewSub(x1, x2)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0, -1, -2, -3}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(2, 2, []float64{1, 2, 3, 4}))
+
+	if !floats.EqualApprox(x1.grad.Data(), []float64{10}, 1.0e-6) {
+		t.Error("The x1-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(x2.grad.Data(), []float64{-1, -2, -3, -4}, 1.0e-6) {
+		t.Error("The x2-gradients don't match the expected values")
+	}
+}
+
+func TestProd_ForwardBroadcastScalar(t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewScalar(2),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewProd(x1, x2)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{2, 4, 6, 8, 10, 12}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}))
+
+	if !floats.EqualApprox(x1.grad.Data(), []float64{2, 4, 6, 8, 10, 12}, 1.0e-6) {
+		t.Error("The x1-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(x2.grad.Data(), []float64{91}, 1.0e-6) {
+		t.Error("The x2-gradients don't match the expected values")
+	}
+}
+
+func TestDiv_ForwardBroadcastOuter(t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewVecDense([]float64{2, 4}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(1, 3, []float64{1, 2, 4}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewDiv(x1, x2)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{2, 1, 0.5, 4, 2, 1}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+	if y.Rows() != 2 || y.Columns() != 3 {
+		t.Error("The rows and columns of the resulting matrix are not correct")
+	}
+
+	f.Backward(mat.NewDense(2, 3, []float64{1, 1, 1, 1, 1, 1}))
+
+	if !floats.EqualApprox(x1.grad.Data(), []float64{1.75, 1.75}, 1.0e-6) {
+		t.Error("The x1-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(x2.grad.Data(), []float64{-6, -1.5, -0.375}, 1.0e-6) {
+		t.Error("The x2-gradients don't match the expected values")
+	}
+}
+
+func TestAdd_ForwardBroadcastIncompatible(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("The function should panic with incompatible dimensions")
+		}
+	}()
+	NewAdd(
+		&variable{value: mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})},
+		&variable{value: mat.NewDense(1, 2, []float64{1, 2})},
+	).Forward()
+}
@@ -15,9 +15,6 @@ type ColView struct {
 
 // Extract the i-th column from the input matrix
 func NewColView(x Operand, i int) *ColView {
-	if i < 0 {
-		panic("fn: invalid column index")
-	}
 	return &ColView{x: x, i: i}
 }
 
@@ -25,8 +22,8 @@ func NewColView(x Operand, i int) *ColView {
 func (r *ColView) Forward() mat.Matrix {
 	xv := r.x.Value()
 	rows, cols := xv.Dims()
-	if r.i >= cols {
-		panic("fn: matrix with not compatible size")
+	if r.i < 0 || r.i >= cols {
+		panic(&mat.IndexError{Index: []int{r.i}, Shape: []int{rows, cols}})
 	}
 	y := mat.GetDenseWorkspace(1, rows)
 	for i := 0; i < rows; i++ {
@@ -37,7 +34,7 @@ func (r *ColView) Forward() mat.Matrix {
 
 func (r *ColView) Backward(gy mat.Matrix) {
 	if !(r.x.Value().Rows() == gy.Size()) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("ColView", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.NewEmptyDense(r.x.Value().Dims())
@@ -48,3 +45,8 @@ func (r *ColView) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *ColView) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewColView(tangentOf(r.x, tangent), r.i).Forward()
+}
@@ -10,6 +10,8 @@ import (
 
 var _ Function = &Concat{}
 
+// Concat concatenates the vectors in a single vector.
+// If the operands are mini-batch matrices with the same number of columns, they are concatenated along the rows.
 type Concat struct {
 	xs    []Operand
 	ySize int
@@ -31,22 +33,76 @@ func (r *Concat) Forward() mat.Matrix {
 		ms[i] = value
 		r.ySize += value.Size()
 	}
+	if isBatch(ms) {
+		return concatRows(ms, r.ySize)
+	}
 	return mat.ConcatV(ms...)
 }
 
 func (r *Concat) Backward(gy mat.Matrix) {
 	if r.ySize != gy.Size() {
-		panic("fn: vectors with not compatible size")
+		panic(&mat.ShapeError{Op: "Concat", Shapes: [][]int{{r.ySize, 1}, {gy.Rows(), gy.Columns()}}})
 	}
 	sizes := make([]int, len(r.xs))
 	for i, x := range r.xs {
 		sizes[i] = x.Value().Size()
 	}
 	xs := r.xs
+	if gy.Columns() > 1 {
+		r.backwardRows(gy, sizes)
+		return
+	}
 	for i, gx := range gy.(*mat.Dense).SplitV(sizes...) {
 		if xs[i].RequiresGrad() {
 			xs[i].PropagateGrad(gx)
 		}
-		mat.ReleaseDense(gx.(*mat.Dense))
+		mat.ReleaseMatrix(gx)
 	}
 }
+
+// backwardRows splits the gradients along the rows, propagating each block to the corresponding operand.
+func (r *Concat) backwardRows(gy mat.Matrix, sizes []int) {
+	data := gy.Data()
+	offset := 0
+	for i, x := range r.xs {
+		start := offset
+		offset += sizes[i]
+		if !x.RequiresGrad() {
+			continue
+		}
+		gx := mat.NewDense(x.Value().Rows(), gy.Columns(), data[start:offset])
+		x.PropagateGrad(gx)
+		mat.ReleaseDense(gx)
+	}
+}
+
+// isBatch reports whether the matrices are mini-batches, that is they have more than one column.
+// It panics if the matrices don't have the same number of columns.
+func isBatch(ms []mat.Matrix) bool {
+	cols := ms[0].Columns()
+	if cols == 1 {
+		return false
+	}
+	for _, m := range ms[1:] {
+		if m.Columns() != cols {
+			panic(mat.NewShapeError("Concat", ms[0], m))
+		}
+	}
+	return true
+}
+
+// concatRows concatenates the matrices along the rows.
+// Since the data are stored in row-major order, it is sufficient to join them.
+func concatRows(ms []mat.Matrix, size int) mat.Matrix {
+	data := make([]float64, 0, size)
+	for _, m := range ms {
+		data = append(data, m.Data()...)
+	}
+	cols := ms[0].Columns()
+	return mat.NewDense(size/cols, cols, data)
+}
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Concat) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewConcat(tangentsOf(r.xs, tangent)).Forward()
+}
@@ -48,3 +48,40 @@ func TestConcat_Forward(t *testing.T) {
 		t.Error("The x3-gradients don't match the expected values")
 	}
 }
+
+func TestConcat_ForwardBatch(t *testing.T) {
+	x1 := &variable{
+		value: mat.NewDense(2, 2, []float64{
+			0.1, 0.2,
+			0.3, 0.4,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(1, 2, []float64{0.5, 0.6}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewConcat([]Operand{x1, x2})
+	y := f.Forward()
+
+	if r, c := y.Dims(); r != 3 || c != 2 {
+		t.Error("The output dimensions don't match the expected values")
+	}
+
+	if !floats.EqualApprox(y.Data(), []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(3, 2, []float64{1.0, 2.0, 3.0, 4.0, 5.0, 6.0}))
+
+	if !floats.EqualApprox(x1.grad.Data(), []float64{1.0, 2.0, 3.0, 4.0}, 1.0e-6) {
+		t.Error("The x1-gradients don't match the expected values")
+	}
+
+	if !floats.EqualApprox(x2.grad.Data(), []float64{5.0, 6.0}, 1.0e-6) {
+		t.Error("The x2-gradients don't match the expected values")
+	}
+}
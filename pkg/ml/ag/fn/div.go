@@ -10,7 +10,7 @@ import (
 
 var _ Function = &Div{}
 
-// Element-wise division over two values.
+// Element-wise division over two values, with broadcasting.
 type Div struct {
 	x1 Operand
 	x2 Operand
@@ -24,31 +24,43 @@ func NewDiv(x1, x2 Operand) *Div {
 func (r *Div) Forward() mat.Matrix {
 	x1v := r.x1.Value()
 	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, x2v) || mat.VectorsOfSameSize(x1v, x2v)) {
-		panic("fn: matrices with not compatible size")
-	}
-	return x1v.Div(x2v)
+	x1b, x2b := broadcast(x1v, x2v)
+	defer releaseBroadcast(x1b, x1v)
+	defer releaseBroadcast(x2b, x2v)
+	return x1b.Div(x2b)
 }
 
 func (r *Div) Backward(gy mat.Matrix) {
 	x1v := r.x1.Value()
 	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, gy) || mat.VectorsOfSameSize(x1v, gy)) &&
-		!(mat.SameDims(x2v, gy) || mat.VectorsOfSameSize(x2v, gy)) {
-		panic("fn: matrices with not compatible size")
-	}
+	checkBroadcastGrad(x1v, x2v, gy)
+	x1b, x2b := broadcast(x1v, x2v)
+	defer releaseBroadcast(x1b, x1v)
+	defer releaseBroadcast(x2b, x2v)
 	if r.x1.RequiresGrad() {
-		gx := gy.Div(r.x2.Value())
-		defer mat.ReleaseDense(gx.(*mat.Dense))
-		r.x1.PropagateGrad(gx)
+		gx := gy.Div(x2b)
+		defer mat.ReleaseMatrix(gx)
+		propagateBroadcastGrad(r.x1, gx)
 	}
 	if r.x2.RequiresGrad() {
-		x2sq := r.x2.Value().Prod(r.x2.Value())
-		defer mat.ReleaseDense(x2sq.(*mat.Dense))
-		gx := r.x1.Value().Prod(gy)
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		x2sq := x2b.Prod(x2b)
+		defer mat.ReleaseMatrix(x2sq)
+		gx := x1b.Prod(gy)
+		defer mat.ReleaseMatrix(gx)
 		gx.ProdScalarInPlace(-1)
 		gx.DivInPlace(x2sq)
-		r.x2.PropagateGrad(gx)
+		propagateBroadcastGrad(r.x2, gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangents of the operands.
+// ty = (tx1 - y * tx2) / x2
+func (r *Div) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	y := r.Forward()
+	defer mat.ReleaseMatrix(y)
+	ty2 := NewProd(&constant{value: y}, tangentOf(r.x2, tangent)).Forward()
+	defer mat.ReleaseMatrix(ty2)
+	ty := NewSub(tangentOf(r.x1, tangent), &constant{value: ty2}).Forward()
+	defer mat.ReleaseMatrix(ty)
+	return NewDiv(&constant{value: ty}, r.x2).Forward()
+}
@@ -27,7 +27,7 @@ func (r *DivScalar) Forward() mat.Matrix {
 
 func (r *DivScalar) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x1.Value(), gy) || mat.VectorsOfSameSize(r.x1.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("DivScalar", r.x1.Value(), gy))
 	}
 	if r.x1.RequiresGrad() {
 		r.x1.PropagateGrad(gy.ProdScalar(1.0 / r.x2.Value().Scalar()))
@@ -44,3 +44,15 @@ func (r *DivScalar) Backward(gy mat.Matrix) {
 		r.x2.PropagateGrad(scalar)
 	}
 }
+
+// JVP computes the tangent of the output given the tangents of the operands.
+// ty = (tx1 - y * tx2) / x2
+func (r *DivScalar) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	y := r.Forward()
+	defer mat.ReleaseMatrix(y)
+	ty2 := NewProdScalar(&constant{value: y}, tangentOf(r.x2, tangent)).Forward()
+	defer mat.ReleaseMatrix(ty2)
+	ty := NewSub(tangentOf(r.x1, tangent), &constant{value: ty2}).Forward()
+	defer mat.ReleaseMatrix(ty)
+	return NewDivScalar(&constant{value: ty}, r.x2).Forward()
+}
@@ -24,7 +24,7 @@ func (r *Dot) Forward() mat.Matrix {
 	x1v := r.x1.Value()
 	x2v := r.x2.Value()
 	if !(mat.SameDims(x1v, x2v) || mat.VectorsOfSameSize(x1v, x2v)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Dot", x1v, x2v))
 	}
 	y := 0.0
 	if r.x1.Value().IsVector() && r.x2.Value().IsVector() {
@@ -41,7 +41,7 @@ func (r *Dot) Forward() mat.Matrix {
 
 func (r *Dot) Backward(gy mat.Matrix) {
 	if !gy.IsScalar() {
-		panic("fn: the gradient had to be a scalar")
+		panic(&mat.ShapeError{Op: "Dot", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {1, 1}}})
 	}
 	if r.x1.RequiresGrad() {
 		dx := mat.GetDenseWorkspace(r.x1.Value().Dims())
@@ -64,3 +64,12 @@ func (r *Dot) Backward(gy mat.Matrix) {
 		r.x2.PropagateGrad(dx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangents of the operands.
+// ty = tx1 · x2 + x1 · tx2
+func (r *Dot) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return sumTangents(
+		NewDot(tangentOf(r.x1, tangent), r.x2).Forward(),
+		NewDot(r.x1, tangentOf(r.x2, tangent)).Forward(),
+	)
+}
@@ -43,11 +43,21 @@ func (r *Dropout) Forward() mat.Matrix {
 
 func (r *Dropout) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Dropout", r.x.Value(), gy))
 	}
-	defer mat.ReleaseDense(r.mask.(*mat.Dense))
+	defer mat.ReleaseMatrix(r.mask)
 	if r.x.RequiresGrad() {
 		gx := gy.Prod(r.mask)
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, which is the tangent of the operand times the mask of the forward.
+// It must be called before the backward, which releases the mask.
+func (r *Dropout) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangent(r.x)
+	if tx == nil {
+		return r.x.Value().ZerosLike()
+	}
+	return tx.Prod(r.mask)
+}
@@ -29,7 +29,7 @@ func (r *ELU) Forward() mat.Matrix {
 
 func (r *ELU) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("ELU", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.GetDenseWorkspace(r.x.Value().Dims())
@@ -39,3 +39,9 @@ func (r *ELU) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangent of x; the alpha is constant as in the backward.
+// ty = f'(x) * tx
+func (r *ELU) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return parametricTangent(eluDeriv, r.x, tangent, r.alpha.Value().Scalar())
+}
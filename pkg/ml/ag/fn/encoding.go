@@ -0,0 +1,301 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
+	"io"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// encodable contains the functions that can be encoded (see Encode), by name. The fields of the functions are
+// encoded by reflection, so every function of the package must be listed in init, and its fields must be of the
+// types supported by the encoder: TestEncodable fails otherwise.
+var encodable = make(map[string]reflect.Type)
+
+// elementwise contains the element-wise functions (and their derivatives) of the constructors of UnaryElementwise,
+// which are encoded by name, e.g. "Tanh.f" and "Tanh.df".
+var elementwise = make(map[string]func(i, j int, v float64) float64)
+
+// elementwiseNames contains the names of the element-wise functions by their pointers.
+var elementwiseNames = make(map[uintptr]string)
+
+func init() {
+	for _, f := range []Function{
+		&Add{}, &AddScalar{}, &Affine{}, &At{}, &AtVec{}, &AvgPooling{}, &CeLU{}, &ColView{}, &Concat{}, &Div{}, &DivScalar{},
+		&Dot{}, &Dropout{}, &ELU{}, &Entmax{}, &Gather{}, &Identity{}, &IndexSelect{}, &LeakyReLU{}, &LogSoftmax{}, &LogSumExp{}, &MaskedFill{}, &MaxPooling{}, &MinPooling{}, &Mul{}, &Permute{}, &Pow{}, &Prod{},
+		&ProdScalar{}, &ReduceSumAxis{}, &ReduceMaxAxis{}, &ReduceMean{}, &ReduceSum{}, &Reshape{},
+		&ReverseSubScalar{}, &RowView{}, &ScatterAdd{}, &SeLU{}, &Softmax{}, &SoftPlus{}, &Sparsemax{}, &SoftShrink{}, &Stack{}, &Sub{},
+		&SubScalar{}, &SumTo{}, &Swish{}, &Threshold{}, &Transpose{}, &UnaryElementwise{}, &Vec{}, &View{}, &Where{},
+	} {
+		t := reflect.TypeOf(f).Elem()
+		encodable[t.Name()] = t
+	}
+	for _, c := range []struct {
+		name        string
+		constructor func(x Operand) *UnaryElementwise
+	}{
+		{"Tan", NewTan}, {"Tanh", NewTanh}, {"Sigmoid", NewSigmoid}, {"HardSigmoid", NewHardSigmoid},
+		{"HardTanh", NewHardTanh}, {"ReLU", NewReLU}, {"Softsign", NewSoftsign}, {"Cos", NewCos}, {"Sin", NewSin},
+		{"Exp", NewExp}, {"Log", NewLog}, {"Neg", NewNeg}, {"Reciprocal", NewReciprocal}, {"Abs", NewAbs},
+		{"Mish", NewMish}, {"Sqrt", NewSqrt}, {"GELU", NewGELU}, {"GELUTanh", NewGELUTanh},
+	} {
+		f := c.constructor(nil)
+		registerElementwise(c.name+".f", f.f)
+		registerElementwise(c.name+".df", f.df)
+	}
+}
+
+func registerElementwise(name string, f func(i, j int, v float64) float64) {
+	elementwise[name] = f
+	if p := reflect.ValueOf(f).Pointer(); elementwiseNames[p] == "" {
+		elementwiseNames[p] = name
+	}
+}
+
+var (
+	operandType = reflect.TypeOf((*Operand)(nil)).Elem()
+	matrixType  = reflect.TypeOf((*mat.Matrix)(nil)).Elem()
+	randType    = reflect.TypeOf((*rand.LockedRand)(nil))
+)
+
+// Encode writes the type and the fields of the function into w, including the matrices kept for the backward.
+// The operands are written as the ids given by operandId (-1 for the absent ones).
+// Only the functions of this package can be encoded, so the functions of other packages (e.g. the ones of the
+// custom operators, see ag.RegisterOp) make it return an error; the random generators are not encoded (see Decode).
+func Encode(w io.Writer, f Function, operandId func(x Operand) int64) error {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Ptr || encodable[v.Elem().Type().Name()] != v.Elem().Type() {
+		return fmt.Errorf("fn: cannot encode the function %T", f)
+	}
+	e := &encoder{w: w, operandId: operandId}
+	e.writeString(v.Elem().Type().Name())
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		e.writeField(fieldOf(v, i))
+	}
+	return e.err
+}
+
+// Decode reads a function written by Encode, resolving the ids of its operands with operand.
+// The functions requiring a random generator (e.g. Dropout) get randGen.
+func Decode(r io.Reader, operand func(id int64) (Operand, error), randGen *rand.LockedRand) (Function, error) {
+	d := &decoder{r: r, operand: operand, randGen: randGen}
+	name := d.readString()
+	if d.err != nil {
+		return nil, d.err
+	}
+	t, ok := encodable[name]
+	if !ok {
+		return nil, fmt.Errorf("fn: cannot decode the function %q", name)
+	}
+	v := reflect.New(t)
+	for i := 0; i < t.NumField() && d.err == nil; i++ {
+		d.readField(fieldOf(v.Elem(), i))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return v.Interface().(Function), nil
+}
+
+// fieldOf returns the i-th field of the struct, which can be set although it is unexported.
+func fieldOf(v reflect.Value, i int) reflect.Value {
+	f := v.Field(i)
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+type encoder struct {
+	w         io.Writer
+	operandId func(x Operand) int64
+	err       error
+}
+
+func (e *encoder) writeField(f reflect.Value) {
+	switch {
+	case f.Type() == operandType:
+		e.writeOperand(f)
+	case f.Type() == matrixType:
+		e.writeBool(!f.IsNil())
+		if !f.IsNil() && e.err == nil {
+			_, e.err = mat.MarshalBinaryTo(f.Interface().(mat.Matrix), e.w)
+		}
+	case f.Type() == randType:
+		// replaced on decoding
+	case f.Kind() == reflect.Func:
+		if f.IsNil() {
+			e.writeString("")
+		} else if name, ok := elementwiseNames[f.Pointer()]; ok {
+			e.writeString(name)
+		} else {
+			e.fail(fmt.Errorf("fn: cannot encode an element-wise function of an unknown constructor"))
+		}
+	case f.Kind() == reflect.Int:
+		e.writeInt(f.Int())
+	case f.Kind() == reflect.Float64:
+		e.writeInt(int64(math.Float64bits(f.Float())))
+	case f.Kind() == reflect.Bool:
+		e.writeBool(f.Bool())
+	case f.Kind() == reflect.Slice:
+		e.writeBool(!f.IsNil())
+		e.writeInt(int64(f.Len()))
+		for i := 0; i < f.Len(); i++ {
+			e.writeField(f.Index(i))
+		}
+	default:
+		e.fail(fmt.Errorf("fn: cannot encode a field of type %s", f.Type()))
+	}
+}
+
+func (e *encoder) writeOperand(f reflect.Value) {
+	if f.IsNil() {
+		e.writeInt(-1)
+		return
+	}
+	e.writeInt(e.operandId(f.Interface().(Operand)))
+}
+
+func (e *encoder) writeString(s string) {
+	e.writeInt(int64(len(s)))
+	e.write([]byte(s))
+}
+
+func (e *encoder) writeBool(b bool) {
+	if b {
+		e.write([]byte{1})
+	} else {
+		e.write([]byte{0})
+	}
+}
+
+func (e *encoder) writeInt(n int64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(n))
+	e.write(b[:])
+}
+
+func (e *encoder) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+func (e *encoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+type decoder struct {
+	r       io.Reader
+	operand func(id int64) (Operand, error)
+	randGen *rand.LockedRand
+	err     error
+}
+
+func (d *decoder) readField(f reflect.Value) {
+	switch {
+	case f.Type() == operandType:
+		if id := d.readInt(); id >= 0 && d.err == nil {
+			x, err := d.operand(id)
+			d.fail(err)
+			if x != nil {
+				f.Set(reflect.ValueOf(x))
+			}
+		}
+	case f.Type() == matrixType:
+		if d.readBool() && d.err == nil {
+			m, _, err := mat.NewUnmarshalBinaryFrom(d.r)
+			d.fail(err)
+			if err == nil {
+				w := mat.GetDenseWorkspace(m.Dims()) // so that it can be released
+				w.SetData(m.Data())
+				f.Set(reflect.ValueOf(w))
+			}
+		}
+	case f.Type() == randType:
+		f.Set(reflect.ValueOf(d.randGen))
+	case f.Kind() == reflect.Func:
+		if name := d.readString(); name != "" && d.err == nil {
+			g, ok := elementwise[name]
+			if !ok {
+				d.fail(fmt.Errorf("fn: unknown element-wise function %q", name))
+				return
+			}
+			f.Set(reflect.ValueOf(g))
+		}
+	case f.Kind() == reflect.Int:
+		f.SetInt(d.readInt())
+	case f.Kind() == reflect.Float64:
+		f.SetFloat(math.Float64frombits(uint64(d.readInt())))
+	case f.Kind() == reflect.Bool:
+		f.SetBool(d.readBool())
+	case f.Kind() == reflect.Slice:
+		notNil, n := d.readBool(), d.readInt()
+		if d.err != nil || !notNil {
+			return
+		}
+		if n < 0 || n > math.MaxInt32 {
+			d.fail(fmt.Errorf("fn: invalid slice length %d", n))
+			return
+		}
+		f.Set(reflect.MakeSlice(f.Type(), int(n), int(n)))
+		for i := 0; i < int(n) && d.err == nil; i++ {
+			d.readField(f.Index(i))
+		}
+	default:
+		d.fail(fmt.Errorf("fn: cannot decode a field of type %s", f.Type()))
+	}
+}
+
+func (d *decoder) readString() string {
+	n := d.readInt()
+	if d.err != nil {
+		return ""
+	}
+	if n < 0 || n > math.MaxInt16 {
+		d.fail(fmt.Errorf("fn: invalid string length %d", n))
+		return ""
+	}
+	b := make([]byte, n)
+	d.read(b)
+	return string(b)
+}
+
+func (d *decoder) readBool() bool {
+	var b [1]byte
+	d.read(b[:])
+	return b[0] != 0
+}
+
+func (d *decoder) readInt() int64 {
+	var b [8]byte
+	d.read(b[:])
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+func (d *decoder) read(b []byte) {
+	if d.err != nil {
+		return
+	}
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		d.err = err
+	}
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"bytes"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"gonum.org/v1/gonum/floats"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeAndDecode(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{0.5, -0.6, 0.8}),
+		requiresGrad: true,
+	}
+	b := &variable{
+		value:        mat.NewVecDense([]float64{0.1, 0.2, 0.3}),
+		requiresGrad: true,
+	}
+	w := &variable{
+		value:        mat.NewDense(3, 3, []float64{0.1, 0.2, 0.3, -0.4, 0.5, -0.6, 0.7, 0.8, -0.9}),
+		requiresGrad: true,
+	}
+	operands := []Operand{x, b, w}
+	id := func(x Operand) int64 {
+		for i, o := range operands {
+			if o == x {
+				return int64(i)
+			}
+		}
+		return -1
+	}
+	operand := func(id int64) (Operand, error) { return operands[id], nil }
+
+	for _, f := range []Function{
+		NewSigmoid(x),
+		NewFusedAffine(TanhActivation, b, w, x),
+		NewAffine(nil, w, x),
+		NewPow(x, 3.0),
+		NewSoftmax(x),
+		NewEntmax(x, 1.5),
+		NewGELU(x),
+		NewMaxPooling(w, 3, 3),
+		NewMinPooling(w, 3, 1),
+		NewAvgPooling(w, 1, 3),
+		NewConcat([]Operand{x, b}),
+		NewDropout(x, 0.0, rand.NewLockedRand(1)),
+	} {
+		y := f.Forward()
+		var buf bytes.Buffer
+		if err := Encode(&buf, f, id); err != nil {
+			t.Fatalf("%T: %v", f, err)
+		}
+		f2, err := Decode(&buf, operand, rand.NewLockedRand(1))
+		if err != nil {
+			t.Fatalf("%T: %v", f, err)
+		}
+		if !floats.EqualApprox(f2.Forward().Data(), y.Data(), 1.0e-12) {
+			t.Errorf("%T: the output of the decoded function doesn't match the expected values", f)
+		}
+	}
+}
+
+func TestEncode_UnknownElementwise(t *testing.T) {
+	f := &UnaryElementwise{f: func(i, j int, v float64) float64 { return v }}
+	if err := Encode(&bytes.Buffer{}, f, nil); err == nil {
+		t.Errorf("Encoding an element-wise function of an unknown constructor should fail.")
+	}
+}
+
+// TestEncodable checks that all the functions of the package can be encoded: the types with the methods Forward and
+// Backward must be listed among the encodable ones, with fields supported by the encoder, and the constructors of
+// UnaryElementwise must register their element-wise functions.
+func TestEncodable(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	methods := make(map[string]map[string]bool)
+	var constructors []string
+	for _, file := range pkgs["fn"].Files {
+		for _, decl := range file.Decls {
+			f, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if f.Recv == nil {
+				if isUnaryElementwise(f.Type.Results) && strings.HasPrefix(f.Name.Name, "New") {
+					constructors = append(constructors, strings.TrimPrefix(f.Name.Name, "New"))
+				}
+				continue
+			}
+			recv := f.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				if methods[ident.Name] == nil {
+					methods[ident.Name] = make(map[string]bool)
+				}
+				methods[ident.Name][f.Name.Name] = true
+			}
+		}
+	}
+	for name, m := range methods {
+		if !m["Forward"] || !m["Backward"] {
+			continue
+		}
+		typ, ok := encodable[name]
+		if !ok {
+			t.Errorf("The function %s is not encodable", name)
+			continue
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if !isEncodableField(typ.Field(i).Type) {
+				t.Errorf("The field %s.%s cannot be encoded", name, typ.Field(i).Name)
+			}
+		}
+	}
+	for _, name := range constructors {
+		if _, ok := elementwise[name+".f"]; !ok {
+			t.Errorf("The element-wise functions of New%s are not registered", name)
+		}
+	}
+}
+
+func isUnaryElementwise(results *ast.FieldList) bool {
+	if results == nil || len(results.List) != 1 {
+		return false
+	}
+	star, ok := results.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := star.X.(*ast.Ident)
+	return ok && ident.Name == "UnaryElementwise"
+}
+
+// isEncodableField reports whether the encoder supports the type (see encoder.writeField).
+func isEncodableField(t reflect.Type) bool {
+	switch {
+	case t == operandType, t == matrixType, t == randType:
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Func, reflect.Int, reflect.Float64, reflect.Bool:
+		return true
+	case reflect.Slice:
+		return isEncodableField(t.Elem())
+	default:
+		return false
+	}
+}
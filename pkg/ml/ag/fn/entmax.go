@@ -0,0 +1,132 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"math"
+)
+
+var _ Function = &Entmax{}
+
+// entmaxIterations is the number of bisection steps used to find the threshold of the alpha-entmax.
+const entmaxIterations = 50
+
+// Entmax is the alpha-entmax transformation, which interpolates between the softmax (alpha → 1) and the
+// sparsemax (alpha = 2): y = max((alpha - 1)·x - τ, 0)^(1 / (alpha - 1)), where τ is such that y sums to one.
+// The threshold τ is found by bisection. If the input is a mini-batch matrix, the function is applied to each
+// column independently.
+//
+// Reference: "Sparse Sequence-to-Sequence Models" by Ben Peters, Vlad Niculae and André F. T. Martins, 2019
+// (https://arxiv.org/pdf/1905.05702.pdf)
+type Entmax struct {
+	x     Operand
+	alpha float64
+	y     mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewEntmax returns a new Entmax function. It panics if alpha is not greater than one.
+func NewEntmax(x Operand, alpha float64) *Entmax {
+	if !(alpha > 1.0) {
+		panic(fmt.Errorf("fn: the alpha of the entmax must be greater than one, found %g", alpha))
+	}
+	return &Entmax{x: x, alpha: alpha}
+}
+
+// Forward computes the output of the function.
+func (r *Entmax) Forward() mat.Matrix {
+	rows, cols := columnsOf(r.x.Value())
+	y := mat.GetDenseWorkspace(r.x.Value().Dims())
+	xData, yData := r.x.Value().Data(), y.Data()
+	z := make([]float64, rows)
+	p := make([]float64, rows)
+	for j := 0; j < cols; j++ {
+		for i := range z {
+			z[i] = (r.alpha - 1.0) * xData[i*cols+j]
+		}
+		entmaxBisect(z, r.alpha, p)
+		for i, v := range p {
+			yData[i*cols+j] = v
+		}
+	}
+	r.y = y
+	return y
+}
+
+// Backward computes the backward pass.
+// gx = s ⊙ (gy - sum(s ⊙ gy) / sum(s)), where s = y^(2 - alpha) on the support of y and zero elsewhere.
+// The sums are column-wise to support mini-batches.
+func (r *Entmax) Backward(gy mat.Matrix) {
+	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
+		panic(mat.NewShapeError("Entmax", r.x.Value(), gy))
+	}
+	if r.x.RequiresGrad() {
+		gx := entmaxColumnsDeriv(r.y, gy, r.alpha)
+		defer mat.ReleaseDense(gx)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+// ty = s ⊙ (tx - sum(s ⊙ tx) / sum(s)), since the Jacobian is symmetric.
+func (r *Entmax) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangentOf(r.x, tangent).Value()
+	y := (&Entmax{x: r.x, alpha: r.alpha}).Forward() // r.y may have been released by the graph
+	defer mat.ReleaseMatrix(y)
+	return entmaxColumnsDeriv(y, tx, r.alpha)
+}
+
+// entmaxBisect writes into p the values max(z - τ, 0)^(1 / (alpha - 1)), normalized to sum to one, where z is
+// the input already scaled by alpha - 1. The threshold τ lies in [max(z) - 1, max(z) - (1 / len(z))^(alpha - 1)].
+func entmaxBisect(z []float64, alpha float64, p []float64) {
+	exp := 1.0 / (alpha - 1.0)
+	probs := func(tau float64) (sum float64) {
+		for i, v := range z {
+			p[i] = math.Pow(max0(v-tau), exp)
+			sum += p[i]
+		}
+		return sum
+	}
+	m := max(z)
+	lo := m - 1.0
+	hi := m - math.Pow(1.0/float64(len(z)), alpha-1.0)
+	for k := 0; k < entmaxIterations; k++ {
+		mid := 0.5 * (lo + hi)
+		if probs(mid) >= 1.0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	sum := probs(lo)
+	for i := range p {
+		p[i] /= sum
+	}
+}
+
+// entmaxColumnsDeriv returns the product of the Jacobian of the column-wise alpha-entmax y with g.
+// The Jacobian is diag(s) - s·sᵀ / sum(s), where s = y^(2 - alpha) on the support of y and zero elsewhere.
+func entmaxColumnsDeriv(y, g mat.Matrix, alpha float64) *mat.Dense {
+	rows, cols := columnsOf(y)
+	out := mat.GetDenseWorkspace(y.Dims())
+	yData, gData, outData := y.Data(), g.Data(), out.Data()
+	s := make([]float64, rows)
+	for j := 0; j < cols; j++ {
+		sum, dot := 0.0, 0.0
+		for i := range s {
+			s[i] = 0.0
+			if v := yData[i*cols+j]; v > 0.0 {
+				s[i] = math.Pow(v, 2.0-alpha)
+			}
+			sum += s[i]
+			dot += s[i] * gData[i*cols+j]
+		}
+		for i, v := range s {
+			outData[i*cols+j] = v * (gData[i*cols+j] - dot/sum)
+		}
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestEntmax_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{0.5, -1.3, 0.9, 0.1}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewEntmax(x, 1.5)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.3066667, 0.0, 0.5681766, 0.1251567}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewVecDense([]float64{0.2, -0.1, 0.4, 0.3}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{-0.0620442, 0.0, 0.0663031, -0.0042589}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestEntmax_MatchesSparsemax(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{0.5, -0.3, 0.9, 0.1}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	y := NewEntmax(x, 2.0).Forward()
+
+	if !floats.EqualApprox(y.Data(), NewSparsemax(x).Forward().Data(), 1.0e-9) {
+		t.Error("The 2-entmax doesn't match the sparsemax")
+	}
+}
+
+func TestNewEntmax_InvalidAlpha(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(error); !ok {
+			t.Errorf("NewEntmax should panic with an error if alpha is not greater than one")
+		}
+	}()
+	NewEntmax(&variable{value: mat.NewVecDense([]float64{0.5})}, 1.0)
+}
@@ -21,3 +21,34 @@ type Function interface {
 	// Backward computes the backward pass.
 	Backward(gy mat.Matrix)
 }
+
+// RowGradPropagator is implemented by the operands accumulating the gradients of some of their rows directly
+// (e.g. the nodes of a graph, or the parameters of a model), so that the functions selecting a few rows of a large
+// matrix (e.g. IndexSelect on an embedding table) don't allocate dense gradients of its dimensions.
+type RowGradPropagator interface {
+	Operand
+	// PropagateRowGrad propagates the gradients gx of the rows at the indices: the k-th row of gx is added to
+	// the gradients of the row at indices[k]. The indices can be repeated.
+	PropagateRowGrad(indices []int, gx mat.Matrix)
+}
+
+// PropagateRowGrad propagates the gradients gx of the rows of x at the indices (see RowGradPropagator), through
+// dense gradients of the dimensions of x if it doesn't accumulate the gradients of the rows directly.
+func PropagateRowGrad(x Operand, indices []int, gx mat.Matrix) {
+	if x, ok := x.(RowGradPropagator); ok {
+		x.PropagateRowGrad(indices, gx)
+		return
+	}
+	g := mat.GetEmptyDenseWorkspace(x.Value().Dims())
+	defer mat.ReleaseDense(g)
+	AddRowsInPlace(g, indices, gx)
+	x.PropagateGrad(g)
+}
+
+// Releaser is implemented by the functions retaining intermediate matrices for the backward (e.g. Affine),
+// which return them to the workspace on Release, e.g. when the memory of the graph is released.
+type Releaser interface {
+	Function
+	// Release releases the intermediate matrices; the function can be evaluated again by Forward.
+	Release()
+}
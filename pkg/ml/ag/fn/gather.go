@@ -0,0 +1,87 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &Gather{}
+
+// Gather returns a column vector with the elements of x at the coordinates (rows[k], cols[k]), e.g. the scores of
+// the transitions of a sequence of labels. The coordinates can be repeated, in which case the gradients are
+// accumulated. The gradients are propagated by row (see RowGradPropagator), restricted to the rows of the gathered
+// elements, so the backward doesn't allocate a matrix of the dimensions of x when the operand accumulates them
+// directly.
+type Gather struct {
+	x    Operand
+	rows []int
+	cols []int
+}
+
+// NewGather returns a new Gather function. It panics if rows and cols have different lengths.
+func NewGather(x Operand, rows, cols []int) *Gather {
+	if len(rows) != len(cols) {
+		panic(&mat.ShapeError{Op: "Gather", Shapes: [][]int{{len(rows), 1}, {len(cols), 1}}})
+	}
+	return &Gather{x: x, rows: rows, cols: cols}
+}
+
+// Indices returns the row and column indices of the gathered elements.
+func (r *Gather) Indices() (rows, cols []int) {
+	return r.rows, r.cols
+}
+
+// Forward computes the output of the function.
+func (r *Gather) Forward() mat.Matrix {
+	y := mat.GetDenseWorkspace(len(r.rows), 1)
+	xData, yData := r.x.Value().Data(), y.Data()
+	for k := range r.rows {
+		yData[k] = xData[r.offset(k)]
+	}
+	return y
+}
+
+// offset returns the offset in the data of x of the k-th coordinates.
+func (r *Gather) offset(k int) int {
+	rows, cols := r.x.Value().Dims()
+	i, j := r.rows[k], r.cols[k]
+	if i < 0 || i >= rows || j < 0 || j >= cols {
+		panic(&mat.IndexError{Index: []int{i, j}, Shape: []int{rows, cols}})
+	}
+	return i*cols + j
+}
+
+// Backward computes the backward pass.
+func (r *Gather) Backward(gy mat.Matrix) {
+	if gy.Size() != len(r.rows) {
+		panic(&mat.ShapeError{Op: "Gather", Shapes: [][]int{{len(r.rows), 1}, {gy.Rows(), gy.Columns()}}})
+	}
+	if !r.x.RequiresGrad() {
+		return
+	}
+	cols := r.x.Value().Columns()
+	var indices []int         // the distinct rows of the gathered elements
+	position := map[int]int{} // the position of each row in indices
+	for k, i := range r.rows {
+		r.offset(k) // checks the coordinates
+		if _, ok := position[i]; !ok {
+			position[i] = len(indices)
+			indices = append(indices, i)
+		}
+	}
+	gx := mat.GetEmptyDenseWorkspace(len(indices), cols)
+	defer mat.ReleaseDense(gx)
+	gxData, gyData := gx.Data(), gy.Data()
+	for k, i := range r.rows {
+		gxData[position[i]*cols+r.cols[k]] += gyData[k]
+	}
+	PropagateRowGrad(r.x, indices, gx)
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+func (r *Gather) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewGather(tangentOf(r.x, tangent), r.rows, r.cols).Forward()
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"reflect"
+	"testing"
+)
+
+func TestGather_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 2, []float64{
+			0.1, 0.2,
+			0.3, 0.4,
+			0.5, 0.6,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewGather(x, []int{2, 0, 2}, []int{1, 0, 1})
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.6, 0.1, 0.6}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewVecDense([]float64{1.0, 2.0, 3.0}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		2.0, 0.0,
+		0.0, 0.0,
+		0.0, 4.0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+// rowVariable is a variable accumulating the gradients of its rows directly (see RowGradPropagator).
+type rowVariable struct {
+	variable
+	indices []int
+	rows    mat.Matrix
+}
+
+func (r *rowVariable) PropagateRowGrad(indices []int, gx mat.Matrix) {
+	r.indices, r.rows = indices, gx.Clone()
+}
+
+func TestGather_BackwardByRow(t *testing.T) {
+	x := &rowVariable{variable: variable{
+		value:        mat.NewEmptyDense(1000, 2),
+		requiresGrad: true,
+	}}
+	f := NewGather(x, []int{512, 3, 512}, []int{1, 0, 0})
+	f.Forward()
+	f.Backward(mat.NewVecDense([]float64{1.0, 2.0, 3.0}))
+
+	if x.grad != nil {
+		t.Error("The gradients should be propagated by row")
+	}
+	if !reflect.DeepEqual(x.indices, []int{512, 3}) {
+		t.Errorf("Unexpected rows %v", x.indices)
+	}
+	if !floats.EqualApprox(x.rows.Data(), []float64{
+		3.0, 1.0,
+		2.0, 0.0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestAddRowsInPlace_Dense32(t *testing.T) {
+	m := mat.NewEmptyDense32(3, 2)
+	AddRowsInPlace(m, []int{2, 0, 2}, mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}))
+	if !floats.EqualApprox(m.Data(), []float64{3, 4, 0, 0, 6, 8}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+}
@@ -25,7 +25,12 @@ func (r *Identity) Forward() mat.Matrix {
 
 func (r *Identity) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Identity", r.x.Value(), gy))
 	}
 	r.x.PropagateGrad(gy)
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Identity) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewIdentity(tangentOf(r.x, tangent)).Forward()
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &IndexSelect{}
+
+// IndexSelect selects the rows of x at the given indices, e.g. the embeddings of a sequence from a single table.
+// The indices can be repeated, in which case the gradients of the rows are accumulated. The gradients are
+// propagated by row (see RowGradPropagator), so the backward doesn't allocate a matrix of the dimensions of x when
+// the operand accumulates them directly (e.g. a node of the graph, or a parameter of a model).
+type IndexSelect struct {
+	x       Operand
+	indices []int
+}
+
+func NewIndexSelect(x Operand, indices []int) *IndexSelect {
+	return &IndexSelect{x: x, indices: indices}
+}
+
+// Indices returns the indices of the selected rows.
+func (r *IndexSelect) Indices() []int {
+	return r.indices
+}
+
+// Forward computes the output of the function.
+func (r *IndexSelect) Forward() mat.Matrix {
+	rows, cols := r.x.Value().Dims()
+	y := mat.GetDenseWorkspace(len(r.indices), cols)
+	xData, yData := r.x.Value().Data(), y.Data()
+	for k, i := range r.indices {
+		if i < 0 || i >= rows {
+			panic(&mat.IndexError{Index: []int{i}, Shape: []int{rows, cols}})
+		}
+		copy(yData[k*cols:(k+1)*cols], xData[i*cols:(i+1)*cols])
+	}
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *IndexSelect) Backward(gy mat.Matrix) {
+	cols := r.x.Value().Columns()
+	if gy.Rows() != len(r.indices) || gy.Columns() != cols {
+		panic(&mat.ShapeError{Op: "IndexSelect", Shapes: [][]int{{len(r.indices), cols}, {gy.Rows(), gy.Columns()}}})
+	}
+	if r.x.RequiresGrad() {
+		PropagateRowGrad(r.x, r.indices, gy)
+	}
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+func (r *IndexSelect) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewIndexSelect(tangentOf(r.x, tangent), r.indices).Forward()
+}
+
+// AddRowsInPlace adds the k-th row of src to the row of m at indices[k], e.g. to accumulate the gradients of
+// the rows (see RowGradPropagator). The indices can be repeated.
+func AddRowsInPlace(m mat.Matrix, indices []int, src mat.Matrix) {
+	rows, cols := m.Dims()
+	if src.Rows() != len(indices) || src.Columns() != cols {
+		panic(&mat.ShapeError{Op: "AddRowsInPlace", Shapes: [][]int{{len(indices), cols}, {src.Rows(), src.Columns()}}})
+	}
+	d, isDense := m.(*mat.Dense)
+	srcData := src.Data()
+	for k, i := range indices {
+		if i < 0 || i >= rows {
+			panic(&mat.IndexError{Index: []int{i}, Shape: []int{rows, cols}})
+		}
+		if !isDense {
+			for j, v := range srcData[k*cols : (k+1)*cols] {
+				m.Set(i, j, m.At(i, j)+v)
+			}
+			continue
+		}
+		row := d.Data()[i*cols : (i+1)*cols] // in place
+		for j, v := range srcData[k*cols : (k+1)*cols] {
+			row[j] += v
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestIndexSelect_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 2, []float64{
+			0.1, 0.2,
+			0.3, 0.4,
+			0.5, 0.6,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewIndexSelect(x, []int{2, 0, 2})
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		0.5, 0.6,
+		0.1, 0.2,
+		0.5, 0.6,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(3, 2, []float64{
+		1.0, 2.0,
+		3.0, 4.0,
+		5.0, 6.0,
+	}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		3.0, 4.0,
+		0.0, 0.0,
+		6.0, 8.0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestIndexSelect_OutOfRange(t *testing.T) {
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, mat.ErrIndexOutOfRange) {
+			t.Errorf("Expected an index error, found %v", err)
+		}
+	}()
+	x := &variable{value: mat.NewEmptyDense(3, 2)}
+	NewIndexSelect(x, []int{3}).Forward()
+}
+
+func TestIndexSelect_BackwardByRow(t *testing.T) {
+	x := &rowVariable{variable: variable{
+		value:        mat.NewEmptyDense(1000, 2),
+		requiresGrad: true,
+	}}
+	f := NewIndexSelect(x, []int{7, 7})
+	f.Forward()
+	f.Backward(mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}))
+
+	if x.grad != nil || len(x.indices) != 2 || x.rows.Size() != 4 {
+		t.Error("The gradients should be propagated by row")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/pkg/mat"
+
+// ForwardDifferentiable is a Function supporting the forward-mode differentiation.
+type ForwardDifferentiable interface {
+	Function
+	// JVP returns the tangent of the output (the Jacobian-vector product) given the tangents of the operands.
+	// The tangent function returns the tangent of an operand of the function, or nil if it is zero.
+	// The tangents must not be modified.
+	JVP(tangent func(x Operand) mat.Matrix) mat.Matrix
+}
+
+var _ Operand = &constant{}
+
+// constant is an operand with a fixed value that doesn't require gradients.
+// It is used to apply the linear functions to the tangents of their operands, which is their own JVP.
+type constant struct {
+	value mat.Matrix
+}
+
+func (c *constant) Value() mat.Matrix          { return c.value }
+func (c *constant) PropagateGrad(_ mat.Matrix) {}
+func (c *constant) RequiresGrad() bool         { return false }
+
+// tangentOf returns an operand whose value is the tangent of x, or zeros if x has no tangent.
+func tangentOf(x Operand, tangent func(x Operand) mat.Matrix) Operand {
+	if t := tangent(x); t != nil {
+		return &constant{value: t}
+	}
+	return &constant{value: x.Value().ZerosLike()}
+}
+
+// tangentsOf returns the operands whose values are the tangents of xs (see tangentOf).
+func tangentsOf(xs []Operand, tangent func(x Operand) mat.Matrix) []Operand {
+	out := make([]Operand, len(xs))
+	for i, x := range xs {
+		out[i] = tangentOf(x, tangent)
+	}
+	return out
+}
+
+// parametricTangent returns df(x, params) * tx, the tangent of a parametric element-wise function (e.g. ELU).
+func parametricTangent(df func(i, j int, v float64, params ...float64) float64, x Operand, tangent func(x Operand) mat.Matrix, params ...float64) mat.Matrix {
+	tx := tangent(x)
+	if tx == nil {
+		return x.Value().ZerosLike()
+	}
+	ty := mat.GetDenseWorkspace(x.Value().Dims())
+	ty.ApplyWithAlpha(df, x.Value(), params...)
+	ty.ProdInPlace(tx)
+	return ty
+}
+
+// sumTangents returns a + b, releasing b.
+func sumTangents(a, b mat.Matrix) mat.Matrix {
+	defer mat.ReleaseMatrix(b)
+	a.AddInPlace(b)
+	return a
+}
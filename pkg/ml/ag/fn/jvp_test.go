@@ -0,0 +1,152 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
+	"gonum.org/v1/gonum/floats"
+	"reflect"
+	"testing"
+)
+
+func TestProd_JVP(t *testing.T) {
+	x1 := &variable{value: mat.NewVecDense([]float64{0.1, 0.2, 0.3})}
+	x2 := &variable{value: mat.NewVecDense([]float64{0.4, 0.5, -0.6})}
+	tangents := map[Operand]mat.Matrix{
+		x1: mat.NewVecDense([]float64{1.0, 0.0, -1.0}),
+	}
+	ty := NewProd(x1, x2).JVP(func(x Operand) mat.Matrix { return tangents[x] })
+
+	if !floats.EqualApprox(ty.Data(), []float64{0.4, 0.0, 0.6}, 1.0e-6) {
+		t.Error("The tangents don't match the expected values")
+	}
+}
+
+func TestSoftmax_JVP(t *testing.T) {
+	x := &variable{value: mat.NewVecDense([]float64{-0.41, -1.08, 0.0, 0.87})}
+	tangents := map[Operand]mat.Matrix{
+		x: mat.NewVecDense([]float64{0.0, 0.0, 1.0, 0.0}),
+	}
+	f := NewSoftmax(x)
+	y := f.Forward().Data()
+	ty := f.JVP(func(x Operand) mat.Matrix { return tangents[x] })
+
+	// the third column of the Jacobian: y_i * (δ_i2 - y_2)
+	expected := []float64{-y[0] * y[2], -y[1] * y[2], y[2] * (1 - y[2]), -y[3] * y[2]}
+	if !floats.EqualApprox(ty.Data(), expected, 1.0e-6) {
+		t.Error("The tangents don't match the expected values")
+	}
+}
+
+func TestEntmax_JVP(t *testing.T) {
+	x := &variable{value: mat.NewVecDense([]float64{0.5, -1.3, 0.9, 0.1}), requiresGrad: true}
+	tx := mat.NewVecDense([]float64{0.2, -0.1, 0.4, 0.3})
+	f := NewEntmax(x, 1.5)
+	f.Forward()
+	ty := f.JVP(func(Operand) mat.Matrix { return tx })
+
+	// the Jacobian is symmetric, so the tangents match the gradients given the same vector
+	f.Backward(tx)
+	if !floats.EqualApprox(ty.Data(), x.grad.Data(), 1.0e-9) {
+		t.Error("The tangents don't match the expected values")
+	}
+}
+
+func TestPooling_JVP(t *testing.T) {
+	x := &variable{value: mat.NewDense(2, 4, []float64{
+		0.1, 0.5, -0.3, 0.2,
+		0.4, -0.6, 0.7, 0.0,
+	})}
+	tx := mat.NewDense(2, 4, []float64{
+		1.0, 2.0, 3.0, 4.0,
+		5.0, 6.0, 7.0, 8.0,
+	})
+	tangent := func(Operand) mat.Matrix { return tx }
+
+	maxPooling := NewMaxPooling(x, 2, 2)
+	maxPooling.Forward()
+	if !floats.EqualApprox(maxPooling.JVP(tangent).Data(), []float64{2.0, 7.0}, 1.0e-6) {
+		t.Error("The tangents of the max pooling don't match the expected values")
+	}
+
+	minPooling := NewMinPooling(x, 2, 2)
+	minPooling.Forward()
+	if !floats.EqualApprox(minPooling.JVP(tangent).Data(), []float64{6.0, 3.0}, 1.0e-6) {
+		t.Error("The tangents of the min pooling don't match the expected values")
+	}
+}
+
+func TestForwardDifferentiable(t *testing.T) {
+	fd := reflect.TypeOf((*ForwardDifferentiable)(nil)).Elem()
+	for name, typ := range encodable {
+		if !reflect.PtrTo(typ).Implements(fd) {
+			t.Errorf("The function %s is not forward-differentiable", name)
+		}
+	}
+}
+
+func TestParametric_JVP(t *testing.T) {
+	scalar := func(v float64) *variable { return &variable{value: mat.NewScalar(v)} }
+	x := &variable{value: mat.NewVecDense([]float64{0.5, -1.3, 0.9, -0.1}), requiresGrad: true}
+	tx := mat.NewVecDense([]float64{0.2, -0.1, 0.4, 0.3})
+	for _, f := range []interface {
+		ForwardDifferentiable
+		Backward(gy mat.Matrix)
+	}{
+		NewCeLU(x, scalar(2.0)),
+		NewELU(x, scalar(2.0)),
+		NewLeakyReLU(x, scalar(0.1)),
+		NewSeLU(x, scalar(2.0), scalar(1.6)),
+		NewSoftPlus(x, scalar(2.0), scalar(20.0)),
+		NewSoftShrink(x, scalar(0.2)),
+		NewThreshold(x, scalar(0.2), scalar(-1.0)),
+		NewSwish(x, scalar(2.0)),
+		NewPow(x, 3.0),
+	} {
+		f.Forward()
+		ty := f.JVP(func(o Operand) mat.Matrix {
+			if o == x {
+				return tx
+			}
+			return nil
+		})
+		// the Jacobian is diagonal, so the tangents match the gradients given the same vector
+		f.Backward(tx)
+		if !floats.EqualApprox(ty.Data(), x.grad.Data(), 1.0e-9) {
+			t.Errorf("The tangents of %T don't match the expected values", f)
+		}
+	}
+}
+
+func TestSwish_JVPBeta(t *testing.T) {
+	x := &variable{value: mat.NewVecDense([]float64{0.5, -1.3})}
+	beta := &variable{value: mat.NewScalar(2.0), requiresGrad: true}
+	f := NewSwish(x, beta)
+	f.Forward()
+	ty := f.JVP(func(o Operand) mat.Matrix {
+		if o == beta {
+			return mat.NewScalar(1.0)
+		}
+		return nil
+	})
+	f.Backward(mat.NewVecDense([]float64{1.0, 1.0}))
+	if !floats.EqualApprox([]float64{floats.Sum(ty.Data())}, beta.grad.Data(), 1.0e-9) {
+		t.Error("The tangents don't match the expected values")
+	}
+}
+
+func TestDropout_JVP(t *testing.T) {
+	x := &variable{value: mat.NewVecDense([]float64{0.5, -1.3, 0.9, -0.1}), requiresGrad: true}
+	tx := mat.NewVecDense([]float64{0.2, -0.1, 0.4, 0.3})
+	f := NewDropout(x, 0.5, rand.NewLockedRand(42))
+	y := f.Forward()
+	ty := f.JVP(func(Operand) mat.Matrix { return tx })
+	for i, v := range y.Data() {
+		if expected := v / x.value.Data()[i] * tx.Data()[i]; !floats.EqualWithinAbs(ty.Data()[i], expected, 1.0e-9) {
+			t.Error("The tangents don't match the expected values")
+		}
+	}
+}
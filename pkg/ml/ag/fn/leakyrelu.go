@@ -29,7 +29,7 @@ func (r *LeakyReLU) Forward() mat.Matrix {
 
 func (r *LeakyReLU) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("LeakyReLU", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.GetDenseWorkspace(r.x.Value().Dims())
@@ -39,3 +39,9 @@ func (r *LeakyReLU) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangent of x; the alpha is constant as in the backward.
+// ty = f'(x) * tx
+func (r *LeakyReLU) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return parametricTangent(leakyReLUDeriv, r.x, tangent, r.alpha.Value().Scalar())
+}
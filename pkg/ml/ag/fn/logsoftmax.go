@@ -0,0 +1,87 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"math"
+)
+
+var _ Function = &LogSoftmax{}
+
+// LogSoftmax computes log(softmax(x)) as x - logsumexp(x), which is finite also when the softmax underflows.
+// If the input is a mini-batch matrix, the function is applied to each column independently.
+type LogSoftmax struct {
+	x Operand
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewLogSoftmax returns a new LogSoftmax function of x, applied column-wise if x is a mini-batch matrix.
+func NewLogSoftmax(x Operand) *LogSoftmax {
+	return &LogSoftmax{x: x}
+}
+
+// Forward computes the output of the function.
+func (r *LogSoftmax) Forward() mat.Matrix {
+	rows, cols := columnsOf(r.x.Value())
+	y := mat.GetDenseWorkspace(r.x.Value().Dims())
+	xData, yData := r.x.Value().Data(), y.Data()
+	for j := 0; j < cols; j++ {
+		lse := logSumExp(xData, j, rows, cols)
+		for i := 0; i < rows; i++ {
+			yData[i*cols+j] = xData[i*cols+j] - lse
+		}
+	}
+	r.y = y
+	return y
+}
+
+// Backward computes the backward pass.
+// gx = gy - exp(y) * sum(gy), where the sum is column-wise to support mini-batches.
+func (r *LogSoftmax) Backward(gy mat.Matrix) {
+	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
+		panic(mat.NewShapeError("LogSoftmax", r.x.Value(), gy))
+	}
+	if !r.x.RequiresGrad() {
+		return
+	}
+	rows, cols := columnsOf(r.x.Value())
+	gx := mat.GetDenseWorkspace(r.x.Value().Dims())
+	defer mat.ReleaseDense(gx)
+	yData, gyData, gxData := r.y.Data(), gy.Data(), gx.Data()
+	for j := 0; j < cols; j++ {
+		sum := 0.0
+		for i := 0; i < rows; i++ {
+			sum += gyData[i*cols+j]
+		}
+		for i := 0; i < rows; i++ {
+			gxData[i*cols+j] = gyData[i*cols+j] - math.Exp(yData[i*cols+j])*sum
+		}
+	}
+	r.x.PropagateGrad(gx)
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+// ty = tx - sum(softmax(x) * tx), where the sum is column-wise to support mini-batches.
+func (r *LogSoftmax) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangent(r.x)
+	if tx == nil {
+		return r.x.Value().ZerosLike()
+	}
+	rows, cols := columnsOf(r.x.Value())
+	ty := mat.GetDenseWorkspace(r.x.Value().Dims())
+	xData, txData, tyData := r.x.Value().Data(), tx.Data(), ty.Data()
+	for j := 0; j < cols; j++ {
+		lse := logSumExp(xData, j, rows, cols)
+		dot := 0.0
+		for i := 0; i < rows; i++ {
+			dot += math.Exp(xData[i*cols+j]-lse) * txData[i*cols+j]
+		}
+		for i := 0; i < rows; i++ {
+			tyData[i*cols+j] = txData[i*cols+j] - dot
+		}
+	}
+	return ty
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestLogSoftmax_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{-0.41, -1.08, 0, 0.87, -0.19, -0.75}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewLogSoftmax(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		-2.1486193, -2.8186193, -1.7386193, -0.8686193, -1.9286193, -2.4886193,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewVecDense([]float64{0.1, 0.0, -1.0, 0.2, 0.0, 0.3}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0.146658, 0.0238753, -0.9296948, 0.3678122, 0.0581395, 0.3332098,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestLogSoftmax_ForwardBatch(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 2, []float64{
+			1000, -0.41,
+			1001, -1.08,
+			999, 0.0,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewLogSoftmax(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		-1.407606, -1.1047688,
+		-0.407606, -1.7747688,
+		-2.407606, -0.6947688,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(3, 2, []float64{
+		-1.0, 0.0,
+		0.0, 0.0,
+		0.0, -1.0,
+	}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		-0.7552715, 0.3312875,
+		0.6652410, 0.1695226,
+		0.0900306, -0.5008101,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
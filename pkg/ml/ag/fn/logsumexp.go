@@ -0,0 +1,111 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"math"
+)
+
+var _ Function = &LogSumExp{}
+
+// LogSumExp computes log(sum(exp(x))) without overflows, subtracting the max before the exponentiation.
+// If the input is a mini-batch matrix, the function is applied to each column independently and the output is a
+// row vector; otherwise the output is a scalar.
+type LogSumExp struct {
+	x Operand
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewLogSumExp returns a new LogSumExp function of x, reduced column-wise if x is a mini-batch matrix.
+func NewLogSumExp(x Operand) *LogSumExp {
+	return &LogSumExp{x: x}
+}
+
+// Forward computes the output of the function.
+func (r *LogSumExp) Forward() mat.Matrix {
+	rows, cols := columnsOf(r.x.Value())
+	y := mat.GetDenseWorkspace(1, cols)
+	xData, yData := r.x.Value().Data(), y.Data()
+	for j := 0; j < cols; j++ {
+		yData[j] = logSumExp(xData, j, rows, cols)
+	}
+	r.y = y
+	return y
+}
+
+// Backward computes the backward pass.
+// gx = exp(x - y) * gy, i.e. the softmax of each column multiplied by the gradient of its output.
+// The gradients of a column whose values are all -Inf are zero, the output being constant with respect to them.
+func (r *LogSumExp) Backward(gy mat.Matrix) {
+	rows, cols := columnsOf(r.x.Value())
+	if gy.Size() != cols {
+		panic(&mat.ShapeError{Op: "LogSumExp", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {1, cols}}})
+	}
+	if !r.x.RequiresGrad() {
+		return
+	}
+	gx := mat.GetDenseWorkspace(r.x.Value().Dims())
+	defer mat.ReleaseDense(gx)
+	xData, yData, gyData, gxData := r.x.Value().Data(), r.y.Data(), gy.Data(), gx.Data()
+	for j := 0; j < cols; j++ {
+		if math.IsInf(yData[j], -1) {
+			for i := 0; i < rows; i++ {
+				gxData[i*cols+j] = 0
+			}
+			continue
+		}
+		for i := 0; i < rows; i++ {
+			gxData[i*cols+j] = math.Exp(xData[i*cols+j]-yData[j]) * gyData[j]
+		}
+	}
+	r.x.PropagateGrad(gx)
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+// ty = sum(exp(x - y) * tx), where the sum is column-wise to support mini-batches.
+func (r *LogSumExp) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	rows, cols := columnsOf(r.x.Value())
+	ty := mat.GetEmptyDenseWorkspace(1, cols)
+	tx := tangent(r.x)
+	if tx == nil {
+		return ty
+	}
+	xData, txData, tyData := r.x.Value().Data(), tx.Data(), ty.Data()
+	for j := 0; j < cols; j++ {
+		y := logSumExp(xData, j, rows, cols)
+		if math.IsInf(y, -1) {
+			continue // constant output
+		}
+		for i := 0; i < rows; i++ {
+			tyData[j] += math.Exp(xData[i*cols+j]-y) * txData[i*cols+j]
+		}
+	}
+	return ty
+}
+
+// columnsOf returns the dimensions of m, considering a vector as a single column.
+func columnsOf(m mat.Matrix) (rows, cols int) {
+	if m.IsVector() {
+		return m.Size(), 1
+	}
+	return m.Dims()
+}
+
+// logSumExp returns the log-sum-exp of the j-th column of the data of a matrix with the given dimensions.
+func logSumExp(data []float64, j, rows, cols int) float64 {
+	max := math.Inf(-1)
+	for i := 0; i < rows; i++ {
+		max = math.Max(max, data[i*cols+j])
+	}
+	if math.IsInf(max, 0) {
+		return max
+	}
+	sum := 0.0
+	for i := 0; i < rows; i++ {
+		sum += math.Exp(data[i*cols+j] - max)
+	}
+	return max + math.Log(sum)
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"math"
+	"testing"
+)
+
+func TestLogSumExp_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{-0.41, -1.08, 0, 0.87, -0.19, -0.75}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewLogSumExp(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{1.7386193}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewScalar(0.5))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0.0583226, 0.0298441, 0.0878815, 0.2097652, 0.0726744, 0.0415123,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestLogSumExp_ForwardBatch(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 2, []float64{
+			1000, -0.41,
+			1001, -1.08,
+			999, 0.0,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewLogSumExp(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{1001.4076060, 0.6947688}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(1, 2, []float64{1.0, 2.0}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0.2447285, 0.6625750,
+		0.6652410, 0.3390453,
+		0.0900306, 0.9983797,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestLogSumExp_BackwardAllNegInf(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 2, []float64{
+			math.Inf(-1), 0.0,
+			math.Inf(-1), 0.0,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewLogSumExp(x)
+	y := f.Forward()
+	if !math.IsInf(y.Data()[0], -1) || !floats.EqualApprox(y.Data()[1:], []float64{math.Log(2)}, 1.0e-12) {
+		t.Error("The output doesn't match the expected values")
+	}
+	f.Backward(mat.NewDense(1, 2, []float64{1.0, 2.0}))
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0.0, 1.0,
+		0.0, 1.0,
+	}, 1.0e-12) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
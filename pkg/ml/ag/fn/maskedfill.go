@@ -0,0 +1,63 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &MaskedFill{}
+
+// MaskedFill replaces the values of x with the given value where the mask is not zero, e.g. to set to -Inf the
+// attention scores of the masked keys before the softmax. The gradients don't flow into the replaced values.
+type MaskedFill struct {
+	x     Operand
+	mask  mat.Matrix
+	value float64
+}
+
+// NewMaskedFill returns a new MaskedFill function. The mask must have the same dimensions of x.
+func NewMaskedFill(x Operand, mask mat.Matrix, value float64) *MaskedFill {
+	return &MaskedFill{x: x, mask: mask, value: value}
+}
+
+// Mask returns the mask of the replaced values.
+func (r *MaskedFill) Mask() mat.Matrix {
+	return r.mask
+}
+
+// Forward computes the output of the function.
+func (r *MaskedFill) Forward() mat.Matrix {
+	if !mat.SameDims(r.x.Value(), r.mask) {
+		panic(mat.NewShapeError("MaskedFill", r.x.Value(), r.mask))
+	}
+	y := mat.GetDenseWorkspace(r.x.Value().Dims())
+	xData, maskData, yData := r.x.Value().Data(), r.mask.Data(), y.Data()
+	for i, m := range maskData {
+		if m != 0 {
+			yData[i] = r.value
+		} else {
+			yData[i] = xData[i]
+		}
+	}
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *MaskedFill) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic(mat.NewShapeError("MaskedFill", r.x.Value(), gy))
+	}
+	if r.x.RequiresGrad() {
+		gx := NewMaskedFill(&constant{value: gy}, r.mask, 0).Forward()
+		defer mat.ReleaseMatrix(gx)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+func (r *MaskedFill) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewMaskedFill(tangentOf(r.x, tangent), r.mask, 0).Forward()
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"math"
+	"testing"
+)
+
+func TestMaskedFill_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewDense(2, 2, []float64{0.1, 0.2, 0.3, 0.4}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewMaskedFill(x, mat.NewDense(2, 2, []float64{0, 1, 1, 0}), math.Inf(-1))
+	y := f.Forward()
+
+	if !floats.Equal(y.Data(), []float64{0.1, math.Inf(-1), math.Inf(-1), 0.4}) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{1.0, 0.0, 0.0, 4.0}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
@@ -35,45 +35,74 @@ func NewMaxPooling(x Operand, r, c int) *MaxPooling {
 
 // Forward computes the output of the function.
 func (r *MaxPooling) Forward() mat.Matrix {
-	if !(r.x.Value().Rows()%r.rows == 0 && r.x.Value().Columns()%r.cols == 0) {
-		panic("fn: size mismatch")
+	r.y, r.argmaxi, r.argmaxj = argPooling("MaxPooling", r.x.Value(), r.rows, r.cols, 1.0)
+	return r.y
+}
+
+func (r *MaxPooling) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		gx := argPoolingDeriv(r.x.Value(), r.argmaxi, r.argmaxj, gy)
+		defer mat.ReleaseMatrix(gx)
+		r.x.PropagateGrad(gx)
 	}
+}
 
-	r.y = mat.NewEmptyDense(r.x.Value().Rows()/r.rows, r.x.Value().Columns()/r.cols)
-	r.argmaxi = utils.MakeIntMatrix(r.y.Dims()) // output argmax row index
-	r.argmaxj = utils.MakeIntMatrix(r.y.Dims()) // output argmax column index
+// JVP computes the tangent of the output, which is the tangent of the selected values.
+func (r *MaxPooling) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return argPoolingTangent(r.argmaxi, r.argmaxj, tangentOf(r.x, tangent).Value())
+}
 
-	for row := 0; row < r.y.Rows(); row++ {
-		for col := 0; col < r.y.Columns(); col++ {
-			max := math.SmallestNonzeroFloat64
-			for i := row * r.rows; i < (row*r.rows)+r.rows; i++ {
-				for j := col * r.cols; j < (col*r.cols)+r.rows; j++ {
-					val := r.x.Value().At(i, j)
-					if val > max {
-						max = val
-						r.argmaxi[row][col] = i
-						r.argmaxj[row][col] = j
+// checkPooling panics if the dimensions of x are not multiple of the ones of the pooling window.
+func checkPooling(op string, x mat.Matrix, rows, cols int) {
+	if !(rows > 0 && cols > 0 && x.Rows()%rows == 0 && x.Columns()%cols == 0) {
+		panic(&mat.ShapeError{Op: op, Shapes: [][]int{{x.Rows(), x.Columns()}, {rows, cols}}})
+	}
+}
+
+// argPooling returns the max (sign = 1) or the min (sign = -1) of each non-overlapping window of rows x cols values
+// of x, with the row and column indices of the selected values.
+func argPooling(op string, x mat.Matrix, rows, cols int, sign float64) (y mat.Matrix, argi, argj [][]int) {
+	checkPooling(op, x, rows, cols)
+	y = mat.NewEmptyDense(x.Rows()/rows, x.Columns()/cols)
+	argi = utils.MakeIntMatrix(y.Dims()) // row index of the selected value
+	argj = utils.MakeIntMatrix(y.Dims()) // column index of the selected value
+	for row := 0; row < y.Rows(); row++ {
+		for col := 0; col < y.Columns(); col++ {
+			best := math.Inf(-1)
+			argi[row][col], argj[row][col] = row*rows, col*cols
+			for i := row * rows; i < (row*rows)+rows; i++ {
+				for j := col * cols; j < (col*cols)+cols; j++ {
+					if val := sign * x.At(i, j); val > best {
+						best = val
+						argi[row][col] = i
+						argj[row][col] = j
 					}
 				}
 			}
-			r.y.Set(row, col, max)
+			y.Set(row, col, x.At(argi[row][col], argj[row][col]))
 		}
 	}
+	return
+}
 
-	return r.y
+// argPoolingDeriv returns the gradients of x, which flow into the selected values only.
+func argPoolingDeriv(x mat.Matrix, argi, argj [][]int, gy mat.Matrix) mat.Matrix {
+	gx := x.ZerosLike()
+	for row := range argi {
+		for col := range argi[row] {
+			gx.Set(argi[row][col], argj[row][col], gy.At(row, col))
+		}
+	}
+	return gx
 }
 
-func (r *MaxPooling) Backward(gy mat.Matrix) {
-	if r.x.RequiresGrad() {
-		gx := r.x.Value().ZerosLike()
-		defer mat.ReleaseDense(gx.(*mat.Dense))
-		for row := 0; row < r.y.Rows(); row++ {
-			rowi := r.argmaxi[row]
-			rowj := r.argmaxj[row]
-			for col := 0; col < r.y.Columns(); col++ {
-				gx.Set(rowi[col], rowj[col], gy.At(row, col))
-			}
+// argPoolingTangent returns the tangent of the selected values, given the tangent tx of x.
+func argPoolingTangent(argi, argj [][]int, tx mat.Matrix) mat.Matrix {
+	ty := mat.NewEmptyDense(len(argi), len(argi[0]))
+	for row := range argi {
+		for col := range argi[row] {
+			ty.Set(row, col, tx.At(argi[row][col], argj[row][col]))
 		}
-		r.x.PropagateGrad(gx)
 	}
+	return ty
 }
@@ -53,3 +53,35 @@ func TestMaxPool_Forward(t *testing.T) {
 		t.Error("The rows and columns of the resulting x-gradients matrix are not correct")
 	}
 }
+
+func TestMaxPooling_ForwardNegativeRectangular(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 6, []float64{
+			-0.4, -0.1, -0.3, -0.5, -0.9, -0.2,
+			-0.2, -0.6, -0.7, -0.8, -0.3, -0.4,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewMaxPooling(x, 1, 3)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		-0.1, -0.2,
+		-0.2, -0.3,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(2, 2, []float64{
+		0.5, -0.7,
+		0.8, 0.1,
+	}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0.0, 0.5, 0.0, 0.0, 0.0, -0.7,
+		0.8, 0.0, 0.0, 0.0, 0.1, 0.0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &MinPooling{}
+
+// MinPooling takes the min of each non-overlapping window of rows x cols values of the input, whose dimensions
+// must be multiple of the ones of the window.
+type MinPooling struct {
+	x    Operand
+	rows int
+	cols int
+	// initialized during the forward pass
+	y       mat.Matrix
+	argmini [][]int
+	argminj [][]int
+}
+
+func NewMinPooling(x Operand, r, c int) *MinPooling {
+	return &MinPooling{x: x, rows: r, cols: c}
+}
+
+// Forward computes the output of the function.
+func (r *MinPooling) Forward() mat.Matrix {
+	r.y, r.argmini, r.argminj = argPooling("MinPooling", r.x.Value(), r.rows, r.cols, -1.0)
+	return r.y
+}
+
+func (r *MinPooling) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		gx := argPoolingDeriv(r.x.Value(), r.argmini, r.argminj, gy)
+		defer mat.ReleaseMatrix(gx)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output, which is the tangent of the selected values.
+func (r *MinPooling) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return argPoolingTangent(r.argmini, r.argminj, tangentOf(r.x, tangent).Value())
+}
@@ -129,6 +129,24 @@ func NewMish(x Operand) *UnaryElementwise {
 	}
 }
 
+// NewGELU returns the exact Gaussian Error Linear Unit x·Φ(x), where Φ is the standard normal CDF.
+func NewGELU(x Operand) *UnaryElementwise {
+	return &UnaryElementwise{
+		x:  x,
+		f:  gelu,
+		df: geluDeriv,
+	}
+}
+
+// NewGELUTanh returns the tanh approximation of the Gaussian Error Linear Unit used by BERT and GPT.
+func NewGELUTanh(x Operand) *UnaryElementwise {
+	return &UnaryElementwise{
+		x:  x,
+		f:  geluTanh,
+		df: geluTanhDeriv,
+	}
+}
+
 func NewSqrt(x Operand) *UnaryElementwise {
 	return &UnaryElementwise{
 		x:  x,
@@ -424,3 +442,25 @@ func mishDeriv(i, j int, v float64) float64 {
 	delta := 2*exp + exp2 + 2.0
 	return exp * (omega / (delta * delta))
 }
+
+// Reference: "Gaussian Error Linear Units (GELUs)" by Dan Hendrycks and Kevin Gimpel, 2016.
+// (https://arxiv.org/pdf/1606.08415.pdf)
+func gelu(i, j int, v float64) float64 {
+	return 0.5 * v * (1.0 + math.Erf(v/math.Sqrt2))
+}
+
+func geluDeriv(i, j int, v float64) float64 {
+	return 0.5*(1.0+math.Erf(v/math.Sqrt2)) + v*math.Exp(-0.5*v*v)/math.Sqrt(2.0*math.Pi)
+}
+
+// geluTanhScale is sqrt(2/π).
+var geluTanhScale = math.Sqrt(2.0 / math.Pi)
+
+func geluTanh(i, j int, v float64) float64 {
+	return 0.5 * v * (1.0 + math.Tanh(geluTanhScale*(v+0.044715*v*v*v)))
+}
+
+func geluTanhDeriv(i, j int, v float64) float64 {
+	t := math.Tanh(geluTanhScale * (v + 0.044715*v*v*v))
+	return 0.5*(1.0+t) + 0.5*v*(1.0-t*t)*geluTanhScale*(1.0+3.0*0.044715*v*v)
+}
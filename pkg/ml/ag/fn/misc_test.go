@@ -309,3 +309,43 @@ func TestNewMishForward(t *testing.T) {
 		t.Error("The x-gradients don't match the expected values")
 	}
 }
+
+func TestNewGELUForward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{0.1, -0.2, 0.3, -1.5}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewGELU(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.0539827837, -0.0841480581, 0.1853734267, -0.1002108019}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewVecDense([]float64{-1.0, 0.5, 0.8, 0.0}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{-0.579523092, 0.1712658759, 0.5858622135, 0.0}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestNewGELUTanhForward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{0.1, -0.2, 0.3, -1.5}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewGELUTanh(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.053982751, -0.0841485702, 0.1853709235, -0.100428423}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewVecDense([]float64{-1.0, 0.5, 0.8, 0.0}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{-0.579521789, 0.171270925, 0.585836361, 0.0}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
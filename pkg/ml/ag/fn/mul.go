@@ -6,7 +6,8 @@ package fn
 
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
-	"sync"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
+	"github.com/nlpodyssey/spago/pkg/utils"
 )
 
 var _ Function = &Mul{}
@@ -23,7 +24,7 @@ func NewMul(x1, x2 Operand) *Mul {
 // Forward computes the output of the function.
 func (r *Mul) Forward() mat.Matrix {
 	if r.x1.Value().Columns() != r.x2.Value().Rows() {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Mul", r.x1.Value(), r.x2.Value()))
 	}
 	return r.x1.Value().Mul(r.x2.Value())
 }
@@ -31,37 +32,53 @@ func (r *Mul) Forward() mat.Matrix {
 // TODO: backward of sparse gradients
 func (r *Mul) Backward(gy mat.Matrix) {
 	if !(r.x1.Value().Rows() == gy.Rows() && r.x2.Value().Columns() == gy.Columns()) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Mul", r.x1.Value(), r.x2.Value(), gy))
 	}
-	var wg sync.WaitGroup
+	backwardX1 := func() {
+		x2t := r.x2.Value().T()
+		defer mat.ReleaseMatrix(x2t)
+		gx := gy.Mul(x2t)
+		defer mat.ReleaseMatrix(gx)
+		r.x1.PropagateGrad(gx)
+	}
+	backwardX2 := func() {
+		//r.x2.PropagateGrad(gy.T().Mul(r.x1).T()) // alternative method
+		if x1, ok := r.x1.Value().(*mat.Dense); ok && gy.Columns() == 1 {
+			gx := x1.MulT(gy)
+			defer mat.ReleaseMatrix(gx)
+			r.x2.PropagateGrad(gx)
+		} else {
+			x1t := r.x1.Value().T()
+			defer mat.ReleaseMatrix(x1t)
+			gx := x1t.Mul(gy)
+			defer mat.ReleaseMatrix(gx)
+			r.x2.PropagateGrad(gx)
+		}
+	}
+	if determinism.Enabled() {
+		if r.x1.RequiresGrad() {
+			backwardX1()
+		}
+		if r.x2.RequiresGrad() {
+			backwardX2()
+		}
+		return
+	}
+	var g utils.Group
 	if r.x1.RequiresGrad() {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			x2t := r.x2.Value().T()
-			defer mat.ReleaseDense(x2t.(*mat.Dense))
-			gx := gy.Mul(x2t)
-			defer mat.ReleaseDense(gx.(*mat.Dense))
-			r.x1.PropagateGrad(gx)
-		}()
+		g.Go(backwardX1)
 	}
 	if r.x2.RequiresGrad() {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			//r.x2.PropagateGrad(gy.T().Mul(r.x1).T()) // alternative method
-			if gy.Columns() == 1 {
-				gx := r.x1.Value().(*mat.Dense).MulT(gy)
-				defer mat.ReleaseDense(gx.(*mat.Dense))
-				r.x2.PropagateGrad(gx)
-			} else {
-				x1t := r.x1.Value().T()
-				defer mat.ReleaseDense(x1t.(*mat.Dense))
-				gx := x1t.Mul(gy)
-				defer mat.ReleaseDense(gx.(*mat.Dense))
-				r.x2.PropagateGrad(gx)
-			}
-		}()
+		g.Go(backwardX2)
 	}
-	wg.Wait()
+	g.Wait()
+}
+
+// JVP computes the tangent of the output given the tangents of the operands.
+// ty = tx1 x2 + x1 tx2
+func (r *Mul) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return sumTangents(
+		NewMul(tangentOf(r.x1, tangent), r.x2).Forward(),
+		NewMul(r.x1, tangentOf(r.x2, tangent)).Forward(),
+	)
 }
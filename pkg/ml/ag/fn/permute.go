@@ -0,0 +1,52 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &Permute{}
+
+// Permute reorders the dimensions of a n-dimensional tensor.
+// The operand holds the values of the tensor of the given shape in row-major order (see mat.TensorOf),
+// while the output is the permuted tensor in the form returned by mat.Tensor.ToDense.
+type Permute struct {
+	x     Operand
+	shape []int
+	axes  []int
+}
+
+func NewPermute(x Operand, shape []int, axes []int) *Permute {
+	return &Permute{x: x, shape: shape, axes: axes}
+}
+
+// Forward computes the output of the function.
+func (r *Permute) Forward() mat.Matrix {
+	return mat.TensorOf(r.x.Value(), r.shape...).Permute(r.axes...).ToDense()
+}
+
+func (r *Permute) Backward(gy mat.Matrix) {
+	if gy.Size() != r.x.Value().Size() {
+		panic(mat.NewShapeError("Permute", r.x.Value(), gy))
+	}
+	if r.x.RequiresGrad() {
+		inverse := make([]int, len(r.axes))
+		permuted := make([]int, len(r.axes))
+		for i, a := range r.axes {
+			inverse[a] = i
+			permuted[i] = r.shape[a]
+		}
+		gx := mat.NewDense(r.x.Value().Rows(), r.x.Value().Columns(),
+			mat.TensorOf(gy, permuted...).Permute(inverse...).Data())
+		defer mat.ReleaseDense(gx)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Permute) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewPermute(tangentOf(r.x, tangent), r.shape, r.axes).Forward()
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestPermute_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 6, []float64{
+			0, 1, 2, 3, 4, 5,
+			6, 7, 8, 9, 10, 11,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewPermute(x, []int{2, 3, 2}, []int{2, 0, 1})
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		0, 2, 4,
+		6, 8, 10,
+		1, 3, 5,
+		7, 9, 11,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	if y.Rows() != 4 || y.Columns() != 3 {
+		t.Error("The rows and columns of the resulting matrix are not correct")
+	}
+
+	f.Backward(mat.NewDense(4, 3, []float64{
+		0, 2, 4,
+		6, 8, 10,
+		1, 3, 5,
+		7, 9, 11,
+	}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0, 1, 2, 3, 4, 5,
+		6, 7, 8, 9, 10, 11,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+
+	if x.grad.Rows() != 2 || x.grad.Columns() != 6 {
+		t.Error("The rows and columns of the x-gradients are not correct")
+	}
+}
@@ -26,12 +26,24 @@ func (r *Pow) Forward() mat.Matrix {
 
 func (r *Pow) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Pow", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := r.x.Value().Pow(r.power - 1)
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 		gx.ProdScalarInPlace(r.power).ProdInPlace(gy)
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangent of the operand.
+// ty = p * x^(p-1) * tx
+func (r *Pow) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangent(r.x)
+	if tx == nil {
+		return r.x.Value().ZerosLike()
+	}
+	ty := r.x.Value().Pow(r.power - 1)
+	ty.ProdScalarInPlace(r.power).ProdInPlace(tx)
+	return ty
+}
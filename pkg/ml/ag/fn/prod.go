@@ -10,7 +10,7 @@ import (
 
 var _ Function = &Prod{}
 
-// Element-wise product over two values.
+// Element-wise product over two values, with broadcasting.
 type Prod struct {
 	x1 Operand
 	x2 Operand
@@ -28,27 +28,36 @@ func NewSquare(x Operand) *Prod {
 func (r *Prod) Forward() mat.Matrix {
 	x1v := r.x1.Value()
 	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, x2v) || mat.VectorsOfSameSize(x1v, x2v)) {
-		panic("fn: matrices with not compatible size")
-	}
-	return x1v.Prod(x2v)
+	x1b, x2b := broadcast(x1v, x2v)
+	defer releaseBroadcast(x1b, x1v)
+	defer releaseBroadcast(x2b, x2v)
+	return x1b.Prod(x2b)
 }
 
 func (r *Prod) Backward(gy mat.Matrix) {
 	x1v := r.x1.Value()
 	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, gy) || mat.VectorsOfSameSize(x1v, gy)) &&
-		!(mat.SameDims(x2v, gy) || mat.VectorsOfSameSize(x2v, gy)) {
-		panic("fn: matrices with not compatible size")
-	}
+	checkBroadcastGrad(x1v, x2v, gy)
+	x1b, x2b := broadcast(x1v, x2v)
+	defer releaseBroadcast(x1b, x1v)
+	defer releaseBroadcast(x2b, x2v)
 	if r.x1.RequiresGrad() {
-		gx := r.x2.Value().Prod(gy)
-		defer mat.ReleaseDense(gx.(*mat.Dense))
-		r.x1.PropagateGrad(gx)
+		gx := x2b.Prod(gy)
+		defer mat.ReleaseMatrix(gx)
+		propagateBroadcastGrad(r.x1, gx)
 	}
 	if r.x2.RequiresGrad() {
-		gx := r.x1.Value().Prod(gy)
-		defer mat.ReleaseDense(gx.(*mat.Dense))
-		r.x2.PropagateGrad(gx)
+		gx := x1b.Prod(gy)
+		defer mat.ReleaseMatrix(gx)
+		propagateBroadcastGrad(r.x2, gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangents of the operands.
+// ty = tx1 * x2 + x1 * tx2
+func (r *Prod) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return sumTangents(
+		NewProd(tangentOf(r.x1, tangent), r.x2).Forward(),
+		NewProd(r.x1, tangentOf(r.x2, tangent)).Forward(),
+	)
+}
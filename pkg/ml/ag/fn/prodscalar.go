@@ -27,11 +27,11 @@ func (r *ProdScalar) Forward() mat.Matrix {
 
 func (r *ProdScalar) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x1.Value(), gy) || mat.VectorsOfSameSize(r.x1.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("ProdScalar", r.x1.Value(), gy))
 	}
 	if r.x1.RequiresGrad() {
 		gx := gy.ProdScalar(r.x2.Value().Scalar())
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 		r.x1.PropagateGrad(gx)
 	}
 	if r.x2.RequiresGrad() {
@@ -46,3 +46,12 @@ func (r *ProdScalar) Backward(gy mat.Matrix) {
 		r.x2.PropagateGrad(scalar)
 	}
 }
+
+// JVP computes the tangent of the output given the tangents of the operands.
+// ty = tx1 * x2 + x1 * tx2
+func (r *ProdScalar) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return sumTangents(
+		NewProdScalar(tangentOf(r.x1, tangent), r.x2).Forward(),
+		NewProdScalar(r.x1, tangentOf(r.x2, tangent)).Forward(),
+	)
+}
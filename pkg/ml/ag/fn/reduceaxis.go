@@ -0,0 +1,138 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"math"
+)
+
+var _ Function = &ReduceSumAxis{}
+var _ Function = &ReduceMaxAxis{}
+
+// ReduceSumAxis sums the values of a n-dimensional tensor along an axis, which is removed.
+// The operand holds the values of the tensor of the given shape in row-major order (see mat.TensorOf),
+// while the output is the reduced tensor in the form returned by mat.Tensor.ToDense.
+type ReduceSumAxis struct {
+	x     Operand
+	shape []int
+	axis  int
+}
+
+func NewReduceSumAxis(x Operand, shape []int, axis int) *ReduceSumAxis {
+	return &ReduceSumAxis{x: x, shape: shape, axis: axis}
+}
+
+// Forward computes the output of the function.
+func (r *ReduceSumAxis) Forward() mat.Matrix {
+	return mat.TensorOf(r.x.Value(), r.shape...).Sum(r.axis).ToDense()
+}
+
+func (r *ReduceSumAxis) Backward(gy mat.Matrix) {
+	outer, n, inner := axisSplit(r.shape, r.axis)
+	if gy.Size() != outer*inner {
+		panic(&mat.ShapeError{Op: "ReduceSumAxis", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {outer * inner, 1}}})
+	}
+	if r.x.RequiresGrad() {
+		gx := mat.GetDenseWorkspace(r.x.Value().Dims())
+		defer mat.ReleaseDense(gx)
+		gxData := gx.Data()
+		gyData := gy.Data()
+		for o := 0; o < outer; o++ {
+			for k := 0; k < n; k++ {
+				copy(gxData[(o*n+k)*inner:(o*n+k+1)*inner], gyData[o*inner:(o+1)*inner])
+			}
+		}
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// ReduceMaxAxis takes the max of the values of a n-dimensional tensor along an axis, which is removed.
+// The operand holds the values of the tensor of the given shape in row-major order (see mat.TensorOf),
+// while the output is the reduced tensor in the form returned by mat.Tensor.ToDense.
+type ReduceMaxAxis struct {
+	x      Operand
+	shape  []int
+	axis   int
+	argmax []int // initialized during the forward
+}
+
+func NewReduceMaxAxis(x Operand, shape []int, axis int) *ReduceMaxAxis {
+	return &ReduceMaxAxis{x: x, shape: shape, axis: axis}
+}
+
+// Forward computes the output of the function.
+// If the values along the axis contain NaN, the output is NaN and the gradient flows into the first NaN.
+func (r *ReduceMaxAxis) Forward() mat.Matrix {
+	outer, n, inner := axisSplit(r.shape, r.axis)
+	xData := r.x.Value().Data()
+	yData := make([]float64, outer*inner)
+	r.argmax = make([]int, outer*inner)
+	for o := 0; o < outer; o++ {
+		for i := 0; i < inner; i++ {
+			argmax := o*n*inner + i
+			for k := 1; k < n && !math.IsNaN(xData[argmax]); k++ {
+				if pos := (o*n+k)*inner + i; xData[pos] > xData[argmax] || math.IsNaN(xData[pos]) {
+					argmax = pos
+				}
+			}
+			r.argmax[o*inner+i] = argmax
+			yData[o*inner+i] = xData[argmax]
+		}
+	}
+	return r.output(yData)
+}
+
+// output returns the reduced tensor with the given values.
+func (r *ReduceMaxAxis) output(data []float64) mat.Matrix {
+	shape := append(append([]int{}, r.shape[:r.axis]...), r.shape[r.axis+1:]...)
+	if len(shape) == 0 {
+		return mat.NewScalar(data[0])
+	}
+	return mat.NewTensor(shape, data).ToDense()
+}
+
+func (r *ReduceMaxAxis) Backward(gy mat.Matrix) {
+	if gy.Size() != len(r.argmax) {
+		panic(&mat.ShapeError{Op: "ReduceMaxAxis", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {len(r.argmax), 1}}})
+	}
+	if r.x.RequiresGrad() {
+		gx := mat.GetEmptyDenseWorkspace(r.x.Value().Dims())
+		defer mat.ReleaseDense(gx)
+		gxData := gx.Data()
+		for i, g := range gy.Data() {
+			gxData[r.argmax[i]] = g
+		}
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// axisSplit returns the number of elements before the axis, along the axis, and after the axis
+// of a tensor of the given shape.
+func axisSplit(shape []int, axis int) (outer, n, inner int) {
+	outer, inner = 1, 1
+	for _, d := range shape[:axis] {
+		outer *= d
+	}
+	for _, d := range shape[axis+1:] {
+		inner *= d
+	}
+	return outer, shape[axis], inner
+}
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *ReduceSumAxis) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewReduceSumAxis(tangentOf(r.x, tangent), r.shape, r.axis).Forward()
+}
+
+// JVP computes the tangent of the output, which is the tangent of the operand at the max values.
+func (r *ReduceMaxAxis) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangentOf(r.x, tangent).Value().Data()
+	tyData := make([]float64, len(r.argmax))
+	for i, k := range r.argmax {
+		tyData[i] = tx[k]
+	}
+	return r.output(tyData)
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"math"
+	"testing"
+)
+
+func TestReduceSumAxis_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 6, []float64{
+			0, 1, 2, 3, 4, 5,
+			6, 7, 8, 9, 10, 11,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewReduceSumAxis(x, []int{2, 3, 2}, 1)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{6, 9, 24, 27}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	if y.Rows() != 2 || y.Columns() != 2 {
+		t.Error("The rows and columns of the resulting matrix are not correct")
+	}
+
+	f.Backward(mat.NewDense(2, 2, []float64{1, 2, 3, 4}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		1, 2, 1, 2, 1, 2,
+		3, 4, 3, 4, 3, 4,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestReduceMaxAxis_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 6, []float64{
+			0.1, 0.5, -0.2, 0.3, 0.9, 0.4,
+			0.7, 0.6, -0.3, -0.8, 0.2, 0.2,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewReduceMaxAxis(x, []int{2, 3, 2}, 2)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.5, 0.3, 0.9, 0.7, -0.3, 0.2}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	if y.Rows() != 2 || y.Columns() != 3 {
+		t.Error("The rows and columns of the resulting matrix are not correct")
+	}
+
+	f.Backward(mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0, 1, 0, 2, 3, 0,
+		4, 0, 5, 0, 6, 0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestReduceMaxAxis_NaN(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(2, 3, []float64{
+			0.1, math.NaN(), 0.4,
+			0.7, 0.6, math.NaN(),
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+
+	f := NewReduceMaxAxis(x, []int{2, 3}, 0)
+	y := f.Forward()
+
+	if y.AtVec(0) != 0.7 || !math.IsNaN(y.AtVec(1)) || !math.IsNaN(y.AtVec(2)) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewVecDense([]float64{1, 2, 3}))
+
+	if !floats.Equal(x.grad.Data(), []float64{
+		0, 2, 0,
+		1, 0, 3,
+	}) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
@@ -26,11 +26,16 @@ func (r *ReduceMean) Forward() mat.Matrix {
 
 func (r *ReduceMean) Backward(gy mat.Matrix) {
 	if !gy.IsScalar() {
-		panic("fn: the gradient had to be a scalar")
+		panic(&mat.ShapeError{Op: "ReduceMean", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {1, 1}}})
 	}
 	if r.x.RequiresGrad() {
-		gx := mat.NewInitVecDense(r.x.Value().Size(), gy.Scalar()/float64(r.x.Value().Size()))
+		gx := mat.NewInitDense(r.x.Value().Rows(), r.x.Value().Columns(), gy.Scalar()/float64(r.x.Value().Size()))
 		defer mat.ReleaseDense(gx)
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *ReduceMean) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewReduceMean(tangentOf(r.x, tangent)).Forward()
+}
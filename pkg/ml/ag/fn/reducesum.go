@@ -26,11 +26,16 @@ func (r *ReduceSum) Forward() mat.Matrix {
 
 func (r *ReduceSum) Backward(gy mat.Matrix) {
 	if !gy.IsScalar() {
-		panic("fn: the gradient had to be a scalar")
+		panic(&mat.ShapeError{Op: "ReduceSum", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {1, 1}}})
 	}
 	if r.x.RequiresGrad() {
-		gx := mat.NewInitVecDense(r.x.Value().Size(), gy.Scalar())
+		gx := mat.NewInitDense(r.x.Value().Rows(), r.x.Value().Columns(), gy.Scalar())
 		defer mat.ReleaseDense(gx)
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *ReduceSum) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewReduceSum(tangentOf(r.x, tangent)).Forward()
+}
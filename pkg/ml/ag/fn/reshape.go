@@ -23,18 +23,23 @@ func NewReshape(x Operand, r, c int) *Reshape {
 // Forward computes the output of the node.
 func (r *Reshape) Forward() mat.Matrix {
 	if r.x.Value().Size() != r.rows*r.cols {
-		panic("fn: incompatible sizes")
+		panic(&mat.ShapeError{Op: "Reshape", Shapes: [][]int{{r.x.Value().Rows(), r.x.Value().Columns()}, {r.rows, r.cols}}})
 	}
 	return r.x.Value().Reshape(r.rows, r.cols)
 }
 
 func (r *Reshape) Backward(gy mat.Matrix) {
 	if gy.Columns() != r.cols && gy.Rows() != r.rows {
-		panic("fn: matrices with not compatible size")
+		panic(&mat.ShapeError{Op: "Reshape", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {r.rows, r.cols}}})
 	}
 	if r.x.RequiresGrad() {
 		gx := gy.Reshape(r.x.Value().Dims())
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Reshape) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewReshape(tangentOf(r.x, tangent), r.rows, r.cols).Forward()
+}
@@ -25,11 +25,11 @@ func (r *ReverseSubScalar) Forward() mat.Matrix {
 
 func (r *ReverseSubScalar) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x1.Value(), gy) || mat.VectorsOfSameSize(r.x1.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("ReverseSubScalar", r.x1.Value(), gy))
 	}
 	if r.x1.RequiresGrad() {
 		gx := gy.ProdScalar(-1.0)
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 		r.x1.PropagateGrad(gx)
 	}
 	if r.x2.RequiresGrad() {
@@ -44,3 +44,8 @@ func (r *ReverseSubScalar) Backward(gy mat.Matrix) {
 		r.x2.PropagateGrad(scalar)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *ReverseSubScalar) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewReverseSubScalar(tangentOf(r.x1, tangent), tangentOf(r.x2, tangent)).Forward()
+}
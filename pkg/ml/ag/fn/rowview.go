@@ -15,9 +15,6 @@ type RowView struct {
 
 // Extract the i-th row from the input matrix
 func NewRowView(x Operand, i int) *RowView {
-	if i < 0 {
-		panic("fn: invalid row index")
-	}
 	return &RowView{x: x, i: i}
 }
 
@@ -25,8 +22,8 @@ func NewRowView(x Operand, i int) *RowView {
 func (r *RowView) Forward() mat.Matrix {
 	xv := r.x.Value()
 	rows, cols := xv.Dims()
-	if r.i >= rows {
-		panic("fn: matrix with not compatible size")
+	if r.i < 0 || r.i >= rows {
+		panic(&mat.IndexError{Index: []int{r.i}, Shape: []int{rows, cols}})
 	}
 	y := mat.GetDenseWorkspace(1, cols)
 	for j := 0; j < cols; j++ {
@@ -37,7 +34,7 @@ func (r *RowView) Forward() mat.Matrix {
 
 func (r *RowView) Backward(gy mat.Matrix) {
 	if !(r.x.Value().Columns() == gy.Size()) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("RowView", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.NewEmptyDense(r.x.Value().Dims())
@@ -48,3 +45,8 @@ func (r *RowView) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *RowView) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewRowView(tangentOf(r.x, tangent), r.i).Forward()
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &ScatterAdd{}
+
+// ScatterAdd adds the k-th row of src to the row of x at indices[k], returning a new matrix; it is the inverse of
+// IndexSelect. The indices can be repeated, in which case the rows are accumulated.
+type ScatterAdd struct {
+	x       Operand
+	indices []int
+	src     Operand
+}
+
+func NewScatterAdd(x Operand, indices []int, src Operand) *ScatterAdd {
+	return &ScatterAdd{x: x, indices: indices, src: src}
+}
+
+// Indices returns the indices of the rows the rows of src are added to.
+func (r *ScatterAdd) Indices() []int {
+	return r.indices
+}
+
+// Forward computes the output of the function.
+func (r *ScatterAdd) Forward() mat.Matrix {
+	r.checkDims()
+	y := mat.GetDenseWorkspace(r.x.Value().Dims())
+	y.SetData(r.x.Value().Data())
+	AddRowsInPlace(y, r.indices, r.src.Value())
+	return y
+}
+
+func (r *ScatterAdd) checkDims() {
+	if r.src.Value().Rows() != len(r.indices) || r.src.Value().Columns() != r.x.Value().Columns() {
+		panic(&mat.ShapeError{Op: "ScatterAdd", Shapes: [][]int{
+			{len(r.indices), r.x.Value().Columns()},
+			{r.src.Value().Rows(), r.src.Value().Columns()},
+		}})
+	}
+}
+
+// Backward computes the backward pass.
+func (r *ScatterAdd) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic(mat.NewShapeError("ScatterAdd", r.x.Value(), gy))
+	}
+	if r.x.RequiresGrad() {
+		r.x.PropagateGrad(gy)
+	}
+	if r.src.RequiresGrad() {
+		gsrc := NewIndexSelect(&constant{value: gy}, r.indices).Forward()
+		defer mat.ReleaseMatrix(gsrc)
+		r.src.PropagateGrad(gsrc)
+	}
+}
+
+// JVP computes the tangent of the output given the tangents of the operands.
+func (r *ScatterAdd) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewScatterAdd(tangentOf(r.x, tangent), r.indices, tangentOf(r.src, tangent)).Forward()
+}
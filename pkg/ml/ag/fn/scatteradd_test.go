@@ -0,0 +1,61 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestScatterAdd_Forward(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(3, 2, []float64{
+			0.1, 0.2,
+			0.3, 0.4,
+			0.5, 0.6,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	src := &variable{
+		value: mat.NewDense(2, 2, []float64{
+			1.0, 2.0,
+			3.0, 4.0,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewScatterAdd(x, []int{2, 2}, src)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		0.1, 0.2,
+		0.3, 0.4,
+		4.5, 6.6,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(3, 2, []float64{
+		1.0, 2.0,
+		3.0, 4.0,
+		5.0, 6.0,
+	}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		1.0, 2.0,
+		3.0, 4.0,
+		5.0, 6.0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(src.grad.Data(), []float64{
+		5.0, 6.0,
+		5.0, 6.0,
+	}, 1.0e-6) {
+		t.Error("The src-gradients don't match the expected values")
+	}
+}
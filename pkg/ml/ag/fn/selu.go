@@ -30,7 +30,7 @@ func (r *SeLU) Forward() mat.Matrix {
 
 func (r *SeLU) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("SeLU", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.GetDenseWorkspace(r.x.Value().Dims())
@@ -40,3 +40,9 @@ func (r *SeLU) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangent of x; the alpha and scale are constant as in the backward.
+// ty = f'(x) * tx
+func (r *SeLU) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return parametricTangent(seluDeriv, r.x, tangent, r.alpha.Value().Scalar(), r.scale.Value().Scalar())
+}
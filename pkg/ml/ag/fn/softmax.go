@@ -12,6 +12,7 @@ import (
 var _ Function = &Softmax{}
 
 // Single-input, softmax function.
+// If the input is a mini-batch matrix, the softmax is applied to each column independently.
 type Softmax struct {
 	x Operand
 	y mat.Matrix // initialized during the forward pass (required by the backward pass)
@@ -23,13 +24,23 @@ func NewSoftmax(x Operand) *Softmax {
 
 // Forward computes the output of this function.
 func (r *Softmax) Forward() mat.Matrix {
+	if x := r.x.Value(); !x.IsVector() {
+		r.y = softmaxColumns(x)
+		return r.y
+	}
 	r.y = mat.NewVecDense(softmax(r.x.Value().Data()))
 	return r.y
 }
 
 func (r *Softmax) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Softmax", r.x.Value(), gy))
+	}
+	if r.x.RequiresGrad() && !r.y.IsVector() {
+		gx := softmaxColumnsDeriv(r.y, gy)
+		defer mat.ReleaseDense(gx)
+		r.x.PropagateGrad(gx)
+		return
 	}
 	if r.x.RequiresGrad() {
 		n := r.y.Size()
@@ -45,7 +56,7 @@ func (r *Softmax) Backward(gy mat.Matrix) {
 			}
 		}
 		gx := jb.Mul(gy)
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 		r.x.PropagateGrad(gx)
 	}
 }
@@ -72,3 +83,57 @@ func softmax(v []float64) (sm []float64) {
 	}
 	return sm
 }
+
+// softmaxColumns returns a new matrix with the softmax of each column of x.
+func softmaxColumns(x mat.Matrix) *mat.Dense {
+	rows, cols := x.Dims()
+	y := mat.GetDenseWorkspace(rows, cols)
+	col := make([]float64, rows)
+	for j := 0; j < cols; j++ {
+		for i := range col {
+			col[i] = x.At(i, j)
+		}
+		for i, v := range softmax(col) {
+			y.Set(i, j, v)
+		}
+	}
+	return y
+}
+
+// softmaxColumnsDeriv returns the gradients of the column-wise softmax y with respect to its input.
+// gx = y * (gy - sum(y * gy)), computed for each column.
+func softmaxColumnsDeriv(y, gy mat.Matrix) *mat.Dense {
+	rows, cols := y.Dims()
+	gx := mat.GetDenseWorkspace(rows, cols)
+	for j := 0; j < cols; j++ {
+		dot := 0.0
+		for i := 0; i < rows; i++ {
+			dot += y.At(i, j) * gy.At(i, j)
+		}
+		for i := 0; i < rows; i++ {
+			gx.Set(i, j, y.At(i, j)*(gy.At(i, j)-dot))
+		}
+	}
+	return gx
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+// ty = y * (tx - sum(y * tx)), where the sum is column-wise to support mini-batches.
+func (r *Softmax) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangentOf(r.x, tangent).Value()
+	y := (&Softmax{x: r.x}).Forward() // r.y may have been released by the graph
+	defer mat.ReleaseMatrix(y)
+	rows, cols := y.Dims()
+	ty := mat.GetDenseWorkspace(rows, cols)
+	yData, txData, tyData := y.Data(), tx.Data(), ty.Data()
+	for j := 0; j < cols; j++ {
+		dot := 0.0
+		for i := 0; i < rows; i++ {
+			dot += yData[i*cols+j] * txData[i*cols+j]
+		}
+		for i := 0; i < rows; i++ {
+			tyData[i*cols+j] = yData[i*cols+j] * (txData[i*cols+j] - dot)
+		}
+	}
+	return ty
+}
@@ -29,3 +29,51 @@ func TestSoftmax_Forward(t *testing.T) {
 		t.Error("The x-gradients don't match the expected values")
 	}
 }
+
+func TestSoftmax_ForwardBatch(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(6, 2, []float64{
+			-0.41, 0.0,
+			-1.08, 0.0,
+			0, 0.0,
+			0.87, 0.0,
+			-0.19, 0.0,
+			-0.75, 0.0,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewSoftmax(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		0.1166451, 1.0 / 6.0,
+		0.0596882, 1.0 / 6.0,
+		0.1757629, 1.0 / 6.0,
+		0.4195304, 1.0 / 6.0,
+		0.1453487, 1.0 / 6.0,
+		0.083024, 1.0 / 6.0,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(6, 2, []float64{
+		0.0, 0.0,
+		0.0, 0.0,
+		-5.689482, 0.0,
+		0.0, 0.0,
+		0.0, 0.0,
+		0.0, 0.0,
+	}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		0.1166451, 0.0,
+		0.0596882, 0.0,
+		-0.8242370, 0.0,
+		0.4195304, 0.0,
+		0.1453487, 0.0,
+		0.083024, 0.0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
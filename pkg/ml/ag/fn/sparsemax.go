@@ -0,0 +1,90 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"sort"
+)
+
+var _ Function = &Sparsemax{}
+
+// Sparsemax is the euclidean projection of the input onto the probability simplex, which, unlike the softmax,
+// can assign zero probability to the lowest values.
+// If the input is a mini-batch matrix, the function is applied to each column independently.
+//
+// Reference: "From Softmax to Sparsemax: A Sparse Model of Attention and Multi-Label Classification"
+// by André F. T. Martins and Ramón Fernandez Astudillo, 2016 (https://arxiv.org/pdf/1602.02068.pdf)
+type Sparsemax struct {
+	x Operand
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+func NewSparsemax(x Operand) *Sparsemax {
+	return &Sparsemax{x: x}
+}
+
+// Forward computes the output of the function.
+func (r *Sparsemax) Forward() mat.Matrix {
+	rows, cols := columnsOf(r.x.Value())
+	y := mat.GetDenseWorkspace(r.x.Value().Dims())
+	xData, yData := r.x.Value().Data(), y.Data()
+	z := make([]float64, rows)
+	for j := 0; j < cols; j++ {
+		for i := range z {
+			z[i] = xData[i*cols+j]
+		}
+		tau := sparsemaxThreshold(z)
+		for i := 0; i < rows; i++ {
+			yData[i*cols+j] = max0(xData[i*cols+j] - tau)
+		}
+	}
+	r.y = y
+	return y
+}
+
+// Backward computes the backward pass.
+// gx = gy - mean(gy) on the support of y (i.e. where y > 0) and zero elsewhere, where the mean is column-wise
+// to support mini-batches.
+func (r *Sparsemax) Backward(gy mat.Matrix) {
+	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
+		panic(mat.NewShapeError("Sparsemax", r.x.Value(), gy))
+	}
+	if r.x.RequiresGrad() {
+		gx := entmaxColumnsDeriv(r.y, gy, 2.0)
+		defer mat.ReleaseDense(gx)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output given the tangent of the operand.
+// ty = tx - mean(tx) on the support of y and zero elsewhere, since the Jacobian is symmetric.
+func (r *Sparsemax) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangentOf(r.x, tangent).Value()
+	y := (&Sparsemax{x: r.x}).Forward() // r.y may have been released by the graph
+	defer mat.ReleaseMatrix(y)
+	return entmaxColumnsDeriv(y, tx, 2.0)
+}
+
+// sparsemaxThreshold returns the threshold τ such that the sum of max(z - τ, 0) is one.
+// It sorts z in place.
+func sparsemaxThreshold(z []float64) float64 {
+	sort.Sort(sort.Reverse(sort.Float64Slice(z)))
+	sum, tau := 0.0, 0.0
+	for k, v := range z {
+		sum += v
+		if t := (sum - 1.0) / float64(k+1); v > t {
+			tau = t
+		}
+	}
+	return tau
+}
+
+func max0(v float64) float64 {
+	if v > 0.0 {
+		return v
+	}
+	return 0.0
+}
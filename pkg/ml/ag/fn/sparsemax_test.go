@@ -0,0 +1,90 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestSparsemax_Forward(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{0.5, -0.3, 0.9, 0.1}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewSparsemax(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.3, 0.0, 0.7, 0.0}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewVecDense([]float64{0.2, -0.1, 0.4, 0.3}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{-0.1, 0.0, 0.1, 0.0}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestSparsemax_ForwardBatch(t *testing.T) {
+	x := &variable{
+		value: mat.NewDense(4, 2, []float64{
+			0.5, 3.0,
+			-0.3, -1.0,
+			0.9, 0.0,
+			0.1, 0.5,
+		}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewSparsemax(x)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{
+		0.3, 1.0,
+		0.0, 0.0,
+		0.7, 0.0,
+		0.0, 0.0,
+	}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(4, 2, []float64{
+		0.2, 0.5,
+		-0.1, 0.1,
+		0.4, 0.2,
+		0.3, -0.3,
+	}))
+
+	if !floats.EqualApprox(x.grad.Data(), []float64{
+		-0.1, 0.0,
+		0.0, 0.0,
+		0.1, 0.0,
+		0.0, 0.0,
+	}, 1.0e-6) {
+		t.Error("The x-gradients don't match the expected values")
+	}
+}
+
+func TestSparsemax_BackwardShapeError(t *testing.T) {
+	x := &variable{
+		value:        mat.NewVecDense([]float64{0.5, -0.3, 0.9, 0.1}),
+		requiresGrad: true,
+	}
+	f := NewSparsemax(x)
+	f.Forward()
+
+	var err error
+	func() {
+		defer mat.Recover(&err)
+		f.Backward(mat.NewVecDense([]float64{0.2, -0.1}))
+	}()
+	if !errors.Is(err, mat.ErrShapeMismatch) {
+		t.Errorf("Expected a shape mismatch, found %v", err)
+	}
+}
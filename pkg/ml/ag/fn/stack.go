@@ -31,13 +31,13 @@ func (r *Stack) Forward() mat.Matrix {
 
 func (r *Stack) Backward(gy mat.Matrix) {
 	if gy.Rows() != len(r.xs) {
-		panic("fn: matrices with not compatible size")
+		panic(&mat.ShapeError{Op: "Stack", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {len(r.xs), gy.Columns()}}})
 	}
 	sizes := make([]int, len(r.xs))
 	for i, x := range r.xs {
 		sizes[i] = x.Value().Size()
 		if !(sizes[i] == gy.Columns()) {
-			panic("fn: matrices with not compatible size")
+			panic(&mat.ShapeError{Op: "Stack", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {len(r.xs), sizes[i]}}})
 		}
 	}
 	xs := r.xs
@@ -45,6 +45,11 @@ func (r *Stack) Backward(gy mat.Matrix) {
 		if xs[i].RequiresGrad() {
 			xs[i].PropagateGrad(gx)
 		}
-		mat.ReleaseDense(gx.(*mat.Dense))
+		mat.ReleaseMatrix(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Stack) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewStack(tangentsOf(r.xs, tangent)).Forward()
+}
@@ -10,7 +10,7 @@ import (
 
 var _ Function = &Sub{}
 
-// Element-wise subtraction over two values.
+// Element-wise subtraction over two values, with broadcasting.
 type Sub struct {
 	x1 Operand
 	x2 Operand
@@ -24,25 +24,25 @@ func NewSub(x1, x2 Operand) *Sub {
 func (r *Sub) Forward() mat.Matrix {
 	x1v := r.x1.Value()
 	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, x2v) || mat.VectorsOfSameSize(x1v, x2v)) {
-		panic("fn: matrices with not compatible size")
-	}
-	return x1v.Sub(x2v)
+	x1b, x2b := broadcast(x1v, x2v)
+	defer releaseBroadcast(x1b, x1v)
+	defer releaseBroadcast(x2b, x2v)
+	return x1b.Sub(x2b)
 }
 
 func (r *Sub) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !(mat.SameDims(x1v, gy) || mat.VectorsOfSameSize(x1v, gy)) &&
-		!(mat.SameDims(x2v, gy) || mat.VectorsOfSameSize(x2v, gy)) {
-		panic("fn: matrices with not compatible size")
-	}
+	checkBroadcastGrad(r.x1.Value(), r.x2.Value(), gy)
 	if r.x1.RequiresGrad() {
-		r.x1.PropagateGrad(gy)
+		propagateBroadcastGrad(r.x1, gy)
 	}
 	if r.x2.RequiresGrad() {
 		gx := gy.ProdScalar(-1.0)
-		defer mat.ReleaseDense(gx.(*mat.Dense))
-		r.x2.PropagateGrad(gx)
+		defer mat.ReleaseMatrix(gx)
+		propagateBroadcastGrad(r.x2, gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Sub) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewSub(tangentOf(r.x1, tangent), tangentOf(r.x2, tangent)).Forward()
+}
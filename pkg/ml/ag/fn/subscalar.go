@@ -27,7 +27,7 @@ func (r *SubScalar) Forward() mat.Matrix {
 
 func (r *SubScalar) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x1.Value(), gy) || mat.VectorsOfSameSize(r.x1.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("SubScalar", r.x1.Value(), gy))
 	}
 	if r.x1.RequiresGrad() {
 		r.x1.PropagateGrad(gy) // equals to gy.ProdScalar(1.0)
@@ -44,3 +44,8 @@ func (r *SubScalar) Backward(gy mat.Matrix) {
 		r.x2.PropagateGrad(scalar)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *SubScalar) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewSubScalar(tangentOf(r.x1, tangent), tangentOf(r.x2, tangent)).Forward()
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &SumTo{}
+
+// SumTo sums the values of x along the dimensions over which a matrix of size rows×cols has been
+// broadcast to the dimensions of x (see the broadcasting rules of Add). It is the inverse of the broadcasting.
+type SumTo struct {
+	x    Operand
+	rows int
+	cols int
+}
+
+func NewSumTo(x Operand, rows, cols int) *SumTo {
+	return &SumTo{x: x, rows: rows, cols: cols}
+}
+
+// Forward computes the output of the function.
+func (r *SumTo) Forward() mat.Matrix {
+	xv := r.x.Value()
+	rows, ok1 := broadcastDim(xv.Rows(), r.rows)
+	cols, ok2 := broadcastDim(xv.Columns(), r.cols)
+	if !(ok1 && ok2 && rows == xv.Rows() && cols == xv.Columns()) {
+		panic(&mat.ShapeError{Op: "SumTo", Shapes: [][]int{{xv.Rows(), xv.Columns()}, {r.rows, r.cols}}})
+	}
+	y := reduceBroadcast(xv, r.rows, r.cols)
+	if y == xv {
+		return xv.Reshape(r.rows, r.cols)
+	}
+	return y
+}
+
+func (r *SumTo) Backward(gy mat.Matrix) {
+	if gy.Size() != r.rows*r.cols {
+		panic(&mat.ShapeError{Op: "SumTo", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {r.rows, r.cols}}})
+	}
+	if r.x.RequiresGrad() {
+		gx := expand(gy, r.x.Value().Rows(), r.x.Value().Columns())
+		defer releaseBroadcast(gx, gy)
+		r.x.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *SumTo) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewSumTo(tangentOf(r.x, tangent), r.rows, r.cols).Forward()
+}
@@ -31,7 +31,7 @@ func (r *Swish) Forward() mat.Matrix {
 
 func (r *Swish) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Swish", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.GetDenseWorkspace(r.x.Value().Dims())
@@ -48,3 +48,20 @@ func (r *Swish) Backward(gy mat.Matrix) {
 		r.beta.PropagateGrad(gb)
 	}
 }
+
+// JVP computes the tangent of the output given the tangents of x and beta.
+// ty = f'(x) * tx + df/dbeta * tbeta
+func (r *Swish) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	beta := r.beta.Value().Scalar()
+	ty := parametricTangent(swishDeriv, r.x, tangent, beta)
+	if tb := tangent(r.beta); tb != nil {
+		// ty may be a Dense32 (see parametricTangent), whose Data is a copy, so the term is added as a matrix
+		tbeta := mat.GetDenseWorkspace(r.x.Value().Dims())
+		tbData := tbeta.Data()
+		for i, x := range r.x.Value().Data() {
+			tbData[i] = swishBetaDeriv(x, beta) * tb.Scalar()
+		}
+		ty = sumTangents(ty, tbeta)
+	}
+	return ty
+}
@@ -38,3 +38,23 @@ func TestSwishForward(t *testing.T) {
 		t.Error("The beta-gradients don't match the expected values")
 	}
 }
+
+func TestSwish_JVPDense32(t *testing.T) {
+	data := []float64{0.1, -0.2, 0.3, 0.0}
+	beta := &variable{value: mat.NewScalar(2.0), requiresGrad: true}
+	tangent := func(x Operand) mat.Matrix {
+		if x == beta {
+			return mat.NewScalar(1.0)
+		}
+		return nil // the tangent of x is zero
+	}
+	expected := NewSwish(&variable{value: mat.NewVecDense(data), requiresGrad: true}, beta).JVP(tangent)
+	ty := NewSwish(&variable{value: mat.NewVecDense32(data), requiresGrad: true}, beta).JVP(tangent)
+
+	if floats.Norm(expected.Data(), 2) == 0 {
+		t.Fatal("The tangent with respect to beta should not be zero")
+	}
+	if !floats.EqualApprox(ty.Data(), expected.Data(), 1.0e-6) {
+		t.Error("The tangent of a Dense32 doesn't match the one of a Dense")
+	}
+}
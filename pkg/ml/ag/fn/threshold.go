@@ -30,7 +30,7 @@ func (r *Threshold) Forward() mat.Matrix {
 
 func (r *Threshold) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Threshold", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.GetDenseWorkspace(r.x.Value().Dims())
@@ -40,3 +40,9 @@ func (r *Threshold) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangent of x; the threshold and k are constant as in the backward.
+// ty = f'(x) * tx
+func (r *Threshold) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return parametricTangent(thresholdDeriv, r.x, tangent, r.threshold.Value().Scalar(), r.k.Value().Scalar())
+}
@@ -25,11 +25,16 @@ func (r *Transpose) Forward() mat.Matrix {
 
 func (r *Transpose) Backward(gy mat.Matrix) {
 	if r.x.Value().Columns() != gy.Rows() && r.x.Value().Rows() != gy.Columns() {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Transpose", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := gy.T()
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Transpose) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewTranspose(tangentOf(r.x, tangent)).Forward()
+}
@@ -26,7 +26,7 @@ func (r *UnaryElementwise) Forward() mat.Matrix {
 
 func (r *UnaryElementwise) Backward(gy mat.Matrix) {
 	if !(mat.SameDims(r.x.Value(), gy) || mat.VectorsOfSameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("UnaryElementwise", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.GetDenseWorkspace(r.x.Value().Dims())
@@ -36,3 +36,16 @@ func (r *UnaryElementwise) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output given the tangent of the operand.
+// ty = f'(x) * tx
+func (r *UnaryElementwise) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	tx := tangent(r.x)
+	if tx == nil {
+		return r.x.Value().ZerosLike()
+	}
+	ty := mat.GetDenseWorkspace(r.x.Value().Dims())
+	ty.Apply(r.df, r.x.Value())
+	ty.ProdInPlace(tx)
+	return ty
+}
@@ -25,11 +25,16 @@ func (r *Vec) Forward() mat.Matrix {
 
 func (r *Vec) Backward(gy mat.Matrix) {
 	if !(gy.IsVector() && mat.SameSize(r.x.Value(), gy)) {
-		panic("fn: matrices with not compatible size")
+		panic(mat.NewShapeError("Vec", r.x.Value(), gy))
 	}
 	if r.x.RequiresGrad() {
 		gx := gy.Reshape(r.x.Value().Dims())
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *Vec) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewVec(tangentOf(r.x, tangent)).Forward()
+}
@@ -35,7 +35,7 @@ func (r *View) Forward() mat.Matrix {
 
 func (r *View) Backward(gy mat.Matrix) {
 	if !(gy.Rows() == r.lx && gy.Columns() == r.ly) {
-		panic("fn: matrices with not compatible size")
+		panic(&mat.ShapeError{Op: "View", Shapes: [][]int{{gy.Rows(), gy.Columns()}, {r.lx, r.ly}}})
 	}
 	if r.x.RequiresGrad() {
 		gx := mat.NewEmptyDense(r.x.Value().Dims())
@@ -48,3 +48,8 @@ func (r *View) Backward(gy mat.Matrix) {
 		r.x.PropagateGrad(gx)
 	}
 }
+
+// JVP computes the tangent of the output, applying the linear function to the tangents of the operands.
+func (r *View) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return NewView(tangentOf(r.x, tangent), r.sx, r.sy, r.lx, r.ly).Forward()
+}
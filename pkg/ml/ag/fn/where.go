@@ -0,0 +1,81 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+)
+
+var _ Function = &Where{}
+
+// Where selects the values of x1 where the condition is not zero, and the ones of x2 elsewhere.
+// The gradients flow into the selected values only.
+type Where struct {
+	cond mat.Matrix
+	x1   Operand
+	x2   Operand
+}
+
+// NewWhere returns a new Where function. The condition and the operands must have the same dimensions.
+func NewWhere(cond mat.Matrix, x1, x2 Operand) *Where {
+	return &Where{cond: cond, x1: x1, x2: x2}
+}
+
+// Cond returns the condition selecting the values of x1.
+func (r *Where) Cond() mat.Matrix {
+	return r.cond
+}
+
+// Forward computes the output of the function.
+func (r *Where) Forward() mat.Matrix {
+	if !mat.SameDims(r.cond, r.x1.Value()) || !mat.SameDims(r.cond, r.x2.Value()) {
+		panic(mat.NewShapeError("Where", r.cond, r.x1.Value(), r.x2.Value()))
+	}
+	return where(r.cond, r.x1.Value(), r.x2.Value())
+}
+
+// where returns a new matrix with the values of a where the condition is not zero, and the ones of b elsewhere.
+// A nil matrix is treated as zeros.
+func where(cond, a, b mat.Matrix) *mat.Dense {
+	y := mat.GetEmptyDenseWorkspace(cond.Dims())
+	var aData, bData []float64
+	if a != nil {
+		aData = a.Data()
+	}
+	if b != nil {
+		bData = b.Data()
+	}
+	yData := y.Data()
+	for i, c := range cond.Data() {
+		if c != 0 && aData != nil {
+			yData[i] = aData[i]
+		} else if c == 0 && bData != nil {
+			yData[i] = bData[i]
+		}
+	}
+	return y
+}
+
+// Backward computes the backward pass.
+func (r *Where) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.cond, gy) {
+		panic(mat.NewShapeError("Where", r.cond, gy))
+	}
+	if r.x1.RequiresGrad() {
+		gx := where(r.cond, gy, nil)
+		defer mat.ReleaseDense(gx)
+		r.x1.PropagateGrad(gx)
+	}
+	if r.x2.RequiresGrad() {
+		gx := where(r.cond, nil, gy)
+		defer mat.ReleaseDense(gx)
+		r.x2.PropagateGrad(gx)
+	}
+}
+
+// JVP computes the tangent of the output given the tangents of the operands.
+func (r *Where) JVP(tangent func(x Operand) mat.Matrix) mat.Matrix {
+	return where(r.cond, tangent(r.x1), tangent(r.x2))
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestWhere_Forward(t *testing.T) {
+	x1 := &variable{
+		value:        mat.NewDense(2, 2, []float64{0.1, 0.2, 0.3, 0.4}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	x2 := &variable{
+		value:        mat.NewDense(2, 2, []float64{-0.1, -0.2, -0.3, -0.4}),
+		grad:         nil,
+		requiresGrad: true,
+	}
+	f := NewWhere(mat.NewDense(2, 2, []float64{1, 0, 0, 1}), x1, x2)
+	y := f.Forward()
+
+	if !floats.EqualApprox(y.Data(), []float64{0.1, -0.2, -0.3, 0.4}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	f.Backward(mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}))
+
+	if !floats.EqualApprox(x1.grad.Data(), []float64{1.0, 0.0, 0.0, 4.0}, 1.0e-6) {
+		t.Error("The x1-gradients don't match the expected values")
+	}
+	if !floats.EqualApprox(x2.grad.Data(), []float64{0.0, 2.0, 3.0, 0.0}, 1.0e-6) {
+		t.Error("The x2-gradients don't match the expected values")
+	}
+}
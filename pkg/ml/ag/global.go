@@ -94,6 +94,11 @@ func Identity(x Node) Node {
 	return globalGraph.Identity(x)
 }
 
+// StopGrad
+func StopGrad(x Node) Node {
+	return globalGraph.StopGrad(x)
+}
+
 // Dropout
 func Dropout(x Node, p float64) Node {
 	return globalGraph.Dropout(x, p)
@@ -159,6 +164,11 @@ func Mul(x1 Node, x2 Node) Node {
 	return globalGraph.Mul(x1, x2)
 }
 
+// Affine
+func Affine(activation OpName, xs ...Node) Node {
+	return globalGraph.Affine(activation, xs...)
+}
+
 // Dot
 func Dot(x1 Node, x2 Node) Node {
 	return globalGraph.Dot(x1, x2)
@@ -174,6 +184,51 @@ func MaxPooling(x Node, rows, columns int) Node {
 	return globalGraph.MaxPooling(x, rows, columns)
 }
 
+// MinPooling
+func MinPooling(x Node, rows, columns int) Node {
+	return globalGraph.MinPooling(x, rows, columns)
+}
+
+// AvgPooling
+func AvgPooling(x Node, rows, columns int) Node {
+	return globalGraph.AvgPooling(x, rows, columns)
+}
+
+// GlobalMaxPooling
+func GlobalMaxPooling(x Node) Node {
+	return globalGraph.GlobalMaxPooling(x)
+}
+
+// GlobalAvgPooling
+func GlobalAvgPooling(x Node) Node {
+	return globalGraph.GlobalAvgPooling(x)
+}
+
+// MaxPooling1D
+func MaxPooling1D(xs []Node, size, stride int) []Node {
+	return globalGraph.MaxPooling1D(xs, size, stride)
+}
+
+// MinPooling1D
+func MinPooling1D(xs []Node, size, stride int) []Node {
+	return globalGraph.MinPooling1D(xs, size, stride)
+}
+
+// AvgPooling1D
+func AvgPooling1D(xs []Node, size, stride int) []Node {
+	return globalGraph.AvgPooling1D(xs, size, stride)
+}
+
+// GlobalMaxPooling1D
+func GlobalMaxPooling1D(xs ...Node) Node {
+	return globalGraph.GlobalMaxPooling1D(xs...)
+}
+
+// GlobalAvgPooling1D
+func GlobalAvgPooling1D(xs ...Node) Node {
+	return globalGraph.GlobalAvgPooling1D(xs...)
+}
+
 // View
 func View(x Node, row, column, xStride, yStride int) Node {
 	return globalGraph.View(x, row, column, xStride, yStride)
@@ -189,6 +244,31 @@ func ColView(x Node, column int) Node {
 	return globalGraph.ColView(x, column)
 }
 
+// IndexSelect
+func IndexSelect(x Node, indices ...int) Node {
+	return globalGraph.IndexSelect(x, indices...)
+}
+
+// Gather
+func Gather(x Node, rows, cols []int) Node {
+	return globalGraph.Gather(x, rows, cols)
+}
+
+// ScatterAdd
+func ScatterAdd(x Node, indices []int, src Node) Node {
+	return globalGraph.ScatterAdd(x, indices, src)
+}
+
+// MaskedFill
+func MaskedFill(x Node, mask mat.Matrix, value float64) Node {
+	return globalGraph.MaskedFill(x, mask, value)
+}
+
+// Where
+func Where(cond mat.Matrix, x1, x2 Node) Node {
+	return globalGraph.Where(cond, x1, x2)
+}
+
 // Vec
 func Vec(x Node) Node {
 	return globalGraph.Vec(x)
@@ -299,6 +379,46 @@ func Softmax(x Node) Node {
 	return globalGraph.Softmax(x)
 }
 
+// LogSoftmax
+func LogSoftmax(x Node) Node {
+	return globalGraph.LogSoftmax(x)
+}
+
+// LogSumExp
+func LogSumExp(x Node) Node {
+	return globalGraph.LogSumExp(x)
+}
+
+// GELU
+func GELU(x Node) Node {
+	return globalGraph.GELU(x)
+}
+
+// GELUTanh
+func GELUTanh(x Node) Node {
+	return globalGraph.GELUTanh(x)
+}
+
+// Softmin
+func Softmin(x Node) Node {
+	return globalGraph.Softmin(x)
+}
+
+// Sparsemax
+func Sparsemax(x Node) Node {
+	return globalGraph.Sparsemax(x)
+}
+
+// Entmax
+func Entmax(x Node, alpha float64) Node {
+	return globalGraph.Entmax(x, alpha)
+}
+
+// Entmax15
+func Entmax15(x Node) Node {
+	return globalGraph.Entmax15(x)
+}
+
 // Sin
 func Sin(x Node) Node {
 	return globalGraph.Sin(x)
@@ -353,3 +473,23 @@ func Concat(xs ...Node) Node {
 func Stack(xs ...Node) Node {
 	return globalGraph.Stack(xs...)
 }
+
+// Permute
+func Permute(x Node, shape []int, axes ...int) Node {
+	return globalGraph.Permute(x, shape, axes...)
+}
+
+// ReduceSumAxis
+func ReduceSumAxis(x Node, shape []int, axis int) Node {
+	return globalGraph.ReduceSumAxis(x, shape, axis)
+}
+
+// ReduceMeanAxis
+func ReduceMeanAxis(x Node, shape []int, axis int) Node {
+	return globalGraph.ReduceMeanAxis(x, shape, axis)
+}
+
+// ReduceMaxAxis
+func ReduceMaxAxis(x Node, shape []int, axis int) Node {
+	return globalGraph.ReduceMaxAxis(x, shape, axis)
+}
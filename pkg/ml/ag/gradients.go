@@ -0,0 +1,431 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"math"
+	"reflect"
+)
+
+// GradFunc computes the gradients of the operands xs of the operator y as new nodes of the graph, given the node
+// gy of the gradients of y. It returns one node for each operand; a nil node means that no gradients flow into it.
+// Since the gradients are built with the operators of the graph, they can be differentiated again.
+type GradFunc func(g *Graph, y Node, xs []Node, gy Node) []Node
+
+// gradFuncs maps the types of the functions to the rules to compute the gradients as nodes of the graph.
+var gradFuncs = map[reflect.Type]GradFunc{
+	reflect.TypeOf(&fn.Identity{}):         identityGrad,
+	reflect.TypeOf(&fn.Add{}):              addGrad,
+	reflect.TypeOf(&fn.Sub{}):              subGrad,
+	reflect.TypeOf(&fn.Prod{}):             prodGrad,
+	reflect.TypeOf(&fn.Div{}):              divGrad,
+	reflect.TypeOf(&fn.AddScalar{}):        addScalarGrad,
+	reflect.TypeOf(&fn.SubScalar{}):        subScalarGrad,
+	reflect.TypeOf(&fn.ReverseSubScalar{}): reverseSubScalarGrad,
+	reflect.TypeOf(&fn.ProdScalar{}):       prodScalarGrad,
+	reflect.TypeOf(&fn.DivScalar{}):        divScalarGrad,
+	reflect.TypeOf(&fn.Mul{}):              mulGrad,
+	reflect.TypeOf(&fn.Dot{}):              dotGrad,
+	reflect.TypeOf(&fn.Transpose{}):        transposeGrad,
+	reflect.TypeOf(&fn.Reshape{}):          reshapeGrad,
+	reflect.TypeOf(&fn.Vec{}):              reshapeGrad,
+	reflect.TypeOf(&fn.ReduceSum{}):        reduceSumGrad,
+	reflect.TypeOf(&fn.ReduceMean{}):       reduceMeanGrad,
+	reflect.TypeOf(&fn.SumTo{}):            sumToGrad,
+	reflect.TypeOf(&fn.Concat{}):           concatGrad,
+	reflect.TypeOf(&fn.Stack{}):            stackGrad,
+	reflect.TypeOf(&fn.Softmax{}):          softmaxGrad,
+	reflect.TypeOf(&fn.LogSoftmax{}):       logSoftmaxGrad,
+	reflect.TypeOf(&fn.LogSumExp{}):        logSumExpGrad,
+	reflect.TypeOf(&fn.IndexSelect{}):      indexSelectGrad,
+	reflect.TypeOf(&fn.Gather{}):           gatherGrad,
+	reflect.TypeOf(&fn.ScatterAdd{}):       scatterAddGrad,
+	reflect.TypeOf(&fn.MaskedFill{}):       maskedFillGrad,
+	reflect.TypeOf(&fn.Where{}):            whereGrad,
+	reflect.TypeOf(&fn.Affine{}):           affineGrad,
+}
+
+// elementwiseGradFuncs maps the names of the operators whose function type is shared with others
+// (i.e. fn.UnaryElementwise) to the rules to compute their higher-order gradients.
+var elementwiseGradFuncs = map[OpName]GradFunc{
+	OpSqrt:       sqrtGrad,
+	OpTanh:       tanhGrad,
+	OpSigmoid:    sigmoidGrad,
+	OpReLU:       reluGrad,
+	OpSin:        sinGrad,
+	OpCos:        cosGrad,
+	OpExp:        expGrad,
+	OpLog:        logGrad,
+	OpAbs:        absGrad,
+	OpNeg:        negGrad,
+	OpReciprocal: reciprocalGrad,
+}
+
+// RegisterGradFunc registers the rule to compute the higher-order gradients of all the functions of the same type
+// of f (see Gradients). It is meant to be called during the initialization of the program.
+func RegisterGradFunc(f fn.Function, gradFunc GradFunc) {
+	gradFuncs[reflect.TypeOf(f)] = gradFunc
+}
+
+// Gradients returns the gradients of y with respect to the nodes xs. Unlike Backward, the gradients are computed
+// as new nodes of the graph, so that they can be differentiated again, e.g. to build gradient penalties,
+// meta-learning updates or Hessian-vector products. The optional node gy contains the gradients of y; if it
+// is not given, the derivative of y with respect to itself is used (dy/dy = 1).
+// The gradients of the nodes which y doesn't depend on are zeros. The accumulated gradients of the nodes are
+// not modified. It panics if an operator whose function doesn't have a GradFunc (see RegisterGradFunc) is visited.
+func (g *Graph) Gradients(y Node, xs []Node, gy ...Node) []Node {
+	grads := make(map[int64]Node)
+	if len(gy) > 1 {
+		panic("ag: invalid number of arguments. Required zero or one argument.")
+	} else if len(gy) == 0 || gy[0] == nil {
+		grads[y.Id()] = g.NewVariable(y.Value().OnesLike(), false)
+	} else {
+		grads[y.Id()] = gy[0]
+	}
+	for i := y.Id(); i >= 0; i-- {
+		g.mu.Lock()
+		node := g.nodes[i] // the new nodes are appended to the graph during the visit
+		g.mu.Unlock()
+		op, ok := node.(*operator)
+		if !ok || !op.requiresGrad {
+			continue
+		}
+		gn, ok := grads[i]
+		if !ok {
+			continue
+		}
+		gxs := op.gradFuncOrPanic()(g, op, op.operands, gn)
+		for j, x := range op.operands {
+			if gxs[j] == nil || !x.RequiresGrad() {
+				continue
+			}
+			if prev, ok := grads[x.Id()]; ok {
+				grads[x.Id()] = g.Add(prev, gxs[j])
+			} else {
+				grads[x.Id()] = gxs[j]
+			}
+		}
+	}
+	out := make([]Node, len(xs))
+	for i, x := range xs {
+		if gx, ok := grads[x.Id()]; ok {
+			out[i] = gx
+		} else {
+			out[i] = g.NewVariable(x.Value().ZerosLike(), false)
+		}
+	}
+	return out
+}
+
+func (r *operator) gradFuncOrPanic() GradFunc {
+	if _, ok := r.function.(*fn.UnaryElementwise); ok {
+		if gradFunc, ok := elementwiseGradFuncs[r.opName]; ok {
+			return gradFunc
+		}
+	}
+	if gradFunc, ok := gradFuncs[reflect.TypeOf(r.function)]; ok {
+		return gradFunc
+	}
+	panic(fmt.Sprintf("ag: higher-order gradients not supported by %T", r.function))
+}
+
+// sumTo sums x to the dimensions of m, if x has the dimensions of a broadcast of m; otherwise it returns x.
+func (g *Graph) sumTo(x Node, m mat.Matrix) Node {
+	if x.Value().Size() == m.Size() {
+		return x
+	}
+	return g.NewOperator(fn.NewSumTo(x, m.Rows(), m.Columns()), x)
+}
+
+// constant returns a new variable that doesn't require gradients, with the dimensions of m and
+// the values obtained applying f to the values of m.
+func (g *Graph) constant(m mat.Matrix, f func(v float64) float64) Node {
+	out := mat.NewEmptyDense(m.Dims())
+	out.Apply(func(i, j int, v float64) float64 { return f(v) }, m)
+	return g.NewVariable(out, false)
+}
+
+func identityGrad(_ *Graph, _ Node, _ []Node, gy Node) []Node {
+	return []Node{gy}
+}
+
+func addGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.sumTo(gy, xs[0].Value()), g.sumTo(gy, xs[1].Value())}
+}
+
+func subGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.sumTo(gy, xs[0].Value()), g.sumTo(g.Neg(gy), xs[1].Value())}
+}
+
+func prodGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	if len(xs) == 1 { // square
+		return []Node{g.ProdScalar(g.Prod(gy, xs[0]), g.NewScalar(2.0))}
+	}
+	return []Node{
+		g.sumTo(g.Prod(gy, xs[1]), xs[0].Value()),
+		g.sumTo(g.Prod(gy, xs[0]), xs[1].Value()),
+	}
+}
+
+func divGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{
+		g.sumTo(g.Div(gy, xs[1]), xs[0].Value()),
+		g.sumTo(g.Neg(g.Div(g.Prod(gy, xs[0]), g.Square(xs[1]))), xs[1].Value()),
+	}
+}
+
+func addScalarGrad(g *Graph, _ Node, _ []Node, gy Node) []Node {
+	return []Node{gy, g.ReduceSum(gy)}
+}
+
+func subScalarGrad(g *Graph, _ Node, _ []Node, gy Node) []Node {
+	return []Node{gy, g.Neg(g.ReduceSum(gy))}
+}
+
+func reverseSubScalarGrad(g *Graph, _ Node, _ []Node, gy Node) []Node {
+	return []Node{g.Neg(gy), g.ReduceSum(gy)}
+}
+
+func prodScalarGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.ProdScalar(gy, xs[1]), g.ReduceSum(g.Prod(gy, xs[0]))}
+}
+
+func divScalarGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{
+		g.DivScalar(gy, xs[1]),
+		g.Neg(g.DivScalar(g.ReduceSum(g.Prod(gy, xs[0])), g.Square(xs[1]))),
+	}
+}
+
+func mulGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Mul(gy, g.T(xs[1])), g.Mul(g.T(xs[0]), gy)}
+}
+
+func dotGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.ProdScalar(xs[1], gy), g.ProdScalar(xs[0], gy)}
+}
+
+func transposeGrad(g *Graph, _ Node, _ []Node, gy Node) []Node {
+	return []Node{g.T(gy)}
+}
+
+func reshapeGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Reshape(gy, xs[0].Value().Rows(), xs[0].Value().Columns())}
+}
+
+func reduceSumGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.ProdScalar(g.constant(xs[0].Value(), func(_ float64) float64 { return 1.0 }), gy)}
+}
+
+func reduceMeanGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	n := float64(xs[0].Value().Size())
+	return []Node{g.ProdScalar(g.constant(xs[0].Value(), func(_ float64) float64 { return 1.0 / n }), gy)}
+}
+
+func sumToGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Add(g.constant(xs[0].Value(), func(_ float64) float64 { return 0.0 }), gy)} // broadcast
+}
+
+func concatGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	gxs := make([]Node, len(xs))
+	offset := 0
+	for i, x := range xs {
+		rows := x.Value().Size() / gy.Value().Columns()
+		gxs[i] = g.Reshape(g.View(gy, offset, 0, rows, gy.Value().Columns()), x.Value().Rows(), x.Value().Columns())
+		offset += rows
+	}
+	return gxs
+}
+
+func stackGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	gxs := make([]Node, len(xs))
+	for i, x := range xs {
+		gxs[i] = g.Reshape(g.RowView(gy, i), x.Value().Rows(), x.Value().Columns())
+	}
+	return gxs
+}
+
+// indexSelectGrad returns the rule to compute the gradients of IndexSelect, scattering gy to the selected rows.
+func indexSelectGrad(g *Graph, y Node, xs []Node, gy Node) []Node {
+	indices := functionOf(y).(*fn.IndexSelect).Indices()
+	zeros := g.constant(xs[0].Value(), func(_ float64) float64 { return 0.0 })
+	return []Node{g.ScatterAdd(zeros, indices, gy)}
+}
+
+// gatherGrad returns the rule to compute the gradients of Gather, scattering gy to the gathered elements of
+// the flattened operand.
+func gatherGrad(g *Graph, y Node, xs []Node, gy Node) []Node {
+	rows, cols := functionOf(y).(*fn.Gather).Indices()
+	r, c := xs[0].Value().Dims()
+	offsets := make([]int, len(rows))
+	for k := range rows {
+		offsets[k] = rows[k]*c + cols[k]
+	}
+	zeros := g.NewVariable(mat.NewEmptyVecDense(r*c), false)
+	return []Node{g.Reshape(g.ScatterAdd(zeros, offsets, gy), r, c)}
+}
+
+// scatterAddGrad returns the rule to compute the gradients of ScatterAdd, selecting the rows of gy for src.
+func scatterAddGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	indices := functionOf(y).(*fn.ScatterAdd).Indices()
+	return []Node{gy, g.IndexSelect(gy, indices...)}
+}
+
+// maskedFillGrad returns the rule to compute the gradients of MaskedFill, which don't flow into the replaced values.
+func maskedFillGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	mask := functionOf(y).(*fn.MaskedFill).Mask()
+	return []Node{g.MaskedFill(gy, mask, 0.0)}
+}
+
+// whereGrad returns the rule to compute the gradients of Where, which flow into the selected values only.
+func whereGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	cond := functionOf(y).(*fn.Where).Cond()
+	zeros := g.constant(gy.Value(), func(_ float64) float64 { return 0.0 })
+	return []Node{g.Where(cond, gy, zeros), g.Where(cond, zeros, gy)}
+}
+
+// affineGrad computes the gradients of the affine transformation, differentiating the fused activation, if any,
+// by its own rule. It panics if the activation doesn't have higher-order gradients.
+func affineGrad(g *Graph, y Node, xs []Node, gy Node) []Node {
+	var b Node
+	pairs := xs
+	hasBias := len(xs)%2 == 1
+	if hasBias {
+		b, pairs = xs[0], xs[1:]
+	}
+	gz := gy
+	if activation, ok := functionOf(y).(*fn.Affine).Activation(); ok {
+		actGrad := fusedActivationGrad(activation)
+		if actGrad == nil {
+			panic("ag: higher-order gradients not supported by the activation of *fn.Affine")
+		}
+		z := g.Affine(OpIdentity, append([]Node{b}, pairs...)...)
+		gz = actGrad(g, y, []Node{z}, gy)[0]
+	}
+	gxs := make([]Node, 0, len(xs))
+	if hasBias {
+		gxs = append(gxs, g.sumTo(gz, b.Value()))
+	}
+	for i := 0; i < len(pairs); i += 2 {
+		gxs = append(gxs, g.Mul(gz, g.T(pairs[i+1])), g.Mul(g.T(pairs[i]), gz))
+	}
+	return gxs
+}
+
+// fusedActivationGrad returns the rule to compute the gradients of the fused activation, or nil if it has none.
+func fusedActivationGrad(activation fn.Activation) GradFunc {
+	for _, fused := range fusedActivations {
+		if fused.activation.Equal(activation) {
+			return fused.grad
+		}
+	}
+	return nil
+}
+
+// functionOf returns the function of the operator.
+func functionOf(y Node) fn.Function {
+	return y.(*operator).function
+}
+
+// softmaxGrad computes y ⊙ (gy - sum(y ⊙ gy)), where the sum is column-wise to support mini-batches.
+func softmaxGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	sumRows := g.NewVariable(mat.NewInitDense(1, y.Value().Rows(), 1.0), false)
+	return []Node{g.Prod(y, g.Sub(gy, g.Mul(sumRows, g.Prod(y, gy))))}
+}
+
+// logSoftmaxGrad computes gy - exp(y) ⊙ sum(gy), where the sum is column-wise to support mini-batches.
+func logSoftmaxGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	sumRows := g.NewVariable(mat.NewInitDense(1, y.Value().Rows(), 1.0), false)
+	return []Node{g.Sub(gy, g.Prod(g.Exp(y), g.Mul(sumRows, gy)))}
+}
+
+// logSumExpGrad computes exp(x - y) ⊙ gy, broadcasting the output of each column. As in fn.LogSumExp, the gradients
+// of the columns whose values are all -Inf are zero rather than NaN.
+func logSumExpGrad(g *Graph, y Node, xs []Node, gy Node) []Node {
+	diff := g.Sub(xs[0], y)
+	if mask := negInfColumns(xs[0].Value(), y.Value()); mask != nil {
+		diff = g.MaskedFill(diff, mask, math.Inf(-1))
+	}
+	return []Node{g.Prod(g.Exp(diff), gy)}
+}
+
+// negInfColumns returns the mask of the values of x in the columns whose log-sum-exp y is -Inf, or nil if there
+// are none.
+func negInfColumns(x, y mat.Matrix) mat.Matrix {
+	var mask mat.Matrix
+	yData := y.Data()
+	for i := 0; i < x.Rows(); i++ {
+		for j := 0; j < x.Columns(); j++ {
+			if math.IsInf(yData[j%len(yData)], -1) {
+				if mask == nil {
+					mask = mat.NewEmptyDense(x.Dims())
+				}
+				mask.Set(i, j, 1.0)
+			}
+		}
+	}
+	return mask
+}
+
+func tanhGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	return []Node{g.Prod(gy, g.ReverseSub(g.Square(y), g.NewScalar(1.0)))}
+}
+
+func sigmoidGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	return []Node{g.Prod(gy, g.Prod(y, g.ReverseSub(y, g.NewScalar(1.0))))}
+}
+
+func expGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	return []Node{g.Prod(gy, y)}
+}
+
+func logGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Div(gy, xs[0])}
+}
+
+func sinGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Prod(gy, g.Cos(xs[0]))}
+}
+
+func cosGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Neg(g.Prod(gy, g.Sin(xs[0])))}
+}
+
+func negGrad(g *Graph, _ Node, _ []Node, gy Node) []Node {
+	return []Node{g.Neg(gy)}
+}
+
+func reciprocalGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	return []Node{g.Neg(g.Prod(gy, g.Square(y)))}
+}
+
+func sqrtGrad(g *Graph, y Node, _ []Node, gy Node) []Node {
+	return []Node{g.Div(gy, g.ProdScalar(y, g.NewScalar(2.0)))}
+}
+
+func reluGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Prod(gy, g.constant(xs[0].Value(), func(v float64) float64 {
+		if v > 0 {
+			return 1.0
+		}
+		return 0.0
+	}))}
+}
+
+func absGrad(g *Graph, _ Node, xs []Node, gy Node) []Node {
+	return []Node{g.Prod(gy, g.constant(xs[0].Value(), func(v float64) float64 {
+		switch {
+		case v > 0:
+			return 1.0
+		case v < 0:
+			return -1.0
+		default:
+			return 0.0
+		}
+	}))}
+}
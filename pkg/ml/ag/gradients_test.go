@@ -0,0 +1,148 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"math"
+	"testing"
+)
+
+func TestGraph_Gradients(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1.0, 2.0}), true)
+	y := g.ReduceSum(g.Prod(g.Square(x), x)) // sum(x^3)
+
+	gx := g.Gradients(y, []Node{x})[0]
+	if !floats.EqualApprox(gx.Value().Data(), []float64{3.0, 12.0}, 1.0e-6) {
+		t.Error("The gradients don't match the expected values")
+	}
+	if x.HasGrad() {
+		t.Error("The accumulated gradients mustn't be modified")
+	}
+
+	// Hessian-vector product
+	v := g.NewVariable(mat.NewVecDense([]float64{1.0, -1.0}), false)
+	hv := g.Gradients(g.Dot(gx, v), []Node{x})[0]
+	if !floats.EqualApprox(hv.Value().Data(), []float64{6.0, -12.0}, 1.0e-6) {
+		t.Error("The Hessian-vector product doesn't match the expected values")
+	}
+
+	// gradient penalty
+	g.Backward(g.ReduceSum(g.Square(gx))) // sum(9x^4)
+	if !floats.EqualApprox(x.Grad().Data(), []float64{36.0, 288.0}, 1.0e-6) {
+		t.Error("The gradients of the gradients don't match the expected values")
+	}
+}
+
+func TestGraph_GradientsMatchBackward(t *testing.T) {
+	g := NewGraph()
+	w := g.NewVariable(mat.NewDense(3, 2, []float64{0.1, -0.2, 0.3, 0.4, -0.5, 0.6}), true)
+	x := g.NewVariable(mat.NewDense(2, 2, []float64{0.7, -0.8, 0.9, 0.2}), true)
+	b := g.NewVariable(mat.NewDense(1, 2, []float64{0.1, 0.2}), true)
+	h := g.Tanh(g.Add(g.Mul(w, x), b))
+	h = g.Concat(g.Softmax(h), g.Sigmoid(h))
+	h = g.Div(g.Exp(h), g.AddScalar(g.Sqrt(g.Square(h)), g.NewScalar(1.0)))
+	y := g.ReduceMean(g.DivScalar(g.Log(g.ProdScalar(h, g.NewScalar(2.0))), g.NewScalar(3.0)))
+
+	gs := g.Gradients(y, []Node{w, x, b})
+	g.Backward(y)
+	for i, n := range []Node{w, x, b} {
+		if !floats.EqualApprox(gs[i].Value().Data(), n.Grad().Data(), 1.0e-6) {
+			t.Errorf("The gradients of the node %d don't match the back-propagation", i)
+		}
+	}
+}
+
+func TestGraph_GradientsNotSupported(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Gradients should panic with a function without GradFunc")
+		}
+	}()
+	g := NewGraph()
+	x := g.NewVariable(mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}), true)
+	g.Gradients(g.ReduceSum(g.MaxPooling(x, 1, 1)), []Node{x})
+}
+
+func TestGraph_GradientsLogSoftmax(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewDense(3, 2, []float64{0.1, -0.2, 0.3, 0.4, -0.5, 0.6}), true)
+	w := g.NewVariable(mat.NewDense(3, 2, []float64{0.7, -0.8, 0.9, 0.2, 0.5, -0.1}), false)
+	y := g.Add(g.ReduceSum(g.Prod(g.LogSoftmax(x), w)), g.ReduceSum(g.Square(g.LogSumExp(x))))
+
+	gx := g.Gradients(y, []Node{x})[0]
+	tx := mat.NewDense(3, 2, []float64{0.3, 0.1, -0.2, 0.5, 0.4, -0.1})
+	ty := g.JVP([]Node{y}, []Node{x}, []mat.Matrix{tx})[0]
+	g.Backward(y)
+	if !floats.EqualApprox(gx.Value().Data(), x.Grad().Data(), 1.0e-6) {
+		t.Error("The gradients don't match the back-propagation")
+	}
+	if !floats.EqualApprox(ty.Data(), []float64{floats.Dot(x.Grad().Data(), tx.Data())}, 1.0e-6) {
+		t.Error("The Jacobian-vector product doesn't match the back-propagation")
+	}
+}
+
+func TestGraph_GradientsLogSumExpNegInf(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewDense(2, 2, []float64{0.1, math.Inf(-1), 0.3, math.Inf(-1)}), true)
+	y := g.ReduceSum(g.Prod(g.LogSumExp(x), g.NewVariable(mat.NewDense(1, 2, []float64{0.5, 0}), false)))
+
+	gx := g.Gradients(y, []Node{x})[0]
+	g.Backward(y)
+	for _, v := range gx.Value().Data() {
+		if math.IsNaN(v) {
+			t.Fatal("The gradients of the -Inf column are NaN")
+		}
+	}
+	if !floats.Equal(gx.Value().Data(), x.Grad().Data()) {
+		t.Error("The gradients don't match the back-propagation")
+	}
+}
+
+func TestGraph_GradientsIndexing(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewDense(3, 2, []float64{0.1, -0.2, 0.3, 0.4, -0.5, 0.6}), true)
+	src := g.NewVariable(mat.NewDense(2, 2, []float64{0.7, -0.8, 0.9, 0.2}), true)
+	h := g.ScatterAdd(g.Square(x), []int{1, 1}, g.Square(src))
+	h = g.Add(g.IndexSelect(h, 2, 1, 1), g.IndexSelect(g.Tanh(x), 0, 0, 2))
+	y := g.ReduceSum(g.Square(g.Gather(h, []int{0, 1, 2, 1}, []int{1, 0, 1, 0})))
+
+	gs := g.Gradients(y, []Node{x, src})
+	tangents := []mat.Matrix{
+		mat.NewDense(3, 2, []float64{0.3, 0.1, -0.2, 0.5, 0.4, -0.1}),
+		mat.NewDense(2, 2, []float64{-0.6, 0.2, 0.1, 0.3}),
+	}
+	ty := g.JVP([]Node{y}, []Node{x, src}, tangents)[0]
+	g.Backward(y)
+	expected := 0.0
+	for i, n := range []Node{x, src} {
+		if !floats.EqualApprox(gs[i].Value().Data(), n.Grad().Data(), 1.0e-6) {
+			t.Errorf("The gradients of the node %d don't match the back-propagation", i)
+		}
+		expected += floats.Dot(n.Grad().Data(), tangents[i].Data())
+	}
+	if !floats.EqualApprox(ty.Data(), []float64{expected}, 1.0e-6) {
+		t.Error("The Jacobian-vector product doesn't match the back-propagation")
+	}
+}
+
+func TestGraph_GradientsMasking(t *testing.T) {
+	g := NewGraph()
+	x1 := g.NewVariable(mat.NewDense(2, 2, []float64{0.1, -0.2, 0.3, 0.4}), true)
+	x2 := g.NewVariable(mat.NewDense(2, 2, []float64{0.7, -0.8, 0.9, 0.2}), true)
+	cond := mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	h := g.Where(cond, g.Square(x1), g.Tanh(x2))
+	y := g.ReduceSum(g.Square(g.MaskedFill(g.Prod(h, x1), mat.NewDense(2, 2, []float64{0, 0, 1, 0}), -1.0)))
+
+	gs := g.Gradients(y, []Node{x1, x2})
+	g.Backward(y)
+	for i, n := range []Node{x1, x2} {
+		if !floats.EqualApprox(gs[i].Value().Data(), n.Grad().Data(), 1.0e-6) {
+			t.Errorf("The gradients of the node %d don't match the back-propagation", i)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/mat/rand"
 	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
 	"sync"
 	"sync/atomic"
 )
@@ -23,6 +24,18 @@ type Graph struct {
 	nodes []Node
 	// randGen is the generator of random numbers
 	randGen *rand.LockedRand
+	// noGrad is greater than zero while the graph is in no-grad mode (see WithNoGrad).
+	// It is shared by all the goroutines building the graph.
+	noGrad int32
+	// backwardWorkers is the number of workers of the concurrent back-propagation (see ConcurrentBackward)
+	backwardWorkers int
+	// profiler collects the statistics of the operators, if not nil (see Profile)
+	profiler *Profiler
+	// checkNumerics enables the detection of NaN and Inf values, reported to the handler (see CheckNumerics)
+	checkNumerics   bool
+	numericsHandler func(err *NumericError)
+	// dtype is the data type of the values of the variables (see DType)
+	dtype mat.DType
 }
 
 type GraphOption func(*Graph)
@@ -33,8 +46,22 @@ func Rand(rand *rand.LockedRand) GraphOption {
 	}
 }
 
+// DType sets the data type of the values of the variables of the graph (e.g. the inputs), which are converted on
+// creation if needed. Along with nn.SetDType, which sets the data type of the parameters of a model, mat.Float32 lets
+// the operators compute in single precision where the operations between Dense32 are available; their outputs
+// are not converted, since the functions may retain them for the backward.
+// Only the arithmetic operators (Add, Sub, Prod, Div, Mul, their scalar variants, Square and Pow) and the ones that
+// pass their input through (Identity, StopGrad, Dropout, Reshape, T and Vec) keep float32 values; the others, like
+// the activations, the reductions and the selections, return a Dense, as do the gradients.
+func DType(t mat.DType) GraphOption {
+	return func(g *Graph) {
+		g.dtype = t
+	}
+}
+
 // NewGraph returns a new initialized graph.
-// It can take an optional random generator of type rand.Rand.
+// It can take an optional random generator of type rand.Rand; in deterministic mode, the default one is seeded
+// with the seed of the mode (see determinism.Enable).
 func NewGraph(opts ...GraphOption) *Graph {
 	g := &Graph{
 		maxId:       -1,
@@ -44,7 +71,9 @@ func NewGraph(opts ...GraphOption) *Graph {
 	for _, opt := range opts {
 		opt(g)
 	}
-	if g.randGen == nil {
+	if g.randGen == nil && determinism.Enabled() {
+		g.randGen = rand.NewLockedRand(determinism.Seed())
+	} else if g.randGen == nil {
 		g.randGen = rand.NewLockedRand(1) // set default random generator
 	}
 	return g
@@ -66,7 +95,7 @@ func (g *Graph) Clear() {
 	}
 	g.maxId = -1
 	g.curTimeStep = 0
-	g.releaseMemory()
+	g.releaseMemory(false)
 	g.nodes = nil
 }
 
@@ -79,16 +108,22 @@ func (g *Graph) ClearForReuse() {
 	if g.nodes == nil {
 		return
 	}
-	g.releaseMemory()
+	g.releaseMemory(true)
 }
 
 // releaseMemory clears the values and the gradients of operator nodes.
 // Since the values and the gradients within the nodes are handled through a pool of dense matrices,
 // releasing them allows the memory to be reused without being reallocated, improving performance.
-func (g *Graph) releaseMemory() {
+// If reuse is true, the values of the operators that cannot be recomputed (see WithNoGrad) are kept.
+func (g *Graph) releaseMemory(reuse bool) {
 	for _, node := range g.nodes {
 		if node, ok := node.(*operator); ok {
-			g.releaseValue(node)
+			if !reuse || node.function != nil {
+				g.releaseValue(node)
+			}
+			if f, ok := node.function.(fn.Releaser); ok {
+				f.Release()
+			}
 			g.releaseGrad(node)
 		}
 	}
@@ -98,7 +133,7 @@ func (g *Graph) releaseValue(node *operator) {
 	if node.value == nil {
 		return
 	}
-	mat.ReleaseDense(node.value.(*mat.Dense))
+	mat.ReleaseMatrix(node.value)
 	node.value = nil
 }
 
@@ -113,7 +148,9 @@ func (g *Graph) ZeroGrad() {
 }
 
 // NewVariable creates e returns a new node.
+// If the data type of the value differs from the one of the graph, the node holds a converted copy (see DType).
 func (g *Graph) NewVariable(value mat.Matrix, requiresGrad bool) Node {
+	value = g.convert(value)
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	newNode := &variable{
@@ -136,29 +173,51 @@ func (g *Graph) NewScalar(value float64) Node {
 }
 
 // NewOperator creates a new operator along with its forward pass.
-// Please note that operations must be performed among nodes belonging to the same graph; it panics otherwise.
+// Please note that operations must be performed among nodes belonging to the same graph; it panics with
+// ErrGraphMismatch otherwise (see Try).
 func (g *Graph) NewOperator(f fn.Function, operands ...Node) Node {
+	return g.newOperator(opUnknown, f, requireGrad(operands), operands...)
+}
+
+// newOperatorOf creates a new operator recording the OpName it has been created by (see WriteDOT).
+func (g *Graph) newOperatorOf(op OpName, f fn.Function, operands ...Node) Node {
+	return g.newOperator(op, f, requireGrad(operands), operands...)
+}
+
+// newOperator creates a new operator along with its forward pass.
+// In no-grad mode the operator doesn't require gradients, and the function and the operands are not retained.
+func (g *Graph) newOperator(op OpName, f fn.Function, requiresGrad bool, operands ...Node) Node {
 	for _, o := range operands {
 		if o.Graph() != g {
-			panic("ag: operations cannot be executed among nodes of different graphs. " +
-				"You may consider wrapping the nodes you need with NewWrap().")
+			panic(ErrGraphMismatch)
 		}
 	}
-	value := f.Forward() // the calculation can be concurrent
-	g.mu.Lock()
-	defer g.mu.Unlock()
+	value := g.forward(op, f, operands) // the calculation can be concurrent
 	newNode := &operator{
 		graph:        g,
-		timeStep:     g.curTimeStep,
-		id:           g.newId(),
+		opName:       op,
 		function:     f,
+		operands:     operands,
 		value:        value,
 		grad:         nil,
 		hasGrad:      false,
-		requiresGrad: requireGrad(operands),
+		requiresGrad: requiresGrad,
+	}
+	if atomic.LoadInt32(&g.noGrad) > 0 {
+		if f, ok := f.(fn.Releaser); ok {
+			f.Release() // the backward will never be performed
+		}
+		newNode.function, newNode.operands, newNode.requiresGrad = nil, nil, false
 	}
+	g.mu.Lock()
+	newNode.timeStep = g.curTimeStep
+	newNode.id = g.newId()
 	// the new id is sequential so this the append is fine
 	g.nodes = append(g.nodes, newNode)
+	g.mu.Unlock()
+	if g.checkNumerics {
+		g.checkForward(newNode, f, operands)
+	}
 	return newNode
 }
 
@@ -192,15 +251,35 @@ func (g *Graph) NewWrapNoGrad(value GradValue) Node {
 	return newNode
 }
 
+// ForwardAll recomputes the values of all the operators, e.g. after the values of the variables have been replaced.
+// The operators created in no-grad mode cannot be recomputed, so they keep their previous values.
+// Only the values of the checkpoints' operators used outside their segments are kept (see Checkpoint).
 func (g *Graph) ForwardAll() {
 	g.ClearForReuse() // make sure you don't waste memory
 	for _, node := range g.nodes {
-		if node, ok := node.(*operator); ok {
-			node.value = node.function.Forward()
+		if node, ok := node.(*operator); ok && node.function != nil {
+			if node.checkpoint != nil {
+				if node.pinned {
+					node.Value() // recomputes the segment
+				}
+				continue
+			}
+			node.value = g.forward(node.opName, node.function, node.operands)
+			if g.checkNumerics {
+				g.checkForward(node, node.function, node.operands)
+			}
 		}
 	}
 }
 
+// forward computes the value of the function, profiling it if required (see Profile).
+func (g *Graph) forward(op OpName, f fn.Function, operands []Node) mat.Matrix {
+	if g.profiler != nil {
+		return g.profiler.forward(op, f, operands)
+	}
+	return f.Forward()
+}
+
 // Backward performs the back-propagation.
 // It visits each node in reverse topological order, to propagate the gradients from the given node all the way
 // back to the leaf. If there are no input gradients (i.e. grad is nil), it starts by finding the derivative of the
@@ -211,7 +290,7 @@ func (g *Graph) Backward(node Node, grad ...mat.Matrix) {
 		panic("ag: invalid number of arguments. Required zero or one argument.")
 	} else if len(grad) == 0 || grad[0] == nil {
 		gx = node.Value().OnesLike()
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 	} else {
 		gx = grad[0]
 	}
@@ -234,7 +313,7 @@ func (g *Graph) TBackward(node Node, backSteps int, grad ...mat.Matrix) {
 		panic("ag: invalid number of arguments. Required zero or one argument.")
 	} else if len(grad) == 0 || grad[0] == nil {
 		gx = node.Value().OnesLike()
-		defer mat.ReleaseDense(gx.(*mat.Dense))
+		defer mat.ReleaseMatrix(gx)
 	} else {
 		gx = grad[0]
 	}
@@ -252,32 +331,20 @@ func (g *Graph) BackwardAll() {
 }
 
 func (g *Graph) fullBackPropagation(node Node) {
-	nodes := g.nodes
-	lastIndex := node.Id()
-	_ = nodes[lastIndex] // avoid bounds check
-	for i := lastIndex; i >= 0; i-- {
-		if node, ok := nodes[i].(*operator); ok {
-			node.backward()
-		}
-	}
+	g.backPropagate(g.nodes[:node.Id()+1])
 }
 
 func (g *Graph) truncatedBackPropagation(node Node, backSteps int) {
 	if node.getTimeStep() != g.curTimeStep {
 		panic("ag: the truncated back-propagation must start from a node whose time-step is equal to the current step")
 	}
-	nodes := g.nodes
-	lastIndex := node.Id()
+	nodes := g.nodes[:node.Id()+1]
 	stopAtTimeStep := g.curTimeStep - int64(backSteps)
-	_ = nodes[lastIndex] // avoid bounds check
-	for i := lastIndex; i >= 0; i-- {
-		if nodes[i].getTimeStep() <= stopAtTimeStep {
-			break
-		}
-		if node, ok := nodes[i].(*operator); ok {
-			node.backward()
-		}
+	first := len(nodes)
+	for first > 0 && nodes[first-1].getTimeStep() > stopAtTimeStep {
+		first--
 	}
+	g.backPropagate(nodes[first:])
 }
 
 // GetValues returns a copy of the value of a node. If the value is nil, GetCopiedValue returns nil.
@@ -307,10 +374,32 @@ func (g *Graph) ReplaceValue(node Node, value mat.Matrix) {
 	if node, ok := node.(*variable); !ok {
 		panic("ag: invalid node. Only variables are allowed to change their value.")
 	} else {
-		node.value = value
+		node.value = g.convert(value)
 	}
 }
 
+// convert returns the value with the data type of the graph, converting it if needed.
+func (g *Graph) convert(value mat.Matrix) mat.Matrix {
+	if value == nil || mat.DTypeOf(value) == g.dtype {
+		return value
+	}
+	return mat.Convert(value, g.dtype)
+}
+
+// WithNoGrad executes the callback in no-grad mode: the operators created by it don't require gradients and
+// don't retain their functions and operands, so that the back-propagation never goes through them and the memory
+// they hold can be freed. This is convenient for inference. WithNoGrad can be nested.
+//
+// The no-grad mode belongs to the graph, not to the callback: while the callback runs, every operator created
+// on the graph is affected, including those created concurrently by other goroutines (e.g. by the concurrent
+// processors of the nn package). WithNoGrad must therefore not be mixed with the concurrent building of the parts
+// of the graph that require gradients; use a separate graph, or StopGrad on the single nodes, instead.
+func (g *Graph) WithNoGrad(callback func()) {
+	atomic.AddInt32(&g.noGrad, 1)
+	defer atomic.AddInt32(&g.noGrad, -1)
+	callback()
+}
+
 func (g *Graph) IncTimeStep() {
 	atomic.AddInt64(&g.curTimeStep, 1)
 }
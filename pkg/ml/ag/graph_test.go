@@ -6,6 +6,8 @@ package ag
 
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"gonum.org/v1/gonum/floats"
 	"testing"
 )
 
@@ -56,3 +58,95 @@ func TestGraph_TimeStep(t *testing.T) {
 		t.Errorf("The node time-step doesn't match the expected value.")
 	}
 }
+
+func TestGraph_StopGrad(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{2.0, 3.0}), true)
+	s := g.StopGrad(g.Square(x))
+	if s.RequiresGrad() {
+		t.Error("The node mustn't require gradients")
+	}
+	y := g.ReduceSum(g.Add(g.Square(x), s))
+	if !(y.ScalarValue() == 26.0) {
+		t.Errorf("The value doesn't match the expected value.")
+	}
+	g.Backward(y)
+	if !(x.Grad().AtVec(0) == 4.0 && x.Grad().AtVec(1) == 6.0) {
+		t.Errorf("The gradients don't match the expected values.")
+	}
+}
+
+func TestGraph_WithNoGrad(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{2.0, 3.0}), true)
+	var y Node
+	g.WithNoGrad(func() {
+		y = g.Square(x)
+	})
+	z := g.Square(x)
+	if y.RequiresGrad() || !z.RequiresGrad() {
+		t.Error("Only the operators created in no-grad mode mustn't require gradients")
+	}
+	if op := y.(*operator); op.function != nil || op.operands != nil {
+		t.Error("The operator created in no-grad mode mustn't retain the function and the operands")
+	}
+	g.ReplaceValue(x, mat.NewVecDense([]float64{1.0, 1.0}))
+	g.ForwardAll()
+	if !(y.Value().AtVec(0) == 4.0 && z.Value().AtVec(0) == 1.0) {
+		t.Errorf("The values don't match the expected values.")
+	}
+}
+
+func TestGraph_DType(t *testing.T) {
+	build := func(g *Graph) (Node, Node) {
+		w := g.NewVariable(mat.NewDense(2, 3, []float64{0.1, -0.2, 0.3, 0.4, 0.5, -0.6}), true)
+		x := g.NewVariable(mat.NewVecDense([]float64{0.7, -0.8, 0.9}), false)
+		return w, g.ReduceSum(g.Tanh(g.Mul(w, x)))
+	}
+	g64 := NewGraph()
+	w64, y64 := build(g64)
+	g64.Backward(y64)
+
+	g32 := NewGraph(DType(mat.Float32))
+	w32, y32 := build(g32)
+	if mat.DTypeOf(w32.Value()) != mat.Float32 {
+		t.Fatal("The variables should have the data type of the graph")
+	}
+	g32.Backward(y32)
+	if !floats.EqualApprox(y32.Value().Data(), y64.Value().Data(), 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+	if !floats.EqualApprox(w32.Grad().Data(), w64.Grad().Data(), 1.0e-6) {
+		t.Error("The gradients don't match the expected values")
+	}
+	g32.ReplaceValue(w32, mat.NewEmptyDense(2, 3))
+	if mat.DTypeOf(w32.Value()) != mat.Float32 {
+		t.Error("The replaced value should have the data type of the graph")
+	}
+}
+
+// releasingIdentity is an identity function counting its releases.
+type releasingIdentity struct {
+	fn.Identity
+	releases int
+}
+
+func (r *releasingIdentity) Release() { r.releases++ }
+
+func TestGraph_ReleaseFunctions(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1, 2}), true)
+	f := &releasingIdentity{Identity: *fn.NewIdentity(x)}
+	g.NewOperator(f, x)
+	noGrad := &releasingIdentity{Identity: *fn.NewIdentity(x)}
+	g.WithNoGrad(func() {
+		g.NewOperator(noGrad, x)
+	})
+	if f.releases != 0 || noGrad.releases != 1 {
+		t.Errorf("The functions in no-grad mode should be released on creation.")
+	}
+	g.Clear()
+	if f.releases != 1 || noGrad.releases != 1 {
+		t.Errorf("The functions should be released by Clear.")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"sync"
+)
+
+// GradHook is called with the gradients propagated to a node (see Node.RegisterHook), before they are accumulated.
+// A node which is used by several operators receives several contributions during the back-propagation, and the
+// hooks are called on each of them, not on their sum: a hook that needs the whole gradients (e.g. to clip them by
+// their norm) should be applied to Grad after the back-propagation instead.
+// It returns the gradients to accumulate: the given ones (e.g. after logging their norm), new ones (e.g. rescaled,
+// clipped or reversed) or nil to discard them.
+// The given gradients must be neither modified nor retained, since they can be shared with other nodes and
+// released afterwards; the returned ones are not released.
+type GradHook func(grad mat.Matrix) mat.Matrix
+
+// Hooks is a list of gradient hooks safe for concurrent use, for the implementations of GradValue supporting them
+// (e.g. nn.Param). The zero value is an empty list.
+type Hooks struct {
+	mu   sync.RWMutex
+	list []GradHook
+}
+
+// Register appends the hook to the list.
+func (h *Hooks) Register(hook GradHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.list = append(h.list, hook)
+}
+
+// Len returns the number of hooks.
+func (h *Hooks) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.list)
+}
+
+// Apply calls the hooks in order of registration and returns the gradients to accumulate, or nil if discarded.
+func (h *Hooks) Apply(grad mat.Matrix) mat.Matrix {
+	h.mu.RLock()
+	list := h.list
+	h.mu.RUnlock()
+	for _, hook := range list {
+		if grad = hook(grad); grad == nil {
+			return nil
+		}
+	}
+	return grad
+}
+
+// propagateDenseRowGrad propagates the gradients of the rows of x at the indices as dense gradients of the
+// dimensions of x, which are required by the hooks (see fn.RowGradPropagator).
+func propagateDenseRowGrad(x fn.Operand, indices []int, gx mat.Matrix) {
+	g := mat.GetEmptyDenseWorkspace(x.Value().Dims())
+	defer mat.ReleaseDense(g)
+	fn.AddRowsInPlace(g, indices, gx)
+	x.PropagateGrad(g)
+}
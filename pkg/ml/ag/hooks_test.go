@@ -0,0 +1,63 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestNode_RegisterHook(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{0.1, 0.2}), true)
+	w := g.NewVariable(mat.NewVecDense([]float64{0.5, -0.5}), true)
+
+	// gradient reversal
+	h := g.Identity(x)
+	h.RegisterHook(func(grad mat.Matrix) mat.Matrix {
+		return grad.ProdScalar(-1.0)
+	})
+	var norms []float64
+	x.RegisterHook(func(grad mat.Matrix) mat.Matrix {
+		norms = append(norms, grad.Norm(2))
+		return grad
+	})
+	w.RegisterHook(func(grad mat.Matrix) mat.Matrix {
+		return nil // frozen
+	})
+	y := g.ReduceSum(g.Prod(h, w))
+	g.Backward(y)
+
+	if !floats.EqualApprox(x.Grad().Data(), []float64{-0.5, 0.5}, 1.0e-6) {
+		t.Error("The gradients of x don't match the expected values")
+	}
+	if len(norms) != 1 || !floats.EqualWithinAbs(norms[0], 0.70710678, 1.0e-6) {
+		t.Errorf("Unexpected norms %v", norms)
+	}
+	if w.HasGrad() {
+		t.Error("The gradients of w should have been discarded")
+	}
+}
+
+func TestNode_RegisterHookRowGrad(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}), true)
+	var rows, cols []int
+	x.RegisterHook(func(grad mat.Matrix) mat.Matrix {
+		rows = append(rows, grad.Rows())
+		cols = append(cols, grad.Columns())
+		return grad
+	})
+	y := g.ReduceSum(g.Gather(x, []int{2, 0, 2}, []int{1, 0, 1}))
+	g.Backward(y)
+
+	if !floats.EqualApprox(x.Grad().Data(), []float64{1, 0, 0, 0, 0, 2}, 1.0e-6) {
+		t.Error("The gradients of x don't match the expected values")
+	}
+	if len(rows) != 1 || rows[0] != 3 || cols[0] != 2 {
+		t.Errorf("The hook should get a dense gradient, got %v×%v", rows, cols)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+)
+
+// JVP computes the Jacobian-vector products of the outputs with respect to the inputs, i.e. the directional
+// derivatives of the outputs along the given tangents of the inputs, by means of the forward-mode differentiation.
+// The nodes are visited once in topological order, propagating the tangents alongside the values already computed,
+// so the cost doesn't depend on the number of outputs. The outputs which don't depend on the inputs have zero tangents;
+// the operators created in no-grad mode are treated as constants.
+// It panics if it visits an operator whose function doesn't implement fn.ForwardDifferentiable (e.g. the function
+// of a custom operator, see RegisterOp); all the functions of the fn package implement it.
+func (g *Graph) JVP(outputs, inputs []Node, tangents []mat.Matrix) []mat.Matrix {
+	if len(inputs) != len(tangents) {
+		panic("ag: the number of tangents must match the number of inputs")
+	}
+	ts := make(map[int64]mat.Matrix, len(inputs))
+	first, last := int64(-1), int64(-1)
+	for i, x := range inputs {
+		if !(mat.SameDims(x.Value(), tangents[i]) || mat.VectorsOfSameSize(x.Value(), tangents[i])) {
+			panic("ag: the tangent doesn't match the dimensions of the input")
+		}
+		ts[x.Id()] = tangents[i]
+		if first == -1 || x.Id() < first {
+			first = x.Id()
+		}
+	}
+	for _, y := range outputs {
+		if y.Id() > last {
+			last = y.Id()
+		}
+	}
+	tangent := func(x fn.Operand) mat.Matrix {
+		return ts[x.(Node).Id()]
+	}
+
+	g.mu.Lock()
+	nodes := g.nodes
+	g.mu.Unlock()
+	for i := first; i >= 0 && i <= last; i++ {
+		op, ok := nodes[i].(*operator)
+		if !ok || !hasTangent(op.operands, ts) {
+			continue
+		}
+		if _, ok := ts[op.id]; ok {
+			continue // the tangent of an input is given
+		}
+		f, ok := op.function.(fn.ForwardDifferentiable)
+		if !ok {
+			panic(fmt.Sprintf("ag: forward-mode differentiation not supported by %T", op.function))
+		}
+		ts[op.id] = f.JVP(tangent)
+	}
+
+	out := make([]mat.Matrix, len(outputs))
+	for i, y := range outputs {
+		if t, ok := ts[y.Id()]; ok {
+			out[i] = t.Clone()
+		} else {
+			out[i] = y.Value().ZerosLike()
+		}
+	}
+	return out
+}
+
+func hasTangent(xs []Node, ts map[int64]mat.Matrix) bool {
+	for _, x := range xs {
+		if _, ok := ts[x.Id()]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestGraph_JVP(t *testing.T) {
+	g := NewGraph()
+	w := g.NewVariable(mat.NewDense(2, 2, []float64{0.5, -0.6, 0.3, 0.7}), true)
+	x := g.NewVariable(mat.NewVecDense([]float64{0.2, -0.4}), true)
+	y := g.Tanh(g.Mul(w, x))
+	z := g.ReduceSum(y)
+
+	ts := g.JVP([]Node{y, z}, []Node{x}, []mat.Matrix{mat.NewVecDense([]float64{1.0, 0.0})})
+
+	// ty = (1 - y^2) * W[:,0]
+	yv := y.Value().Data()
+	expected := []float64{(1 - yv[0]*yv[0]) * 0.5, (1 - yv[1]*yv[1]) * 0.3}
+	if !floats.EqualApprox(ts[0].Data(), expected, 1.0e-6) {
+		t.Error("The tangents of y don't match the expected values")
+	}
+	if !floats.EqualApprox(ts[1].Data(), []float64{expected[0] + expected[1]}, 1.0e-6) {
+		t.Error("The tangent of z doesn't match the expected value")
+	}
+}
+
+func TestGraph_JVPMatchBackward(t *testing.T) {
+	g := NewGraph()
+	w := g.NewVariable(mat.NewDense(3, 2, []float64{0.1, -0.2, 0.3, 0.4, -0.5, 0.6}), true)
+	x := g.NewVariable(mat.NewDense(2, 2, []float64{0.7, -0.8, 0.9, 0.2}), true)
+	b := g.NewVariable(mat.NewDense(1, 2, []float64{0.1, 0.2}), true)
+	h := g.Tanh(g.Add(g.Mul(w, x), b))
+	h = g.Concat(g.Softmax(h), g.Sigmoid(h))
+	h = g.Div(g.Exp(h), g.AddScalar(g.Sqrt(g.Square(h)), g.NewScalar(1.0)))
+	y := g.ReduceMean(g.DivScalar(g.Log(g.ProdScalar(h, g.NewScalar(2.0))), g.NewScalar(3.0)))
+
+	tangents := []mat.Matrix{
+		mat.NewDense(3, 2, []float64{0.3, 0.1, -0.2, 0.5, 0.4, -0.1}),
+		mat.NewDense(2, 2, []float64{-0.6, 0.2, 0.1, 0.3}),
+		mat.NewDense(1, 2, []float64{0.2, -0.7}),
+	}
+	ty := g.JVP([]Node{y}, []Node{w, x, b}, tangents)[0]
+
+	g.Backward(y)
+	expected := 0.0
+	for i, n := range []Node{w, x, b} {
+		expected += floats.Dot(n.Grad().Data(), tangents[i].Data())
+	}
+	if !floats.EqualApprox(ty.Data(), []float64{expected}, 1.0e-6) {
+		t.Error("The Jacobian-vector product doesn't match the back-propagation")
+	}
+}
+
+func TestGraph_JVPPooling1D(t *testing.T) {
+	g := NewGraph()
+	xs := []Node{
+		g.NewVariable(mat.NewDense(2, 2, []float64{0.1, -0.2, 0.3, 0.4}), true),
+		g.NewVariable(mat.NewDense(2, 2, []float64{0.7, -0.8, 0.9, 0.2}), true),
+		g.NewVariable(mat.NewDense(2, 2, []float64{-0.5, 0.6, 0.0, 0.3}), true),
+	}
+	maxs := g.MaxPooling1D(xs, 2, 1)
+	outputs := []Node{maxs[0], maxs[1], g.MinPooling1D(xs, 3, 1)[0], g.GlobalMaxPooling1D(xs...)}
+	tangents := []mat.Matrix{
+		mat.NewDense(2, 2, []float64{1.0, 2.0, 3.0, 4.0}),
+		mat.NewDense(2, 2, []float64{5.0, 6.0, 7.0, 8.0}),
+		mat.NewDense(2, 2, []float64{9.0, 10.0, 11.0, 12.0}),
+	}
+	ts := g.JVP(outputs, xs, tangents)
+
+	// the tangents of the selected values
+	expected := [][]float64{
+		{5.0, 2.0, 7.0, 4.0},
+		{5.0, 10.0, 7.0, 12.0},
+		{9.0, 6.0, 11.0, 8.0},
+		{5.0, 10.0, 7.0, 4.0},
+	}
+	for i, ty := range ts {
+		if !floats.EqualApprox(ty.Data(), expected[i], 1.0e-6) {
+			t.Errorf("The tangents of the output %d don't match the expected values", i)
+		}
+	}
+}
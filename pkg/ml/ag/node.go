@@ -10,6 +10,10 @@ type Node interface {
 	Graph() *Graph
 	// Id returns the id of the node in the graph.
 	Id() int64
+	// RegisterHook registers a function called with the gradients propagated to the node during the
+	// back-propagation, before they are accumulated: the hooks are called on each contribution of the operators
+	// using the node, in order of registration (see GradHook).
+	RegisterHook(hook GradHook)
 	//
 	getTimeStep() int64
 }
@@ -0,0 +1,193 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrNumeric is the error matched by the errors raised on NaN or Inf values (see CheckNumerics).
+var ErrNumeric = errors.New("ag: NaN or Inf value")
+
+// NumericError reports a NaN or Inf value in the value or in the gradients computed by an operator.
+type NumericError struct {
+	// Phase is "forward" if the value of the operator is not finite, "backward" if the gradients propagated by it are not
+	Phase string
+	// Node is the id of the operator
+	Node int64
+	// Function is the type of the function of the operator
+	Function string
+	// TimeStep is the time-step of the operator
+	TimeStep int64
+	// Operands contains the dimensions of the operands
+	Operands [][]int
+	// Path contains the operators which led to the operator, from the operator back to a variable, following at
+	// each step the operand with the largest absolute value (e.g. "12 *fn.Mul")
+	Path []string
+}
+
+// maxPathLen is the maximum length of the path in the message of a NumericError.
+const maxPathLen = 10
+
+// Error returns the message of the error.
+func (e *NumericError) Error() string {
+	shapes := make([]string, len(e.Operands))
+	for i, s := range e.Operands {
+		shapes[i] = fmt.Sprintf("%dx%d", s[0], s[1])
+	}
+	path := e.Path
+	if len(path) > maxPathLen {
+		path = append(path[:maxPathLen:maxPathLen], "...")
+	}
+	return fmt.Sprintf("ag: NaN or Inf on %s of node %d (%s, time-step %d, operands %s); path: %s",
+		e.Phase, e.Node, e.Function, e.TimeStep, strings.Join(shapes, " "), strings.Join(path, " <- "))
+}
+
+// Is reports whether the target is ErrNumeric.
+func (e *NumericError) Is(target error) bool {
+	return target == ErrNumeric
+}
+
+// CheckNumerics enables the detection of NaN and Inf values in the values of the operators and in the gradients
+// they propagate to their operands (including the parameters), to find where the computation diverges.
+// Each anomaly is reported to the handler, which can be called concurrently (e.g. by the concurrent backward);
+// if the handler is nil, the graph panics with the *NumericError instead (see Try).
+// The checks visit all the values, so they are meant for debugging.
+func CheckNumerics(handler func(err *NumericError)) GraphOption {
+	return func(g *Graph) {
+		g.checkNumerics = true
+		g.numericsHandler = handler
+	}
+}
+
+// checkForward checks the value of the operator, whose function and operands are given since they are not retained
+// in no-grad mode.
+func (g *Graph) checkForward(op *operator, f fn.Function, operands []Node) {
+	if !hasNaN(op.value) {
+		return
+	}
+	g.reportNumeric(newNumericError("forward", op, f, operands))
+}
+
+// gradNumerics records whether a node has been propagated non-finite gradients (see CheckNumerics).
+// The gradients are checked as propagated, before the hooks and the accumulation, so an operator is only
+// reported for the gradients it propagates itself, even if the operands already accumulated non-finite ones.
+type gradNumerics struct {
+	nonFinite int32
+}
+
+// check records whether the gradients propagated to the node are not finite, if the graph checks the numerics.
+func (n *gradNumerics) check(g *Graph, grad mat.Matrix) {
+	if g.checkNumerics && hasNaN(grad) {
+		atomic.StoreInt32(&n.nonFinite, 1)
+	}
+}
+
+// take reports whether non-finite gradients have been propagated to the node since the last call.
+func (n *gradNumerics) take() bool {
+	return atomic.SwapInt32(&n.nonFinite, 0) == 1
+}
+
+func gradNumericsOf(node Node) *gradNumerics {
+	switch node := node.(type) {
+	case *operator:
+		return &node.numerics
+	case *variable:
+		return &node.numerics
+	case *wrapper:
+		return &node.numerics
+	default:
+		return nil
+	}
+}
+
+// resetBackward clears the gradients recorded by the operands before the backward of the operator.
+func (g *Graph) resetBackward(op *operator) {
+	for _, x := range op.operands {
+		if n := gradNumericsOf(x); n != nil {
+			n.take()
+		}
+	}
+}
+
+// checkBackward checks the gradients propagated by the operator to its operands during its backward.
+// Both the sequential and the concurrent backward never process at the same time two operators propagating
+// gradients to the same node, so the gradients recorded by the operands are the ones of the operator.
+func (g *Graph) checkBackward(op *operator) {
+	nonFinite := false
+	for _, x := range op.operands {
+		if n := gradNumericsOf(x); n != nil && n.take() {
+			nonFinite = true
+		}
+	}
+	if nonFinite {
+		g.reportNumeric(newNumericError("backward", op, op.function, op.operands))
+	}
+}
+
+func (g *Graph) reportNumeric(err *NumericError) {
+	if g.numericsHandler == nil {
+		panic(err)
+	}
+	g.numericsHandler(err)
+}
+
+func newNumericError(phase string, op *operator, f fn.Function, operands []Node) *NumericError {
+	err := &NumericError{
+		Phase:    phase,
+		Node:     op.id,
+		Function: fmt.Sprintf("%T", f),
+		TimeStep: op.timeStep,
+		Operands: make([][]int, len(operands)),
+		Path:     []string{fmt.Sprintf("%d %T", op.id, f)},
+	}
+	for i, x := range operands {
+		err.Operands[i] = []int{x.Value().Rows(), x.Value().Columns()}
+	}
+	for next := largestOperand(operands); next != nil; {
+		if x, ok := next.(*operator); ok && x.function != nil {
+			err.Path = append(err.Path, fmt.Sprintf("%d %T", x.id, x.function))
+			next = largestOperand(x.operands)
+			continue
+		}
+		err.Path = append(err.Path, fmt.Sprintf("%d %s", next.Id(), leafName(next)))
+		break
+	}
+	return err
+}
+
+// leafName returns the type of the node, or of the wrapped value (e.g. *nn.Param).
+func leafName(node Node) string {
+	if w, ok := node.(*wrapper); ok {
+		return fmt.Sprintf("%T", w.GradValue)
+	}
+	return fmt.Sprintf("%T", node)
+}
+
+// largestOperand returns the operand with the largest absolute value, if any.
+func largestOperand(operands []Node) Node {
+	var largest Node
+	max := -1.0
+	for _, x := range operands {
+		if x.Value() == nil {
+			continue
+		}
+		for _, v := range x.Value().Data() {
+			if a := math.Abs(v); a > max || math.IsNaN(v) {
+				largest, max = x, a
+				if math.IsNaN(v) {
+					return largest
+				}
+			}
+		}
+	}
+	return largest
+}
@@ -0,0 +1,74 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"reflect"
+	"testing"
+)
+
+func TestCheckNumerics_Forward(t *testing.T) {
+	g := NewGraph(CheckNumerics(nil))
+	w := g.NewVariable(mat.NewDense(2, 2, []float64{100.0, 0.1, 0.2, 900.0}), true)
+	x := g.NewVariable(mat.NewVecDense([]float64{0.5, 1.0}), false)
+	h := g.Mul(w, x)
+
+	err := Try(func() {
+		g.Exp(h)
+	})
+	if !errors.Is(err, ErrNumeric) {
+		t.Fatalf("Expected a numeric error, found %v", err)
+	}
+	var numErr *NumericError
+	errors.As(err, &numErr)
+	expected := &NumericError{
+		Phase:    "forward",
+		Node:     3,
+		Function: "*fn.UnaryElementwise",
+		TimeStep: 0,
+		Operands: [][]int{{2, 1}},
+		Path:     []string{"3 *fn.UnaryElementwise", "2 *fn.Mul", "0 *ag.variable"},
+	}
+	if !reflect.DeepEqual(numErr, expected) {
+		t.Errorf("Expected %v, found %v", expected, numErr)
+	}
+}
+
+func TestCheckNumerics_Backward(t *testing.T) {
+	var errs []*NumericError
+	g := NewGraph(CheckNumerics(func(err *NumericError) {
+		errs = append(errs, err)
+	}))
+	x := g.NewVariable(mat.NewVecDense([]float64{0.0, 4.0}), true)
+	y := g.ReduceSum(g.Sqrt(x))
+	g.Backward(y)
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, found %d", len(errs))
+	}
+	if errs[0].Phase != "backward" || errs[0].Node != 1 {
+		t.Errorf("Unexpected error %v", errs[0])
+	}
+}
+
+func TestCheckNumerics_BackwardPropagatedOnly(t *testing.T) {
+	var errs []*NumericError
+	g := NewGraph(CheckNumerics(func(err *NumericError) {
+		errs = append(errs, err)
+	}))
+	x := g.NewVariable(mat.NewVecDense([]float64{0.0, 4.0}), true)
+	a := g.Tanh(x)
+	b := g.Sqrt(x) // propagates an infinite gradient to x before Tanh, which propagates finite gradients
+	g.Backward(g.ReduceSum(g.Add(a, b)))
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, found %d", len(errs))
+	}
+	if errs[0].Phase != "backward" || errs[0].Node != b.Id() {
+		t.Errorf("Unexpected error %v", errs[0])
+	}
+}
@@ -11,21 +11,28 @@ import (
 )
 
 var (
-	_ fn.Operand = &operator{}
-	_ GradValue  = &operator{}
-	_ Node       = &operator{}
+	_ fn.Operand           = &operator{}
+	_ GradValue            = &operator{}
+	_ Node                 = &operator{}
+	_ fn.RowGradPropagator = &operator{}
 )
 
 type operator struct {
 	graph        *Graph
 	timeStep     int64
 	id           int64
+	opName       OpName // the operator the node has been created by, if known (see WriteDOT)
 	function     fn.Function
-	value        mat.Matrix // store the results of a forward evaluation
-	mu           sync.Mutex // to avoid data race during gradients accumulation
-	grad         mat.Matrix // TODO: support of sparse gradients
+	operands     []Node      // the input nodes of the function
+	checkpoint   *checkpoint // the segment the operator belongs to, if any (see Checkpoint)
+	pinned       bool        // whether the value is kept although the operator belongs to a checkpoint
+	value        mat.Matrix  // store the results of a forward evaluation
+	mu           sync.Mutex  // to avoid data race during gradients accumulation
+	grad         mat.Matrix  // TODO: support of sparse gradients
 	hasGrad      bool
 	requiresGrad bool
+	hooks        Hooks        // called on the propagation of the gradients (see RegisterHook)
+	numerics     gradNumerics // records the non-finite gradients propagated to the node (see CheckNumerics)
 }
 
 // Id returns the id of the node in the graph.
@@ -38,8 +45,12 @@ func (r *operator) Graph() *Graph {
 	return r.graph
 }
 
-// Value returns the cached result of the function.
+// Value returns the result of the forward evaluation.
+// If the value has been released by a checkpoint, the segment is recomputed and the value is kept from now on.
 func (r *operator) Value() mat.Matrix {
+	if r.value == nil && r.checkpoint != nil {
+		r.graph.restore(r)
+	}
 	return r.value
 }
 
@@ -60,15 +71,44 @@ func (r *operator) PropagateGrad(grad mat.Matrix) {
 	if !r.requiresGrad {
 		return
 	}
+	r.numerics.check(r.graph, grad)
+	if grad = r.hooks.Apply(grad); grad == nil {
+		return
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.grad == nil {
-		r.grad = mat.GetEmptyDenseWorkspace(r.value.Dims()) // this could reduce the number of allocations
+		r.grad = mat.GetEmptyDenseWorkspace(r.Value().Dims()) // this could reduce the number of allocations
 	}
 	r.grad.AddInPlace(grad)
 	r.hasGrad = true
 }
 
+// PropagateRowGrad accumulates the gradients of the rows at the indices to the node itself (see
+// fn.RowGradPropagator). The gradients are passed to the hooks as dense gradients of the dimensions of the node.
+func (r *operator) PropagateRowGrad(indices []int, gx mat.Matrix) {
+	if !r.requiresGrad {
+		return
+	}
+	if r.hooks.Len() > 0 {
+		propagateDenseRowGrad(r, indices, gx)
+		return
+	}
+	r.numerics.check(r.graph, gx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.grad == nil {
+		r.grad = mat.GetEmptyDenseWorkspace(r.Value().Dims())
+	}
+	fn.AddRowsInPlace(r.grad, indices, gx)
+	r.hasGrad = true
+}
+
+// RegisterHook registers a function called with the gradients propagated to the node, before they are accumulated.
+func (r *operator) RegisterHook(hook GradHook) {
+	r.hooks.Register(hook)
+}
+
 // HasGrad returns true if there are accumulated gradients.
 func (r *operator) HasGrad() bool {
 	return r.hasGrad
@@ -84,7 +124,7 @@ func (r *operator) ZeroGrad() {
 	if r.grad == nil {
 		return
 	}
-	defer mat.ReleaseDense(r.grad.(*mat.Dense)) // release memory
+	defer mat.ReleaseMatrix(r.grad) // release memory
 	r.grad = nil
 	r.hasGrad = false
 }
@@ -97,5 +137,15 @@ func (r *operator) backward() {
 	if !r.hasGrad {
 		return
 	}
-	r.function.Backward(r.grad)
+	if r.graph.checkNumerics {
+		r.graph.resetBackward(r)
+	}
+	if p := r.graph.profiler; p != nil {
+		p.backward(r.opName, r.function, r.operands, r.grad)
+	} else {
+		r.function.Backward(r.grad)
+	}
+	if r.graph.checkNumerics {
+		r.graph.checkBackward(r)
+	}
 }
@@ -5,6 +5,8 @@
 package ag
 
 import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
 	"reflect"
 )
@@ -65,67 +67,116 @@ const (
 	OpReduceMean
 	OpConcat
 	OpStack
+	OpPermute
+	OpReduceSumAxis
+	OpReduceMeanAxis
+	OpReduceMaxAxis
+	OpStopGrad
+	OpLogSoftmax
+	OpLogSumExp
+	OpGather
+	OpIndexSelect
+	OpScatterAdd
+	OpMaskedFill
+	OpWhere
+	OpGELU
+	OpGELUTanh
+	OpSoftmin
+	OpSparsemax
+	OpEntmax15
+	OpAvgPooling
+	OpMinPooling
+	OpGlobalMaxPooling
+	OpGlobalAvgPooling
 )
 
 var opNameToMethodName = map[OpName]string{
-	OpIdentity:    "Identity",
-	OpDropout:     "Dropout",
-	OpAtVec:       "AtVec",
-	OpAt:          "At",
-	OpAdd:         "Add",
-	OpSub:         "Sub",
-	OpSubScalar:   "SubScalar",
-	OpAddScalar:   "AddScalar",
-	OpReverseSub:  "ReverseSub",
-	OpProd:        "Prod",
-	OpDiv:         "Div",
-	OpProdScalar:  "ProdScalar",
-	OpDivScalar:   "DivScalar",
-	OpMul:         "Mul",
-	OpDot:         "Dot",
-	OpReshape:     "Reshape",
-	OpMaxPooling:  "MaxPooling",
-	OpView:        "View",
-	OpRowView:     "RowView",
-	OpColView:     "ColView",
-	OpVec:         "Vec",
-	OpT:           "T",
-	OpSquare:      "Square",
-	OpPow:         "Pow",
-	OpSqrt:        "Sqrt",
-	OpTan:         "Tan",
-	OpTanh:        "Tanh",
-	OpSigmoid:     "Sigmoid",
-	OpHardSigmoid: "HardSigmoid",
-	OpHardTanh:    "HardTanh",
-	OpSoftsign:    "Softsign",
-	OpReLU:        "ReLU",
-	OpCeLU:        "CeLU",
-	OpELU:         "ELU",
-	OpSwish:       "Swish",
-	OpMish:        "Mish",
-	OpLeakyReLU:   "LeakyReLU",
-	OpSeLU:        "SeLU",
-	OpSoftPlus:    "SoftPlus",
-	OpSoftShrink:  "SoftShrink",
-	OpThreshold:   "Threshold",
-	OpSoftmax:     "Softmax",
-	OpSin:         "Sin",
-	OpCos:         "Cos",
-	OpExp:         "Exp",
-	OpLog:         "Log",
-	OpAbs:         "Abs",
-	OpNeg:         "Neg",
-	OpReciprocal:  "Reciprocal",
-	OpReduceSum:   "ReduceSum",
-	OpReduceMean:  "ReduceMean",
-	OpConcat:      "Concat",
-	OpStack:       "Stack",
-}
-
-// Invoke
+	OpIdentity:         "Identity",
+	OpDropout:          "Dropout",
+	OpAtVec:            "AtVec",
+	OpAt:               "At",
+	OpAdd:              "Add",
+	OpSub:              "Sub",
+	OpSubScalar:        "SubScalar",
+	OpAddScalar:        "AddScalar",
+	OpReverseSub:       "ReverseSub",
+	OpProd:             "Prod",
+	OpDiv:              "Div",
+	OpProdScalar:       "ProdScalar",
+	OpDivScalar:        "DivScalar",
+	OpMul:              "Mul",
+	OpDot:              "Dot",
+	OpReshape:          "Reshape",
+	OpMaxPooling:       "MaxPooling",
+	OpView:             "View",
+	OpRowView:          "RowView",
+	OpColView:          "ColView",
+	OpVec:              "Vec",
+	OpT:                "T",
+	OpSquare:           "Square",
+	OpPow:              "Pow",
+	OpSqrt:             "Sqrt",
+	OpTan:              "Tan",
+	OpTanh:             "Tanh",
+	OpSigmoid:          "Sigmoid",
+	OpHardSigmoid:      "HardSigmoid",
+	OpHardTanh:         "HardTanh",
+	OpSoftsign:         "Softsign",
+	OpReLU:             "ReLU",
+	OpCeLU:             "CeLU",
+	OpELU:              "ELU",
+	OpSwish:            "Swish",
+	OpMish:             "Mish",
+	OpLeakyReLU:        "LeakyReLU",
+	OpSeLU:             "SeLU",
+	OpSoftPlus:         "SoftPlus",
+	OpSoftShrink:       "SoftShrink",
+	OpThreshold:        "Threshold",
+	OpSoftmax:          "Softmax",
+	OpSin:              "Sin",
+	OpCos:              "Cos",
+	OpExp:              "Exp",
+	OpLog:              "Log",
+	OpAbs:              "Abs",
+	OpNeg:              "Neg",
+	OpReciprocal:       "Reciprocal",
+	OpReduceSum:        "ReduceSum",
+	OpReduceMean:       "ReduceMean",
+	OpConcat:           "Concat",
+	OpStack:            "Stack",
+	OpPermute:          "Permute",
+	OpReduceSumAxis:    "ReduceSumAxis",
+	OpReduceMeanAxis:   "ReduceMeanAxis",
+	OpReduceMaxAxis:    "ReduceMaxAxis",
+	OpStopGrad:         "StopGrad",
+	OpLogSoftmax:       "LogSoftmax",
+	OpLogSumExp:        "LogSumExp",
+	OpGather:           "Gather",
+	OpIndexSelect:      "IndexSelect",
+	OpScatterAdd:       "ScatterAdd",
+	OpMaskedFill:       "MaskedFill",
+	OpWhere:            "Where",
+	OpGELU:             "GELU",
+	OpGELUTanh:         "GELUTanh",
+	OpSoftmin:          "Softmin",
+	OpSparsemax:        "Sparsemax",
+	OpEntmax15:         "Entmax15",
+	OpAvgPooling:       "AvgPooling",
+	OpMinPooling:       "MinPooling",
+	OpGlobalMaxPooling: "GlobalMaxPooling",
+	OpGlobalAvgPooling: "GlobalAvgPooling",
+}
+
+// Invoke creates a new operator of the given type, built-in or custom (see RegisterOp).
 func (g *Graph) Invoke(operator OpName, xs ...Node) Node {
-	v := reflect.ValueOf(g).MethodByName(opNameToMethodName[operator])
+	if c, ok := lookupCustomOp(operator); ok {
+		return g.newOperatorOf(operator, c.factory(operands(xs)...), xs...)
+	}
+	methodName, ok := opNameToMethodName[operator]
+	if !ok {
+		panic(fmt.Sprintf("ag: unknown operator %d", int(operator)))
+	}
+	v := reflect.ValueOf(g).MethodByName(methodName)
 	args := make([]reflect.Value, len(xs))
 	for i, x := range xs {
 		args[i] = reflect.ValueOf(x)
@@ -136,271 +187,495 @@ func (g *Graph) Invoke(operator OpName, xs ...Node) Node {
 
 // Identity
 func (g *Graph) Identity(x Node) Node {
-	return g.NewOperator(fn.NewIdentity(x), x)
+	return g.newOperatorOf(OpIdentity, fn.NewIdentity(x), x)
+}
+
+// StopGrad returns a new node with the same value of x, through which the gradients are not propagated.
+// It can be used to treat x as a constant, e.g. for the targets or the straight-through estimators.
+func (g *Graph) StopGrad(x Node) Node {
+	return g.newOperator(opUnknown, fn.NewIdentity(x), false, x)
 }
 
 // Dropout
 func (g *Graph) Dropout(x Node, p float64) Node {
-	return g.NewOperator(fn.NewDropout(x, p, g.randGen), x)
+	return g.newOperatorOf(OpDropout, fn.NewDropout(x, p, g.randGen), x)
 }
 
 // AtVec
 func (g *Graph) AtVec(x Node, i int) Node {
-	return g.NewOperator(fn.NewAtVec(x, i), x)
+	return g.newOperatorOf(OpAtVec, fn.NewAtVec(x, i), x)
 }
 
 // At
 func (g *Graph) At(x Node, i int, j int) Node {
-	return g.NewOperator(fn.NewAt(x, i, j), x)
+	return g.newOperatorOf(OpAt, fn.NewAt(x, i, j), x)
 }
 
-// Add
+// Add returns the element-wise sum of x1 and x2, with broadcasting (see fn.Add).
 // The first node may be null. This help to keep the code as concise as possible e.g. during accumulation.
 func (g *Graph) Add(x1 Node, x2 Node) Node {
 	if x1 != nil {
-		return g.NewOperator(fn.NewAdd(x1, x2), x1, x2)
+		return g.newOperatorOf(OpAdd, fn.NewAdd(x1, x2), x1, x2)
 	} else {
 		fake := g.NewVariable(x2.Value().ZerosLike(), false)
-		return g.NewOperator(fn.NewAdd(fake, x2), fake, x2)
+		return g.newOperatorOf(OpAdd, fn.NewAdd(fake, x2), fake, x2)
 	}
 }
 
-// Sub
+// Sub returns the element-wise difference of x1 and x2, with broadcasting.
 func (g *Graph) Sub(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewSub(x1, x2), x1, x2)
+	return g.newOperatorOf(OpSub, fn.NewSub(x1, x2), x1, x2)
 }
 
 // SubScalar
 func (g *Graph) SubScalar(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewSubScalar(x1, x2), x1, x2)
+	return g.newOperatorOf(OpSubScalar, fn.NewSubScalar(x1, x2), x1, x2)
 }
 
 // AddScalar
 func (g *Graph) AddScalar(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewAddScalar(x1, x2), x1, x2)
+	return g.newOperatorOf(OpAddScalar, fn.NewAddScalar(x1, x2), x1, x2)
 }
 
 // ReverseSub
 func (g *Graph) ReverseSub(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewReverseSubScalar(x1, x2), x1, x2)
+	return g.newOperatorOf(OpReverseSub, fn.NewReverseSubScalar(x1, x2), x1, x2)
 }
 
-// Prod
+// Prod returns the element-wise product of x1 and x2, with broadcasting.
 func (g *Graph) Prod(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewProd(x1, x2), x1, x2)
+	return g.newOperatorOf(OpProd, fn.NewProd(x1, x2), x1, x2)
 }
 
-// Div
+// Div returns the element-wise division of x1 and x2, with broadcasting.
 func (g *Graph) Div(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewDiv(x1, x2), x1, x2)
+	return g.newOperatorOf(OpDiv, fn.NewDiv(x1, x2), x1, x2)
 }
 
 // ProdScalar
 func (g *Graph) ProdScalar(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewProdScalar(x1, x2), x1, x2)
+	return g.newOperatorOf(OpProdScalar, fn.NewProdScalar(x1, x2), x1, x2)
 }
 
 // DivScalar
 func (g *Graph) DivScalar(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewDivScalar(x1, x2), x1, x2)
+	return g.newOperatorOf(OpDivScalar, fn.NewDivScalar(x1, x2), x1, x2)
 }
 
 // Mul
 func (g *Graph) Mul(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewMul(x1, x2), x1, x2)
+	return g.newOperatorOf(OpMul, fn.NewMul(x1, x2), x1, x2)
+}
+
+// fusedActivations contains the element-wise activations that can be fused in the affine transformation,
+// with the rules to compute their higher-order gradients, if any.
+var fusedActivations = map[OpName]struct {
+	activation fn.Activation
+	grad       GradFunc
+}{
+	OpTanh:        {fn.TanhActivation, tanhGrad},
+	OpSigmoid:     {fn.SigmoidActivation, sigmoidGrad},
+	OpReLU:        {fn.ReLUActivation, reluGrad},
+	OpHardSigmoid: {fn.HardSigmoidActivation, nil},
+	OpHardTanh:    {fn.HardTanhActivation, nil},
+	OpSoftsign:    {fn.SoftsignActivation, nil},
+	OpMish:        {fn.MishActivation, nil},
+	OpGELU:        {fn.GELUActivation, nil},
+	OpGELUTanh:    {fn.GELUTanhActivation, nil},
+}
+
+// Affine returns activation(b + w1·x1 + w2·x2 + ... + wn·xn) computed by a single fused operator (see fn.Affine).
+// The first node is the bias b, which may be nil; the pairs whose x is nil are skipped.
+// The activations Tanh, Sigmoid, ReLU, HardSigmoid, HardTanh, Softsign, Mish, GELU and GELUTanh are computed by the
+// same operator; the others are applied to its output. Use OpIdentity for the affine transformation alone.
+func (g *Graph) Affine(activation OpName, xs ...Node) Node {
+	if len(xs)%2 == 0 {
+		panic("ag: the number of arguments of the affine transformation should be odd")
+	}
+	fused, ok := fusedActivations[activation]
+	if !ok && activation != OpIdentity {
+		return g.Invoke(activation, g.Affine(OpIdentity, xs...))
+	}
+	b := xs[0]
+	var pairs []Node
+	for i := 1; i < len(xs)-1; i += 2 {
+		if xs[i+1] != nil {
+			pairs = append(pairs, xs[i], xs[i+1])
+		}
+	}
+	var f *fn.Affine
+	if ok {
+		f = fn.NewFusedAffine(fused.activation, b, operands(pairs)...)
+	} else {
+		f = fn.NewAffine(b, operands(pairs)...)
+	}
+	if b == nil {
+		return g.NewOperator(f, pairs...)
+	}
+	return g.NewOperator(f, append([]Node{b}, pairs...)...)
 }
 
 // Dot
 func (g *Graph) Dot(x1 Node, x2 Node) Node {
-	return g.NewOperator(fn.NewDot(x1, x2), x1, x2)
+	return g.newOperatorOf(OpDot, fn.NewDot(x1, x2), x1, x2)
 }
 
 // Reshape
 func (g *Graph) Reshape(x Node, rows, columns int) Node {
-	return g.NewOperator(fn.NewReshape(x, rows, columns), x)
+	return g.newOperatorOf(OpReshape, fn.NewReshape(x, rows, columns), x)
 }
 
 // MaxPooling
 func (g *Graph) MaxPooling(x Node, rows, columns int) Node {
-	return g.NewOperator(fn.NewMaxPooling(x, rows, columns), x)
+	return g.newOperatorOf(OpMaxPooling, fn.NewMaxPooling(x, rows, columns), x)
+}
+
+// MinPooling takes the min of each non-overlapping window of rows x columns values of x.
+func (g *Graph) MinPooling(x Node, rows, columns int) Node {
+	return g.newOperatorOf(OpMinPooling, fn.NewMinPooling(x, rows, columns), x)
+}
+
+// AvgPooling takes the mean of each non-overlapping window of rows x columns values of x.
+func (g *Graph) AvgPooling(x Node, rows, columns int) Node {
+	return g.newOperatorOf(OpAvgPooling, fn.NewAvgPooling(x, rows, columns), x)
+}
+
+// GlobalMaxPooling returns a 1x1 matrix with the max of the values of x.
+func (g *Graph) GlobalMaxPooling(x Node) Node {
+	return g.MaxPooling(x, x.Value().Rows(), x.Value().Columns())
+}
+
+// GlobalAvgPooling returns a 1x1 matrix with the mean of the values of x.
+func (g *Graph) GlobalAvgPooling(x Node) Node {
+	return g.AvgPooling(x, x.Value().Rows(), x.Value().Columns())
+}
+
+// MaxPooling1D slides a window of size nodes over the sequence xs, moving by stride nodes, and returns the
+// element-wise max of the nodes of each window. The nodes must have the same dimensions (e.g. the mini-batch
+// matrices of a recurrent network). It panics if the window is not in the range [1, len(xs)] or the stride is
+// less than one.
+func (g *Graph) MaxPooling1D(xs []Node, size, stride int) []Node {
+	return g.pooling1D(xs, size, stride, func(x Node, shape []int) Node {
+		return g.ReduceMaxAxis(x, shape, 0)
+	})
+}
+
+// MinPooling1D returns the element-wise min of the nodes of each window of the sequence xs (see MaxPooling1D).
+func (g *Graph) MinPooling1D(xs []Node, size, stride int) []Node {
+	return g.pooling1D(xs, size, stride, func(x Node, shape []int) Node {
+		return g.Neg(g.ReduceMaxAxis(g.Neg(x), shape, 0))
+	})
+}
+
+// AvgPooling1D returns the element-wise mean of the nodes of each window of the sequence xs (see MaxPooling1D).
+func (g *Graph) AvgPooling1D(xs []Node, size, stride int) []Node {
+	return g.pooling1D(xs, size, stride, func(x Node, shape []int) Node {
+		return g.ReduceMeanAxis(x, shape, 0)
+	})
+}
+
+// GlobalMaxPooling1D returns the element-wise max of the whole sequence xs, a.k.a. max-over-time pooling.
+func (g *Graph) GlobalMaxPooling1D(xs ...Node) Node {
+	return g.MaxPooling1D(xs, len(xs), 1)[0]
+}
+
+// GlobalAvgPooling1D returns the element-wise mean of the whole sequence xs, a.k.a. mean-over-time pooling.
+func (g *Graph) GlobalAvgPooling1D(xs ...Node) Node {
+	return g.AvgPooling1D(xs, len(xs), 1)[0]
+}
+
+// pooling1D stacks the vectorized nodes of each window of the sequence and reduces the stacked rows.
+func (g *Graph) pooling1D(xs []Node, size, stride int, reduce func(x Node, shape []int) Node) []Node {
+	if size < 1 || size > len(xs) || stride < 1 {
+		panic(fmt.Sprintf("ag: invalid 1-D pooling window %d with stride %d over %d nodes", size, stride, len(xs)))
+	}
+	rows, cols := xs[0].Value().Dims()
+	ys := make([]Node, 0, (len(xs)-size)/stride+1)
+	for start := 0; start+size <= len(xs); start += stride {
+		window := make([]Node, size)
+		for k, x := range xs[start : start+size] {
+			window[k] = g.Vec(x)
+		}
+		y := reduce(g.Stack(window...), []int{size, rows * cols})
+		ys = append(ys, g.Reshape(y, rows, cols))
+	}
+	return ys
 }
 
 // View
 func (g *Graph) View(x Node, row, column, xStride, yStride int) Node {
-	return g.NewOperator(fn.NewView(x, row, column, xStride, yStride), x)
+	return g.newOperatorOf(OpView, fn.NewView(x, row, column, xStride, yStride), x)
 }
 
 // RowView
 func (g *Graph) RowView(x Node, row int) Node {
-	return g.NewOperator(fn.NewRowView(x, row), x)
+	return g.newOperatorOf(OpRowView, fn.NewRowView(x, row), x)
 }
 
 // ColView
 func (g *Graph) ColView(x Node, column int) Node {
-	return g.NewOperator(fn.NewColView(x, column), x)
+	return g.newOperatorOf(OpColView, fn.NewColView(x, column), x)
+}
+
+// IndexSelect returns a new matrix with the rows of x at the given indices, which can be repeated.
+// It can be used to look up the embeddings of a sequence from a single table.
+func (g *Graph) IndexSelect(x Node, indices ...int) Node {
+	return g.newOperatorOf(OpIndexSelect, fn.NewIndexSelect(x, indices), x)
+}
+
+// Gather returns a column vector with the elements of x at the coordinates (rows[k], cols[k]).
+func (g *Graph) Gather(x Node, rows, cols []int) Node {
+	return g.newOperatorOf(OpGather, fn.NewGather(x, rows, cols), x)
+}
+
+// ScatterAdd returns a copy of x in which the k-th row of src is added to the row at indices[k].
+func (g *Graph) ScatterAdd(x Node, indices []int, src Node) Node {
+	return g.newOperatorOf(OpScatterAdd, fn.NewScatterAdd(x, indices, src), x, src)
+}
+
+// MaskedFill returns a copy of x in which the values are replaced with the given value where the mask is not zero.
+// The mask must have the same dimensions of x.
+func (g *Graph) MaskedFill(x Node, mask mat.Matrix, value float64) Node {
+	return g.newOperatorOf(OpMaskedFill, fn.NewMaskedFill(x, mask, value), x)
+}
+
+// Where returns a new node with the values of x1 where the condition is not zero, and the ones of x2 elsewhere.
+// The condition and the operands must have the same dimensions.
+func (g *Graph) Where(cond mat.Matrix, x1, x2 Node) Node {
+	return g.newOperatorOf(OpWhere, fn.NewWhere(cond, x1, x2), x1, x2)
 }
 
 // Vec
 func (g *Graph) Vec(x Node) Node {
-	return g.NewOperator(fn.NewVec(x), x)
+	return g.newOperatorOf(OpVec, fn.NewVec(x), x)
 }
 
 // T
 func (g *Graph) T(x Node) Node {
-	return g.NewOperator(fn.NewTranspose(x), x)
+	return g.newOperatorOf(OpT, fn.NewTranspose(x), x)
 }
 
 // Square
 func (g *Graph) Square(x Node) Node {
-	return g.NewOperator(fn.NewSquare(x), x)
+	return g.newOperatorOf(OpSquare, fn.NewSquare(x), x)
 }
 
 // Pow
 func (g *Graph) Pow(x Node, power float64) Node {
-	return g.NewOperator(fn.NewPow(x, power), x)
+	return g.newOperatorOf(OpPow, fn.NewPow(x, power), x)
 }
 
 // Sqrt
 func (g *Graph) Sqrt(x Node) Node {
-	return g.NewOperator(fn.NewSqrt(x), x)
+	return g.newOperatorOf(OpSqrt, fn.NewSqrt(x), x)
 }
 
 // Tan
 func (g *Graph) Tan(x Node) Node {
-	return g.NewOperator(fn.NewTan(x), x)
+	return g.newOperatorOf(OpTan, fn.NewTan(x), x)
 }
 
 // Tanh
 func (g *Graph) Tanh(x Node) Node {
-	return g.NewOperator(fn.NewTanh(x), x)
+	return g.newOperatorOf(OpTanh, fn.NewTanh(x), x)
 }
 
 // Sigmoid
 func (g *Graph) Sigmoid(x Node) Node {
-	return g.NewOperator(fn.NewSigmoid(x), x)
+	return g.newOperatorOf(OpSigmoid, fn.NewSigmoid(x), x)
 }
 
 // HardSigmoid
 func (g *Graph) HardSigmoid(x Node) Node {
-	return g.NewOperator(fn.NewHardSigmoid(x), x)
+	return g.newOperatorOf(OpHardSigmoid, fn.NewHardSigmoid(x), x)
 }
 
 // HardTanh
 func (g *Graph) HardTanh(x Node) Node {
-	return g.NewOperator(fn.NewHardTanh(x), x)
+	return g.newOperatorOf(OpHardTanh, fn.NewHardTanh(x), x)
 }
 
 // Softsign
 func (g *Graph) Softsign(x Node) Node {
-	return g.NewOperator(fn.NewSoftsign(x), x)
+	return g.newOperatorOf(OpSoftsign, fn.NewSoftsign(x), x)
 }
 
 // ReLU
 func (g *Graph) ReLU(x Node) Node {
-	return g.NewOperator(fn.NewReLU(x), x)
+	return g.newOperatorOf(OpReLU, fn.NewReLU(x), x)
 }
 
 // CeLU
 func (g *Graph) CeLU(x Node, alpha Node) Node {
-	return g.NewOperator(fn.NewCeLU(x, alpha), x, alpha)
+	return g.newOperatorOf(OpCeLU, fn.NewCeLU(x, alpha), x, alpha)
 }
 
 // ELU
 func (g *Graph) ELU(x Node, alpha Node) Node {
-	return g.NewOperator(fn.NewELU(x, alpha), x, alpha)
+	return g.newOperatorOf(OpELU, fn.NewELU(x, alpha), x, alpha)
 }
 
 // Swish
 func (g *Graph) Swish(x Node, beta Node) Node {
-	return g.NewOperator(fn.NewSwish(x, beta), x, beta)
+	return g.newOperatorOf(OpSwish, fn.NewSwish(x, beta), x, beta)
 }
 
 // Mish
 func (g *Graph) Mish(x Node) Node {
-	return g.NewOperator(fn.NewMish(x), x)
+	return g.newOperatorOf(OpMish, fn.NewMish(x), x)
 }
 
 // LeakyReLU
 func (g *Graph) LeakyReLU(x Node, alpha Node) Node {
-	return g.NewOperator(fn.NewLeakyReLU(x, alpha), x, alpha)
+	return g.newOperatorOf(OpLeakyReLU, fn.NewLeakyReLU(x, alpha), x, alpha)
 }
 
 // SeLU
 func (g *Graph) SeLU(x Node, alpha Node, scale Node) Node {
-	return g.NewOperator(fn.NewSeLU(x, alpha, scale), x, alpha, scale)
+	return g.newOperatorOf(OpSeLU, fn.NewSeLU(x, alpha, scale), x, alpha, scale)
 }
 
 // SoftPlus
 func (g *Graph) SoftPlus(x Node, beta Node, threshold Node) Node {
-	return g.NewOperator(fn.NewSoftPlus(x, beta, threshold), x, beta, threshold)
+	return g.newOperatorOf(OpSoftPlus, fn.NewSoftPlus(x, beta, threshold), x, beta, threshold)
 }
 
 // SoftShrink
 func (g *Graph) SoftShrink(x Node, lambda Node) Node {
-	return g.NewOperator(fn.NewSoftShrink(x, lambda), x, lambda)
+	return g.newOperatorOf(OpSoftShrink, fn.NewSoftShrink(x, lambda), x, lambda)
 }
 
 // Threshold
 func (g *Graph) Threshold(x Node, threshold Node, k Node) Node {
-	return g.NewOperator(fn.NewThreshold(x, threshold, k), x, threshold, k)
+	return g.newOperatorOf(OpThreshold, fn.NewThreshold(x, threshold, k), x, threshold, k)
 }
 
 // Softmax
 func (g *Graph) Softmax(x Node) Node {
-	return g.NewOperator(fn.NewSoftmax(x), x)
+	return g.newOperatorOf(OpSoftmax, fn.NewSoftmax(x), x)
+}
+
+// LogSoftmax returns the logarithm of the softmax of x, computed in a numerically stable way.
+// If x is a mini-batch matrix, the function is applied to each column independently.
+func (g *Graph) LogSoftmax(x Node) Node {
+	return g.newOperatorOf(OpLogSoftmax, fn.NewLogSoftmax(x), x)
+}
+
+// LogSumExp returns log(sum(exp(x))), computed in a numerically stable way.
+// If x is a mini-batch matrix, it returns a row vector with the result of each column.
+func (g *Graph) LogSumExp(x Node) Node {
+	return g.newOperatorOf(OpLogSumExp, fn.NewLogSumExp(x), x)
+}
+
+// GELU returns the Gaussian Error Linear Unit x·Φ(x), where Φ is the standard normal CDF.
+func (g *Graph) GELU(x Node) Node {
+	return g.newOperatorOf(OpGELU, fn.NewGELU(x), x)
+}
+
+// GELUTanh returns the tanh approximation of the GELU, as used by BERT and GPT.
+func (g *Graph) GELUTanh(x Node) Node {
+	return g.newOperatorOf(OpGELUTanh, fn.NewGELUTanh(x), x)
+}
+
+// Softmin returns the softmax of -x, which gives the highest probabilities to the lowest values.
+func (g *Graph) Softmin(x Node) Node {
+	return g.Softmax(g.Neg(x))
+}
+
+// Sparsemax returns the euclidean projection of x onto the probability simplex, which can be sparse.
+// If x is a mini-batch matrix, the function is applied to each column independently.
+func (g *Graph) Sparsemax(x Node) Node {
+	return g.newOperatorOf(OpSparsemax, fn.NewSparsemax(x), x)
+}
+
+// Entmax returns the alpha-entmax of x, which is the softmax for alpha → 1 and the sparsemax for alpha = 2.
+// If x is a mini-batch matrix, the function is applied to each column independently.
+// It panics if alpha is not greater than one.
+func (g *Graph) Entmax(x Node, alpha float64) Node {
+	return g.NewOperator(fn.NewEntmax(x, alpha), x)
+}
+
+// Entmax15 returns the 1.5-entmax of x (see Entmax).
+func (g *Graph) Entmax15(x Node) Node {
+	return g.Entmax(x, 1.5)
 }
 
 // Sin
 func (g *Graph) Sin(x Node) Node {
-	return g.NewOperator(fn.NewSin(x), x)
+	return g.newOperatorOf(OpSin, fn.NewSin(x), x)
 }
 
 // Cos
 func (g *Graph) Cos(x Node) Node {
-	return g.NewOperator(fn.NewCos(x), x)
+	return g.newOperatorOf(OpCos, fn.NewCos(x), x)
 }
 
 // Exp
 func (g *Graph) Exp(x Node) Node {
-	return g.NewOperator(fn.NewExp(x), x)
+	return g.newOperatorOf(OpExp, fn.NewExp(x), x)
 }
 
 // Log
 func (g *Graph) Log(x Node) Node {
-	return g.NewOperator(fn.NewLog(x), x)
+	return g.newOperatorOf(OpLog, fn.NewLog(x), x)
 }
 
 // Abs
 func (g *Graph) Abs(x Node) Node {
-	return g.NewOperator(fn.NewAbs(x), x)
+	return g.newOperatorOf(OpAbs, fn.NewAbs(x), x)
 }
 
 // Neg
 func (g *Graph) Neg(x Node) Node {
-	return g.NewOperator(fn.NewNeg(x), x)
+	return g.newOperatorOf(OpNeg, fn.NewNeg(x), x)
 }
 
 // Reciprocal
 func (g *Graph) Reciprocal(x Node) Node {
-	return g.NewOperator(fn.NewReciprocal(x), x)
+	return g.newOperatorOf(OpReciprocal, fn.NewReciprocal(x), x)
 }
 
 // ReduceSum
 func (g *Graph) ReduceSum(x Node) Node {
-	return g.NewOperator(fn.NewReduceSum(x), x)
+	return g.newOperatorOf(OpReduceSum, fn.NewReduceSum(x), x)
 }
 
 // ReduceMean
 func (g *Graph) ReduceMean(x Node) Node {
-	return g.NewOperator(fn.NewReduceMean(x), x)
+	return g.newOperatorOf(OpReduceMean, fn.NewReduceMean(x), x)
 }
 
 // Concat
 func (g *Graph) Concat(xs ...Node) Node {
-	return g.NewOperator(fn.NewConcat(operands(xs)), xs...)
+	return g.newOperatorOf(OpConcat, fn.NewConcat(operands(xs)), xs...)
 }
 
 // Stack
 func (g *Graph) Stack(xs ...Node) Node {
-	return g.NewOperator(fn.NewStack(operands(xs)), xs...)
+	return g.newOperatorOf(OpStack, fn.NewStack(operands(xs)), xs...)
+}
+
+// Permute reorders the dimensions of the n-dimensional tensor of the given shape held by x (see fn.Permute).
+//
+// The nodes hold 2-D matrices only, so the n-dimensional operators (Permute, ReduceSumAxis, ReduceMeanAxis and
+// ReduceMaxAxis) don't carry the shape: the caller passes it on each call, and keeps track of the shape of the
+// output, i.e. the permuted or reduced one. The other operators, including the convolution and the attention,
+// work on 2-D matrices.
+func (g *Graph) Permute(x Node, shape []int, axes ...int) Node {
+	return g.newOperatorOf(OpPermute, fn.NewPermute(x, shape, axes), x)
+}
+
+// ReduceSumAxis sums the values of the n-dimensional tensor of the given shape held by x along the axis.
+func (g *Graph) ReduceSumAxis(x Node, shape []int, axis int) Node {
+	return g.newOperatorOf(OpReduceSumAxis, fn.NewReduceSumAxis(x, shape, axis), x)
+}
+
+// ReduceMeanAxis averages the values of the n-dimensional tensor of the given shape held by x along the axis.
+func (g *Graph) ReduceMeanAxis(x Node, shape []int, axis int) Node {
+	return g.DivScalar(g.ReduceSumAxis(x, shape, axis), g.NewScalar(float64(shape[axis])))
+}
+
+// ReduceMaxAxis takes the max of the values of the n-dimensional tensor of the given shape held by x along the axis.
+func (g *Graph) ReduceMaxAxis(x Node, shape []int, axis int) Node {
+	return g.newOperatorOf(OpReduceMaxAxis, fn.NewReduceMaxAxis(x, shape, axis), x)
 }
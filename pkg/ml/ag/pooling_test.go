@@ -0,0 +1,76 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestGraph_Pooling1D(t *testing.T) {
+	g := NewGraph()
+	xs := []Node{
+		g.NewVariable(mat.NewDense(2, 2, []float64{0.1, -0.2, 0.3, 0.4}), true),
+		g.NewVariable(mat.NewDense(2, 2, []float64{0.7, -0.8, 0.9, 0.2}), true),
+		g.NewVariable(mat.NewDense(2, 2, []float64{-0.5, 0.6, 0.0, 0.3}), true),
+	}
+
+	maxs := g.MaxPooling1D(xs, 2, 1)
+	if len(maxs) != 2 {
+		t.Fatalf("The number of windows doesn't match the expected value")
+	}
+	if r, c := maxs[0].Value().Dims(); r != 2 || c != 2 {
+		t.Error("The dimensions of the output don't match the expected values")
+	}
+	if !floats.EqualApprox(maxs[0].Value().Data(), []float64{0.7, -0.2, 0.9, 0.4}, 1.0e-6) {
+		t.Error("The output of the first window doesn't match the expected values")
+	}
+	if !floats.EqualApprox(maxs[1].Value().Data(), []float64{0.7, 0.6, 0.9, 0.3}, 1.0e-6) {
+		t.Error("The output of the second window doesn't match the expected values")
+	}
+	if !floats.EqualApprox(g.MinPooling1D(xs, 3, 1)[0].Value().Data(), []float64{-0.5, -0.8, 0.0, 0.2}, 1.0e-6) {
+		t.Error("The min over the sequence doesn't match the expected values")
+	}
+
+	y := g.GlobalAvgPooling1D(xs...)
+	if !floats.EqualApprox(y.Value().Data(), []float64{0.1, -0.1333333, 0.4, 0.3}, 1.0e-6) {
+		t.Error("The mean over the sequence doesn't match the expected values")
+	}
+	g.Backward(g.Add(y, g.GlobalMaxPooling1D(xs...)), mat.NewDense(2, 2, []float64{0.3, -0.6, 0.9, 1.2}))
+	expected := [][]float64{
+		{0.1, -0.2, 0.3, 1.6},
+		{0.4, -0.2, 1.2, 0.4},
+		{0.1, -0.8, 0.3, 0.4},
+	}
+	for i, x := range xs {
+		if !floats.EqualApprox(x.Grad().Data(), expected[i], 1.0e-6) {
+			t.Errorf("The gradients of the node %d don't match the expected values", i)
+		}
+	}
+}
+
+func TestGraph_GlobalPooling(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewDense(2, 3, []float64{0.1, -0.2, 0.3, 0.4, -0.6, 0.2}), true)
+	y := g.Add(g.GlobalMaxPooling(x), g.GlobalAvgPooling(x))
+	if y.Value().Size() != 1 || !floats.EqualApprox(y.Value().Data(), []float64{0.4 + 0.2/6}, 1.0e-6) {
+		t.Error("The output doesn't match the expected values")
+	}
+	g.Backward(y)
+	if !floats.EqualApprox(x.Grad().Data(), []float64{1.0 / 6, 1.0 / 6, 1.0 / 6, 7.0 / 6, 1.0 / 6, 1.0 / 6}, 1.0e-6) {
+		t.Error("The gradients don't match the expected values")
+	}
+}
+
+func TestGraph_Pooling1DInvalidWindow(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("The 1-D pooling should panic if the window is larger than the sequence")
+		}
+	}()
+	g := NewGraph()
+	g.MaxPooling1D([]Node{g.NewVariable(mat.NewVecDense([]float64{0.1}), true)}, 2, 1)
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"context"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"io"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Profile enables the profiling of the operators of the graph. The same profiler can be shared by several graphs
+// (e.g. the ones of the training steps), also concurrently.
+func Profile(p *Profiler) GraphOption {
+	return func(g *Graph) {
+		g.profiler = p
+	}
+}
+
+// Profiler collects the execution time, the size of the outputs and the shapes of the operators, grouped by
+// operator (e.g. Tanh, by the OpName they have been created by, or by the function type for those created by
+// NewOperator), both on forward (see NewOperator and ForwardAll) and on backward.
+//
+// The samples of the CPU profile (see pprof.StartCPUProfile) taken during the execution of the operators are
+// labeled with "op", the operator, and "phase", "forward" or "backward", so that the profile can be broken
+// down with the pprof tool (e.g. go tool pprof -tagfocus=op=Mul, or -tagroot=op,phase).
+// The profiler also enables the statistics of the workspace pool of the matrices (see mat.EnableWorkspaceStats),
+// which are process-wide, until it is closed.
+type Profiler struct {
+	mu    sync.Mutex
+	stats map[string]*OpStats
+	// workspace contains the statistics of the workspace pool when the profiling started
+	workspace mat.WorkspaceStats
+	closed    bool
+}
+
+// OpStats contains the statistics of an operator.
+type OpStats struct {
+	// Name is the name of the operator (see Profiler)
+	Name     string
+	Forward  PhaseStats
+	Backward PhaseStats
+	// Shapes counts the forwards by the dimensions of the operands and of the output (e.g. "3x2 2x1 -> 3x1")
+	Shapes map[string]int
+}
+
+// PhaseStats contains the statistics of the operators on forward or on backward.
+type PhaseStats struct {
+	Calls int
+	Time  time.Duration
+	// Bytes is the size of the matrices produced: the values on forward, the gradients of the operands on backward.
+	// It is not the memory allocated, which is lower as far as the matrices are reused from the workspace pool
+	// (see WorkspaceStats), and it doesn't include the intermediate matrices of the functions.
+	Bytes int
+}
+
+// NewProfiler returns a new profiler, enabling the statistics of the workspace pool until it is closed.
+func NewProfiler() *Profiler {
+	mat.EnableWorkspaceStats(true)
+	return &Profiler{
+		stats:     make(map[string]*OpStats),
+		workspace: mat.ReadWorkspaceStats(),
+	}
+}
+
+// Close disables the statistics of the workspace pool enabled by NewProfiler, unless other profilers still
+// require them. The statistics of the operators are still collected and can be read.
+func (p *Profiler) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	mat.EnableWorkspaceStats(false)
+}
+
+// Reset clears the statistics collected so far.
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats = make(map[string]*OpStats)
+	p.workspace = mat.ReadWorkspaceStats()
+}
+
+// Stats returns the statistics of the operators, sorted by the total time in descending order.
+func (p *Profiler) Stats() []OpStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]OpStats, 0, len(p.stats))
+	for _, s := range p.stats {
+		c := *s
+		c.Shapes = make(map[string]int, len(s.Shapes))
+		for k, v := range s.Shapes {
+			c.Shapes[k] = v
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ti, tj := out[i].Forward.Time+out[i].Backward.Time, out[j].Forward.Time+out[j].Backward.Time
+		if ti != tj {
+			return ti > tj
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// WorkspaceStats returns the statistics of the workspace pool since the profiling started, collected until the
+// profiler is closed. They are process-wide, so they include the matrices used outside the profiled graphs.
+func (p *Profiler) WorkspaceStats() mat.WorkspaceStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return mat.ReadWorkspaceStats().Sub(p.workspace)
+}
+
+// WriteReport writes a table with the statistics of the operators, followed by the ones of the workspace pool.
+// The bytes are the sizes of the outputs (see PhaseStats).
+func (p *Profiler) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operator\tforward\ttime\tout bytes\tbackward\ttime\tgrad bytes\ttop shape\t")
+	for _, s := range p.Stats() {
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%d\t%d\t%v\t%d\t%s\t\n", s.Name,
+			s.Forward.Calls, s.Forward.Time, s.Forward.Bytes,
+			s.Backward.Calls, s.Backward.Time, s.Backward.Bytes,
+			topShape(s.Shapes))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	ws := p.WorkspaceStats()
+	_, err := fmt.Fprintf(w, "workspace: %d gets, %d hits, %d misses, %d releases\n",
+		ws.Gets, ws.Hits(), ws.Misses, ws.Releases)
+	return err
+}
+
+// topShape returns the most frequent shape, if any.
+func topShape(shapes map[string]int) string {
+	top, max := "", 0
+	for shape, n := range shapes {
+		if n > max || n == max && shape < top {
+			top, max = shape, n
+		}
+	}
+	return top
+}
+
+func (p *Profiler) forward(op OpName, f fn.Function, operands []Node) mat.Matrix {
+	var value mat.Matrix
+	name := operatorName(op, f)
+	start := time.Now()
+	pprof.Do(context.Background(), pprof.Labels("op", name, "phase", "forward"), func(context.Context) {
+		value = f.Forward()
+	})
+	elapsed := time.Since(start)
+	shape := shapeOf(operands, value)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.statsOf(name)
+	s.Forward.Calls++
+	s.Forward.Time += elapsed
+	s.Forward.Bytes += sizeOf(value)
+	s.Shapes[shape]++
+	return value
+}
+
+func (p *Profiler) backward(op OpName, f fn.Function, operands []Node, gy mat.Matrix) {
+	name := operatorName(op, f)
+	start := time.Now()
+	pprof.Do(context.Background(), pprof.Labels("op", name, "phase", "backward"), func(context.Context) {
+		f.Backward(gy)
+	})
+	elapsed := time.Since(start)
+	bytes := 0
+	for _, x := range operands {
+		if x.RequiresGrad() {
+			bytes += sizeOf(x.Value())
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.statsOf(name)
+	s.Backward.Calls++
+	s.Backward.Time += elapsed
+	s.Backward.Bytes += bytes
+}
+
+func (p *Profiler) statsOf(name string) *OpStats {
+	s, ok := p.stats[name]
+	if !ok {
+		s = &OpStats{Name: name, Shapes: make(map[string]int)}
+		p.stats[name] = s
+	}
+	return s
+}
+
+// shapeOf returns the dimensions of the operands and of the output, e.g. "3x2 2x1 -> 3x1".
+func shapeOf(operands []Node, value mat.Matrix) string {
+	dims := make([]string, len(operands))
+	for i, x := range operands {
+		dims[i] = fmt.Sprintf("%dx%d", x.Value().Rows(), x.Value().Columns())
+	}
+	return fmt.Sprintf("%s -> %dx%d", strings.Join(dims, " "), value.Rows(), value.Columns())
+}
+
+// sizeOf returns the size in bytes of the values of the matrix.
+func sizeOf(m mat.Matrix) int {
+	if _, ok := m.(*mat.Dense32); ok {
+		return m.Size() * 4
+	}
+	return m.Size() * 8
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"bytes"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"strings"
+	"testing"
+)
+
+func TestProfiler(t *testing.T) {
+	p := NewProfiler()
+	defer p.Close()
+	g := NewGraph(Profile(p))
+	w := g.NewVariable(mat.NewDense(3, 2, []float64{0.1, -0.2, 0.3, 0.4, -0.5, 0.6}), true)
+	b := g.NewVariable(mat.NewVecDense([]float64{0.1, 0.0, -0.1}), true)
+	for _, x := range []float64{0.5, -1.0} {
+		y := g.Tanh(g.Add(g.Mul(w, g.NewVariable(mat.NewVecDense([]float64{x, x}), false)), b))
+		g.Backward(y)
+		g.ZeroGrad()
+	}
+
+	stats := make(map[string]OpStats)
+	for _, s := range p.Stats() {
+		stats[s.Name] = s
+	}
+	mul, ok := stats["Mul"]
+	if !ok {
+		t.Fatal("Missing the statistics of Mul")
+	}
+	if mul.Forward.Calls != 2 || mul.Backward.Calls != 2 {
+		t.Errorf("Unexpected number of calls %d, %d", mul.Forward.Calls, mul.Backward.Calls)
+	}
+	if mul.Forward.Bytes != 2*3*8 || mul.Backward.Bytes != 2*6*8 {
+		t.Errorf("Unexpected number of bytes %d, %d", mul.Forward.Bytes, mul.Backward.Bytes)
+	}
+	if mul.Shapes["3x2 2x1 -> 3x1"] != 2 {
+		t.Errorf("Unexpected shapes %v", mul.Shapes)
+	}
+	if _, ok := stats["Tanh"]; !ok || len(stats) != 3 {
+		t.Errorf("Unexpected operators %v", stats)
+	}
+	if p.WorkspaceStats().Gets == 0 {
+		t.Error("The workspace statistics have not been collected")
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Mul") || !strings.Contains(buf.String(), "workspace:") {
+		t.Errorf("Unexpected report:\n%s", buf.String())
+	}
+
+	p.Reset()
+	if len(p.Stats()) != 0 {
+		t.Error("The statistics have not been cleared")
+	}
+}
+
+func TestProfiler_Operators(t *testing.T) {
+	p := NewProfiler()
+	g := NewGraph(Profile(p))
+	x := g.NewVariable(mat.NewVecDense([]float64{0.1, -0.2}), true)
+	g.Backward(g.ReduceSum(g.Add(g.Tanh(x), g.Sigmoid(x))))
+	g.NewOperator(fn.NewSigmoid(x), x)
+
+	names := make(map[string]int)
+	for _, s := range p.Stats() {
+		names[s.Name] = s.Forward.Calls
+	}
+	for name, calls := range map[string]int{"Tanh": 1, "Sigmoid": 1, "UnaryElementwise": 1} {
+		if names[name] != calls {
+			t.Errorf("Unexpected statistics of %s: %v", name, names)
+		}
+	}
+
+	p.Close()
+	p.Close() // no effect
+	prev := mat.ReadWorkspaceStats()
+	mat.ReleaseMatrix(mat.GetDenseWorkspace(2, 2))
+	if mat.ReadWorkspaceStats() != prev {
+		t.Error("The workspace statistics should be disabled once the profiler is closed")
+	}
+}
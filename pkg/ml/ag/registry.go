@@ -0,0 +1,130 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"sync"
+)
+
+// OpFactory returns a new function of the given operands.
+type OpFactory func(xs ...fn.Operand) fn.Function
+
+// firstCustomOp is the first OpName assigned to the custom operators, far from the built-in ones.
+const firstCustomOp OpName = 1 << 16
+
+// opUnknown marks the operators created by a function rather than by an OpName (see NewOperator).
+const opUnknown OpName = -1
+
+type customOp struct {
+	name    string
+	factory OpFactory
+}
+
+var customOps = struct {
+	sync.RWMutex
+	byOp   map[OpName]customOp
+	byName map[string]OpName
+}{
+	byOp:   make(map[OpName]customOp),
+	byName: make(map[string]OpName),
+}
+
+// RegisterOp registers a custom operator under the given name and returns its OpName, which can be used wherever
+// an OpName is accepted (e.g. as activation of a perceptron). Invoking the operator creates a new operator node
+// with the function returned by the factory, whose operands are the nodes given to Invoke.
+// The gradients of the function can be extended with RegisterGradFunc, and its forward-mode derivatives by
+// implementing fn.ForwardDifferentiable.
+//
+// The OpName values of the custom operators depend on the registration order, so a custom operator is encoded in
+// JSON by its name (see MarshalJSON): the operators must be registered, e.g. in an init function, before loading
+// a model. Other encodings, such as gob, keep the plain number, which is only stable for a fixed registration order.
+// It panics if the name is already in use.
+func RegisterOp(name string, factory OpFactory) OpName {
+	customOps.Lock()
+	defer customOps.Unlock()
+	if _, ok := builtinOpByName(name); ok {
+		panic(fmt.Sprintf("ag: the operator name %q is already in use", name))
+	}
+	if _, ok := customOps.byName[name]; ok {
+		panic(fmt.Sprintf("ag: the operator name %q is already in use", name))
+	}
+	op := firstCustomOp + OpName(len(customOps.byOp))
+	customOps.byOp[op] = customOp{name: name, factory: factory}
+	customOps.byName[name] = op
+	return op
+}
+
+// LookupOp returns the OpName of the built-in or custom operator with the given name.
+func LookupOp(name string) (OpName, bool) {
+	if op, ok := builtinOpByName(name); ok {
+		return op, true
+	}
+	customOps.RLock()
+	defer customOps.RUnlock()
+	op, ok := customOps.byName[name]
+	return op, ok
+}
+
+func builtinOpByName(name string) (OpName, bool) {
+	for op, methodName := range opNameToMethodName {
+		if methodName == name {
+			return op, true
+		}
+	}
+	return 0, false
+}
+
+func lookupCustomOp(op OpName) (customOp, bool) {
+	customOps.RLock()
+	defer customOps.RUnlock()
+	c, ok := customOps.byOp[op]
+	return c, ok
+}
+
+// String returns the name of the operator.
+func (op OpName) String() string {
+	if name, ok := opNameToMethodName[op]; ok {
+		return name
+	}
+	if c, ok := lookupCustomOp(op); ok {
+		return c.name
+	}
+	return fmt.Sprintf("OpName(%d)", int(op))
+}
+
+// MarshalJSON encodes the built-in operators by number, as they have always been, and the custom operators by
+// name, so that they are preserved regardless of their registration order. It implements json.Marshaler.
+func (op OpName) MarshalJSON() ([]byte, error) {
+	if _, ok := opNameToMethodName[op]; ok {
+		return json.Marshal(int(op))
+	}
+	if c, ok := lookupCustomOp(op); ok {
+		return json.Marshal(c.name)
+	}
+	return nil, fmt.Errorf("ag: unknown operator %d", int(op))
+}
+
+// UnmarshalJSON decodes an operator either by number, as encoded before the introduction of the custom operators,
+// or by name. Custom operators must be registered beforehand. It implements json.Unmarshaler.
+func (op *OpName) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*op = OpName(n)
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("ag: invalid operator %s", data)
+	}
+	v, ok := LookupOp(name)
+	if !ok {
+		return fmt.Errorf("ag: unknown operator %q", name)
+	}
+	*op = v
+	return nil
+}
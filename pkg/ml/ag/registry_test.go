@@ -0,0 +1,110 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"gonum.org/v1/gonum/floats"
+	"strings"
+	"testing"
+)
+
+var opTestCube = RegisterOp("TestCube", func(xs ...fn.Operand) fn.Function {
+	return fn.NewPow(xs[0], 3)
+})
+
+func TestRegisterOp(t *testing.T) {
+	g := NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1, 2, -3}), true)
+	y := g.Invoke(opTestCube, x)
+	if !floats.EqualApprox(y.Value().Data(), []float64{1, 8, -27}, 1.0e-12) {
+		t.Errorf("The output doesn't match the expected values.")
+	}
+	g.Backward(y)
+	if !floats.EqualApprox(x.Grad().Data(), []float64{3, 12, 27}, 1.0e-12) {
+		t.Errorf("The gradients don't match the expected values.")
+	}
+}
+
+func TestRegisterOp_DuplicateName(t *testing.T) {
+	for _, name := range []string{"TestCube", "ReLU"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Registering %q should panic.", name)
+				}
+			}()
+			RegisterOp(name, nil)
+		}()
+	}
+}
+
+func TestOpName_MarshalJSON(t *testing.T) {
+	type config struct {
+		Hidden OpName
+		Output OpName
+	}
+	data, err := json.Marshal(config{Hidden: opTestCube, Output: OpReLU})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Hidden":"TestCube","Output":31}` {
+		t.Errorf("Unexpected encoding %s.", data)
+	}
+	var c config
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Hidden != opTestCube || c.Output != OpReLU {
+		t.Errorf("The decoded operators don't match the expected values.")
+	}
+	if err := json.Unmarshal([]byte(`{"Hidden":"ReLU"}`), &c); err != nil || c.Hidden != OpReLU {
+		t.Errorf("The built-in operators should be decoded by name too.")
+	}
+	if err := json.Unmarshal([]byte(`{"Hidden":"Unknown"}`), &c); err == nil {
+		t.Errorf("Decoding an unknown operator should fail.")
+	}
+}
+
+// legacyConfig is a configuration encoded when OpName was a plain number: {Size: 4, Activation: OpReLU}.
+var legacyConfig = struct {
+	json string
+	gob  string
+}{
+	json: `{"Size":4,"Activation":31}`,
+	gob:  "+\x7f\x03\x01\x01\x06config\x01\xff\x80\x00\x01\x02\x01\x04Size\x01\x04\x00\x01\nActivation\x01\x04\x00\x00\x00\a\xff\x80\x01\b\x01>\x00",
+}
+
+func TestOpName_DecodeLegacy(t *testing.T) {
+	type config struct {
+		Size       int
+		Activation OpName
+	}
+	var c config
+	if err := json.Unmarshal([]byte(legacyConfig.json), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Size != 4 || c.Activation != OpReLU {
+		t.Errorf("The JSON configuration doesn't match the expected values: %+v.", c)
+	}
+	c = config{}
+	if err := gob.NewDecoder(strings.NewReader(legacyConfig.gob)).Decode(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Size != 4 || c.Activation != OpReLU {
+		t.Errorf("The gob configuration doesn't match the expected values: %+v.", c)
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(c); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != legacyConfig.gob {
+		t.Errorf("The gob encoding should not change.")
+	}
+}
@@ -11,9 +11,10 @@ import (
 )
 
 var (
-	_ fn.Operand = &variable{}
-	_ GradValue  = &variable{}
-	_ Node       = &variable{}
+	_ fn.Operand           = &variable{}
+	_ GradValue            = &variable{}
+	_ Node                 = &variable{}
+	_ fn.RowGradPropagator = &variable{}
 )
 
 type variable struct {
@@ -25,6 +26,8 @@ type variable struct {
 	grad         mat.Matrix // TODO: support of sparse gradients
 	hasGrad      bool
 	requiresGrad bool
+	hooks        Hooks        // called on the propagation of the gradients (see RegisterHook)
+	numerics     gradNumerics // records the non-finite gradients propagated to the node (see CheckNumerics)
 }
 
 // Id returns the id of the node in the graph.
@@ -59,6 +62,10 @@ func (r *variable) PropagateGrad(grad mat.Matrix) {
 	if !r.requiresGrad {
 		return
 	}
+	r.numerics.check(r.graph, grad)
+	if grad = r.hooks.Apply(grad); grad == nil {
+		return
+	}
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.grad == nil {
@@ -68,6 +75,31 @@ func (r *variable) PropagateGrad(grad mat.Matrix) {
 	r.hasGrad = true
 }
 
+// PropagateRowGrad accumulates the gradients of the rows at the indices to the node itself (see
+// fn.RowGradPropagator). The gradients are passed to the hooks as dense gradients of the dimensions of the node.
+func (r *variable) PropagateRowGrad(indices []int, gx mat.Matrix) {
+	if !r.requiresGrad {
+		return
+	}
+	if r.hooks.Len() > 0 {
+		propagateDenseRowGrad(r, indices, gx)
+		return
+	}
+	r.numerics.check(r.graph, gx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.grad == nil {
+		r.grad = mat.GetEmptyDenseWorkspace(r.value.Dims())
+	}
+	fn.AddRowsInPlace(r.grad, indices, gx)
+	r.hasGrad = true
+}
+
+// RegisterHook registers a function called with the gradients propagated to the node, before they are accumulated.
+func (r *variable) RegisterHook(hook GradHook) {
+	r.hooks.Register(hook)
+}
+
 // HasGrad returns true if there are accumulated gradients.
 func (r *variable) HasGrad() bool {
 	return r.hasGrad
@@ -83,7 +115,7 @@ func (r *variable) ZeroGrad() {
 	if r.grad == nil {
 		return
 	}
-	defer mat.ReleaseDense(r.grad.(*mat.Dense)) // release memory
+	defer mat.ReleaseMatrix(r.grad) // release memory
 	r.grad = nil
 	r.hasGrad = false
 }
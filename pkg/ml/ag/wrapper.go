@@ -10,9 +10,10 @@ import (
 )
 
 var (
-	_ fn.Operand = &wrapper{}
-	_ GradValue  = &wrapper{}
-	_ Node       = &wrapper{}
+	_ fn.Operand           = &wrapper{}
+	_ GradValue            = &wrapper{}
+	_ Node                 = &wrapper{}
+	_ fn.RowGradPropagator = &wrapper{}
 )
 
 type wrapper struct {
@@ -21,6 +22,8 @@ type wrapper struct {
 	timeStep int64
 	id       int64
 	wrapGrad bool
+	hooks    Hooks        // called on the propagation of the gradients (see RegisterHook)
+	numerics gradNumerics // records the non-finite gradients propagated to the node (see CheckNumerics)
 }
 
 // Id returns the id of the node in the graph.
@@ -46,9 +49,34 @@ func (r *wrapper) PropagateGrad(gx mat.Matrix) {
 	if !r.wrapGrad {
 		return
 	}
+	r.numerics.check(r.graph, gx)
+	if gx = r.hooks.Apply(gx); gx == nil {
+		return
+	}
 	r.GradValue.PropagateGrad(gx)
 }
 
+// PropagateRowGrad propagates the gradients of the rows at the indices to the wrapped value, by row if it
+// supports it (see fn.RowGradPropagator), as dense gradients otherwise or if the node has hooks.
+func (r *wrapper) PropagateRowGrad(indices []int, gx mat.Matrix) {
+	if !r.wrapGrad {
+		return
+	}
+	p, ok := r.GradValue.(fn.RowGradPropagator)
+	if !ok || r.hooks.Len() > 0 {
+		propagateDenseRowGrad(r, indices, gx)
+		return
+	}
+	r.numerics.check(r.graph, gx)
+	p.PropagateRowGrad(indices, gx)
+}
+
+// RegisterHook registers a function called with the gradients propagated to the node, before they are passed
+// to the wrapped value (e.g. a parameter, which can have hooks of its own).
+func (r *wrapper) RegisterHook(hook GradHook) {
+	r.hooks.Register(hook)
+}
+
 // HasGrad returns true if there are accumulated gradients.
 func (r *wrapper) HasGrad() bool {
 	if !r.wrapGrad {
@@ -0,0 +1,66 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package determinism provides a global deterministic mode, to rerun the training and the inference of a model
+// obtaining bit-exact results, e.g. for regression testing.
+//
+// While the mode is enabled:
+//
+//   - the processors, the attention and the other components which would build the graph concurrently (e.g. the
+//     Concurrency option of the perceptron, the GateConcurrency option of the LSTM, the bidirectional RNN) build it
+//     serially, so that the nodes are always created in the same order, the random numbers (e.g. of the dropout)
+//     are always drawn in the same order, and the gradients are accumulated in the same order by the back-propagation;
+//   - the functions propagate the gradients to their operands serially (e.g. fn.Mul);
+//   - the optimizer updates the parameters serially, visiting them in the order they have been tracked, so that
+//     the gradient clipping by norm always sums them in the same order;
+//   - the global random of the mat/rand package (used e.g. by rand.WeightedChoice in data.GenerateBatches) is
+//     replaced by a generator seeded with the given seed (see rand.SetGlobal), and the graphs created without
+//     a random generator (see ag.Rand) use a generator seeded with it too.
+//
+// The concurrent back-propagation (see ag.ConcurrentBackward) is deterministic in any case, so it is not affected.
+// The results are reproducible on the same machine and build: the mode doesn't cover the differences between
+// architectures or assembly kernels, nor the randomness drawn from generators created elsewhere with
+// non-fixed seeds. The random state is reset by Enable, which must then be called at the beginning of each run.
+package determinism
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	enabled int32
+	mu      sync.Mutex
+	seed    uint64
+)
+
+// Enable enables the deterministic mode, seeding the global random with the given seed.
+func Enable(globalSeed uint64) {
+	mu.Lock()
+	defer mu.Unlock()
+	seed = globalSeed
+	rand.SetGlobal(rand.NewLockedRand(globalSeed))
+	atomic.StoreInt32(&enabled, 1)
+}
+
+// Disable disables the deterministic mode, restoring the global random.
+func Disable() {
+	mu.Lock()
+	defer mu.Unlock()
+	rand.SetGlobal(nil)
+	atomic.StoreInt32(&enabled, 0)
+}
+
+// Enabled reports whether the deterministic mode is enabled.
+func Enabled() bool {
+	return atomic.LoadInt32(&enabled) != 0
+}
+
+// Seed returns the seed given to Enable.
+func Seed() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	return seed
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package determinism
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
+	"reflect"
+	"testing"
+)
+
+func TestEnable(t *testing.T) {
+	dist := []float64{0.2, 0.3, 0.5}
+	draw := func() []int {
+		out := make([]int, 20)
+		for i := range out {
+			out[i] = rand.WeightedChoice(dist)
+		}
+		return out
+	}
+
+	Enable(7)
+	defer Disable()
+	if !Enabled() || Seed() != 7 {
+		t.Fatal("The deterministic mode has not been enabled")
+	}
+	first := draw()
+	Enable(7)
+	if !reflect.DeepEqual(first, draw()) {
+		t.Error("The global random is not reproducible")
+	}
+	Disable()
+	if Enabled() {
+		t.Error("The deterministic mode has not been disabled")
+	}
+}
@@ -5,10 +5,10 @@
 package emb
 
 import (
+	"errors"
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
 	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
-	"log"
 )
 
 type Embedding struct {
@@ -16,15 +16,19 @@ type Embedding struct {
 	storage *Map
 }
 
+// ErrReadOnly is raised by the embeddings of a read-only storage on update.
+var ErrReadOnly = errors.New("emb: read-only embeddings cannot apply delta")
+
 // ApplyDelta applies the dalta and updates the entry of the underlying storage.
-// It panics if the storage is read-only.
+// It panics with ErrReadOnly if the storage is read-only, and with the error of the storage if the update fails,
+// so that the error can be recovered (see ag.Try).
 func (e *Embedding) ApplyDelta(delta mat.Matrix) {
 	if e.storage.ReadOnly {
-		log.Fatal("emb: read-only embeddings cannot apply delta")
+		panic(ErrReadOnly)
 	}
 	e.Param.ApplyDelta(delta)
 	if _, err := e.storage.update(e); err != nil {
-		log.Fatal(err)
+		panic(err)
 	}
 }
 
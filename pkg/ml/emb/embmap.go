@@ -15,7 +15,6 @@ import (
 	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
 	"github.com/nlpodyssey/spago/pkg/utils"
 	"io"
-	"log"
 	"os"
 )
 
@@ -43,7 +42,7 @@ func NewMapConfig(dim int, path string, readOnly bool) Config {
 }
 
 // NewMap returns a new empty embedding map.
-func NewMap(config Config) *Map {
+func NewMap(config Config) (*Map, error) {
 	db, err := badger.Open(
 		badger.DefaultOptions(config.Path).
 			WithReadOnly(config.ReadOnly).
@@ -51,16 +50,16 @@ func NewMap(config Config) *Map {
 			WithLogger(nil),
 	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	return &Map{Config: config, db: db}
+	return &Map{Config: config, db: db}, nil
 }
 
 // Load inserts the pre-trained embeddings into the map.
-func (m *Map) Load(filename string) {
+func (m *Map) Load(filename string) error {
 	count, err := utils.CountLines(filename)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	uip := uiprogress.New()
@@ -71,7 +70,7 @@ func (m *Map) Load(filename string) {
 
 	file, err := os.Open(filename)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer file.Close()
 
@@ -86,25 +85,23 @@ func (m *Map) Load(filename string) {
 			strVec := utils.AfterSpace(line)
 			data, err := f64utils.StrToFloat64Slice(strVec)
 			if err != nil {
-				log.Fatal(err)
+				return err
+			}
+			if err := m.SetVec(key, mat.NewVecDense(data)); err != nil {
+				return err
 			}
-			m.SetVec(key, mat.NewVecDense(data))
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
+	return scanner.Err()
 }
 
 // Close the embeddings map.
-func (m *Map) Close() {
-	if err := m.db.Close(); err != nil {
-		log.Fatal(err)
-	}
+func (m *Map) Close() error {
+	return m.db.Close()
 }
 
-// Lookup
-func (m *Map) Lookup(ks ...string) []*Embedding {
+// Lookup returns the embeddings of the keys. The embeddings of the keys that are not in the map are nil.
+func (m *Map) Lookup(ks ...string) ([]*Embedding, error) {
 	result := make([]*Embedding, len(ks))
 	cache := make(map[string]*Embedding)
 	for i, k := range ks {
@@ -112,7 +109,11 @@ func (m *Map) Lookup(ks ...string) []*Embedding {
 			result[i] = item
 		} else {
 			var newEmbedding *Embedding
-			if value, ok := m.getEntry(k); !ok {
+			value, ok, err := m.getEntry(k)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
 				newEmbedding = nil
 			} else {
 				param := nn.NewParam(value.vec) // TODO: set requireGrad = !m.ReadOnly
@@ -124,26 +125,23 @@ func (m *Map) Lookup(ks ...string) []*Embedding {
 			result[i] = newEmbedding
 		}
 	}
-	return result
+	return result, nil
 }
 
-// SetVec
-func (m *Map) SetVec(key string, vector *mat.Dense) {
+// SetVec stores the vector as the embedding of the key.
+func (m *Map) SetVec(key string, vector *mat.Dense) error {
 	var buf bytes.Buffer
 	if _, err := mat.MarshalBinaryTo(vector, &buf); err != nil {
-		log.Fatal(err)
+		return err
 	}
 	if _, err := gd.MarshalBinaryTo(gd.NewEmptySupport(), &buf); err != nil {
-		log.Fatal(err)
+		return err
 	}
-	err := m.db.Update(func(txn *badger.Txn) error {
+	return m.db.Update(func(txn *badger.Txn) error {
 		e := badger.NewEntry([]byte(key), buf.Bytes())
 		err := txn.SetEntry(e)
 		return err // end view
 	})
-	if err != nil {
-		log.Fatal(err)
-	}
 }
 
 // Keys
@@ -198,7 +196,8 @@ func unmarshalBinaryFrom(r io.Reader) (*entry, int, error) {
 	return &entry{vec: vec, supp: supp}, n, err
 }
 
-func (m *Map) getEntry(key string) (*entry, bool) {
+// getEntry returns the entry of the key, and whether it has been found.
+func (m *Map) getEntry(key string) (*entry, bool, error) {
 	var value *entry
 	err := m.db.View(func(txn *badger.Txn) error {
 		item, err := txn.Get([]byte(key))
@@ -210,15 +209,15 @@ func (m *Map) getEntry(key string) (*entry, bool) {
 			return err
 		}
 		value, _, err = unmarshalBinaryFrom(bytes.NewReader(valCopy))
-		return nil // end view
+		return err // end view
 	})
 	if err != nil {
 		if err == badger.ErrKeyNotFound {
-			return nil, false
+			return nil, false, nil
 		}
-		log.Fatal(err)
+		return nil, false, err
 	}
-	return value, true
+	return value, true, nil
 }
 
 func copyValue(item *badger.Item) ([]byte, error) {
@@ -236,6 +235,9 @@ func (m *Map) update(e *Embedding) (int, error) {
 		vec:  e.Value().(*mat.Dense),
 		supp: e.Support(),
 	}, &buf)
+	if err != nil {
+		return n, err
+	}
 	err = m.db.Update(func(txn *badger.Txn) error {
 		entry := badger.NewEntry([]byte(e.Param.Name()), buf.Bytes())
 		err := txn.SetEntry(entry)
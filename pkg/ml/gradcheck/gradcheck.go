@@ -0,0 +1,248 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gradcheck verifies the gradients computed by the back-propagation, comparing them with
+// numerical approximations obtained by central finite differences.
+//
+// The output y of the function (or the model) under test is reduced to the scalar sum(y ⊙ gy), where gy is a
+// random matrix used as the output gradients of the backward pass. Each element of the inputs (or of the
+// parameters) is then perturbed by ±epsilon to approximate the derivative of the scalar with respect to it.
+package gradcheck
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"math"
+)
+
+const (
+	defaultEpsilon = 1.0e-6
+	defaultSeed    = 42
+	// minDenominator avoids to amplify the numerical noise in the relative errors of near-zero gradients.
+	minDenominator = 1.0e-8
+)
+
+type config struct {
+	epsilon float64
+	seed    uint64
+}
+
+type Option func(*config)
+
+// Epsilon sets the perturbation of the finite differences (default 1e-6).
+func Epsilon(epsilon float64) Option {
+	return func(c *config) {
+		c.epsilon = epsilon
+	}
+}
+
+// Seed sets the seed of the random output gradients.
+func Seed(seed uint64) Option {
+	return func(c *config) {
+		c.seed = seed
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		epsilon: defaultEpsilon,
+		seed:    defaultSeed,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Result reports the comparison between the analytic and the numeric gradients of an input or a parameter.
+// The gradients are listed in row-major order.
+type Result struct {
+	// Name is the name of the parameter, or "x<i>" for the i-th input of a function.
+	Name     string
+	Analytic []float64
+	Numeric  []float64
+	// RelErrors contains the relative error of each element: |a - n| / max(|a|, |n|).
+	RelErrors []float64
+}
+
+// MaxRelError returns the highest relative error of the result.
+func (r *Result) MaxRelError() float64 {
+	max := 0.0
+	for _, e := range r.RelErrors {
+		max = math.Max(max, e)
+	}
+	return max
+}
+
+// MaxRelError returns the highest relative error of the results.
+func MaxRelError(rs []*Result) float64 {
+	max := 0.0
+	for _, r := range rs {
+		max = math.Max(max, r.MaxRelError())
+	}
+	return max
+}
+
+// CheckFn checks the gradients of the function returned by newFn with respect to its operands,
+// whose values are the given inputs. The inputs are not modified.
+func CheckFn(newFn func(xs ...fn.Operand) fn.Function, inputs []mat.Matrix, opts ...Option) []*Result {
+	return CheckFunction(func(g *ag.Graph, xs ...ag.Node) ag.Node {
+		operands := make([]fn.Operand, len(xs))
+		for i, x := range xs {
+			operands[i] = x
+		}
+		return g.NewOperator(newFn(operands...), xs...)
+	}, inputs, opts...)
+}
+
+// CheckFunction checks the gradients of the output of f with respect to the input nodes xs,
+// whose values are the given inputs. The inputs are not modified.
+func CheckFunction(f func(g *ag.Graph, xs ...ag.Node) ag.Node, inputs []mat.Matrix, opts ...Option) []*Result {
+	c := newConfig(opts)
+	values := make([]mat.Matrix, len(inputs))
+	names := make([]string, len(inputs))
+	for i, in := range inputs {
+		values[i] = in.Clone()
+		names[i] = fmt.Sprintf("x%d", i)
+	}
+	newNodes := func(g *ag.Graph, requiresGrad bool) []ag.Node {
+		xs := make([]ag.Node, len(values))
+		for i, v := range values {
+			xs[i] = g.NewVariable(v, requiresGrad)
+		}
+		return xs
+	}
+
+	g := ag.NewGraph()
+	xs := newNodes(g, true)
+	y := f(g, xs...)
+	gy := randomLike(y.Value(), c.seed)
+	g.Backward(y, gy)
+	analytic := make([][]float64, len(xs))
+	for i, x := range xs {
+		analytic[i] = gradData(x.Grad(), x.Value())
+	}
+	g.Clear()
+
+	return compare(names, values, analytic, c, func() float64 {
+		g := ag.NewGraph()
+		defer g.Clear()
+		return weightedSum(f(g, newNodes(g, false)...).Value(), gy)
+	})
+}
+
+// CheckModel checks the gradients of the output of forward with respect to the parameters of the model
+// that require gradients. The forward function must build the output on the given graph, using a processor of
+// the model (e.g. m.NewProc(g).Forward(...)). The gradients of the parameters are zeroed before and after the check.
+func CheckModel(m nn.Model, forward func(g *ag.Graph) ag.Node, opts ...Option) []*Result {
+	c := newConfig(opts)
+	var params []*nn.Param
+	m.ForEachParam(func(param *nn.Param) {
+		if param.RequiresGrad() {
+			params = append(params, param)
+		}
+	})
+
+	nn.ZeroGrad(m)
+	defer nn.ZeroGrad(m)
+	g := ag.NewGraph()
+	y := forward(g)
+	gy := randomLike(y.Value(), c.seed)
+	g.Backward(y, gy)
+	names := make([]string, len(params))
+	values := make([]mat.Matrix, len(params))
+	analytic := make([][]float64, len(params))
+	for i, param := range params {
+		names[i] = param.Name()
+		values[i] = param.Value()
+		analytic[i] = gradData(param.Grad(), param.Value())
+	}
+	g.Clear()
+
+	return compare(names, values, analytic, c, func() float64 {
+		g := ag.NewGraph()
+		defer g.Clear()
+		return weightedSum(forward(g).Value(), gy)
+	})
+}
+
+// compare perturbs each element of the values to approximate the derivatives of the loss with respect to them,
+// and compares the approximations with the analytic gradients.
+func compare(names []string, values []mat.Matrix, analytic [][]float64, c *config, loss func() float64) []*Result {
+	results := make([]*Result, len(values))
+	for i, v := range values {
+		numeric := make([]float64, v.Size())
+		for k := range numeric {
+			numeric[k] = centralDifference(v, k, c.epsilon, loss)
+		}
+		results[i] = &Result{
+			Name:      names[i],
+			Analytic:  analytic[i],
+			Numeric:   numeric,
+			RelErrors: relErrors(analytic[i], numeric),
+		}
+	}
+	return results
+}
+
+// centralDifference approximates the derivative of the loss with respect to the k-th element of m, in row-major
+// order. The element is restored to its original value on return, even if the loss panics.
+func centralDifference(m mat.Matrix, k int, epsilon float64, loss func() float64) float64 {
+	original := m.Data()[k]
+	defer setAt(m, k, original)
+	setAt(m, k, original+epsilon)
+	plus := loss()
+	setAt(m, k, original-epsilon)
+	minus := loss()
+	return (plus - minus) / (2 * epsilon)
+}
+
+// setAt sets the k-th element of m, in row-major order.
+func setAt(m mat.Matrix, k int, value float64) {
+	data := m.Data()
+	data[k] = value
+	m.SetData(data)
+}
+
+func relErrors(analytic, numeric []float64) []float64 {
+	out := make([]float64, len(analytic))
+	for i, a := range analytic {
+		n := numeric[i]
+		out[i] = math.Abs(a-n) / math.Max(math.Max(math.Abs(a), math.Abs(n)), minDenominator)
+	}
+	return out
+}
+
+// gradData returns a copy of the gradients, or zeros if there are no gradients.
+func gradData(grad, value mat.Matrix) []float64 {
+	if grad == nil {
+		return make([]float64, value.Size())
+	}
+	return append([]float64(nil), grad.Data()...)
+}
+
+// randomLike returns a new matrix with the same dimensions of m, with values uniformly distributed in [-1, 1).
+func randomLike(m mat.Matrix, seed uint64) mat.Matrix {
+	r := rand.NewLockedRand(seed)
+	out := mat.NewEmptyDense(m.Dims())
+	data := out.Data()
+	for i := range data {
+		data[i] = r.Float64()*2 - 1
+	}
+	return out
+}
+
+// weightedSum returns sum(y ⊙ gy).
+func weightedSum(y, gy mat.Matrix) float64 {
+	sum := 0.0
+	gyData := gy.Data()
+	for i, v := range y.Data() {
+		sum += v * gyData[i]
+	}
+	return sum
+}
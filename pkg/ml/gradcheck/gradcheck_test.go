@@ -0,0 +1,99 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradcheck
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/perceptron"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func TestCheckFn(t *testing.T) {
+	results := CheckFn(func(xs ...fn.Operand) fn.Function {
+		return fn.NewProd(xs[0], xs[1])
+	}, []mat.Matrix{
+		mat.NewDense(2, 3, []float64{0.1, -0.2, 0.3, 0.4, 0.5, -0.6}),
+		mat.NewDense(1, 3, []float64{0.7, -0.8, 0.9}), // broadcast over the rows
+	})
+	if len(results) != 2 || results[0].Name != "x0" || len(results[1].Numeric) != 3 {
+		t.Fatal("The results don't match the inputs")
+	}
+	if MaxRelError(results) > 1.0e-6 {
+		t.Errorf("The gradients don't match the numerical approximation: %v", results)
+	}
+}
+
+// wrongSquare computes x^2, but its gradients miss the factor 2.
+type wrongSquare struct {
+	x fn.Operand
+}
+
+func (r *wrongSquare) Forward() mat.Matrix {
+	return r.x.Value().Prod(r.x.Value())
+}
+
+func (r *wrongSquare) Backward(gy mat.Matrix) {
+	r.x.PropagateGrad(r.x.Value().Prod(gy))
+}
+
+func TestCheckFn_WrongGradients(t *testing.T) {
+	results := CheckFn(func(xs ...fn.Operand) fn.Function {
+		return &wrongSquare{x: xs[0]}
+	}, []mat.Matrix{mat.NewVecDense([]float64{0.1, -0.2, 0.3})})
+	if results[0].MaxRelError() < 0.1 {
+		t.Error("The check should detect the wrong gradients")
+	}
+}
+
+func TestCheckModel(t *testing.T) {
+	model := perceptron.New(3, 2, ag.OpTanh)
+	model.W.Value().SetData([]float64{0.5, -0.6, 0.3, 0.7, -0.4, 0.1})
+	model.B.Value().SetData([]float64{0.4, -0.2})
+	x := mat.NewVecDense([]float64{-0.8, -0.9, 0.9})
+	results := CheckModel(model, func(g *ag.Graph) ag.Node {
+		return model.NewProc(g).Forward(g.NewVariable(x, false))[0]
+	})
+	if len(results) != 2 || results[0].Name != "w" || results[1].Name != "b" {
+		t.Fatal("The results don't match the parameters")
+	}
+	if MaxRelError(results) > 1.0e-6 {
+		t.Errorf("The gradients don't match the numerical approximation: %v", results)
+	}
+	if model.W.HasGrad() {
+		t.Error("The gradients of the parameters must be zeroed after the check")
+	}
+	if !floats.Same(model.W.Value().Data(), []float64{0.5, -0.6, 0.3, 0.7, -0.4, 0.1}) {
+		t.Error("The values of the parameters must be restored exactly after the check")
+	}
+}
+
+func TestCompare_Panic(t *testing.T) {
+	m := mat.NewVecDense([]float64{0.1, 0.3})
+	func() {
+		defer func() { recover() }()
+		compare([]string{"m"}, []mat.Matrix{m}, [][]float64{{0, 0}}, newConfig(nil), func() float64 {
+			panic("loss failure")
+		})
+	}()
+	if !floats.Same(m.Data(), []float64{0.1, 0.3}) {
+		t.Error("The perturbed value must be restored when the loss panics")
+	}
+}
+
+func TestCompare_Dense32(t *testing.T) {
+	m := mat.NewVecDense32([]float64{0.1, 0.3})
+	results := compare([]string{"m"}, []mat.Matrix{m}, [][]float64{{1, 1}}, newConfig([]Option{Epsilon(1.0e-3)}), func() float64 {
+		return floats.Sum(m.Data())
+	})
+	if !floats.EqualApprox(results[0].Numeric, []float64{1, 1}, 1.0e-3) {
+		t.Errorf("The perturbations of a Dense32 should affect the loss: %v", results[0].Numeric)
+	}
+	if !floats.Same(m.Data(), []float64{float64(float32(0.1)), float64(float32(0.3))}) {
+		t.Error("The perturbed values of a Dense32 must be restored exactly")
+	}
+}
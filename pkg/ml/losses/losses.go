@@ -29,14 +29,17 @@ func MSE(g *ag.Graph, x ag.Node, y ag.Node, reduceMean bool) ag.Node {
 }
 
 // NLL returns the loss of the input x respect to the target y.
-// The target is expected to be a one-hot vector.
+// The input is expected to contain probabilities and the target to be a one-hot vector.
+// Since the logarithm of small probabilities loses precision, CrossEntropy should be used on the
+// unnormalized scores instead of applying NLL to their softmax.
 func NLL(g *ag.Graph, x ag.Node, y ag.Node) ag.Node {
 	return g.Neg(g.ReduceSum(g.Prod(y, g.Log(x))))
 }
 
-// c is the index of the gold class
+// CrossEntropy returns the cross-entropy loss of the unnormalized scores x, computed with a numerically stable
+// log-softmax. c is the index of the gold class
 func CrossEntropy(g *ag.Graph, x ag.Node, c int) ag.Node {
-	return g.Add(g.Neg(g.AtVec(x, c)), g.Log(g.ReduceSum(g.Exp(x))))
+	return g.Neg(g.AtVec(g.LogSoftmax(x), c))
 }
 
 func Perplexity(g *ag.Graph, x ag.Node, c int) ag.Node {
@@ -92,3 +95,22 @@ func SPG(g *ag.Graph, logPropActions []ag.Node, logProbTargets []ag.Node) ag.Nod
 	}
 	return g.Neg(loss)
 }
+
+// CrossEntropyBatch returns the sum of the cross-entropy losses of a mini-batch matrix x, with one example per
+// column (see mat.Batch). cs contains the indices of the gold classes of the examples, in the same order.
+func CrossEntropyBatch(g *ag.Graph, x ag.Node, cs []int, reduceMean bool) ag.Node {
+	if len(cs) != x.Value().Columns() {
+		panic("losses: the number of gold classes doesn't match the batch size")
+	}
+	examples := make([]int, len(cs))
+	for j := range examples {
+		examples[j] = j
+	}
+	// the number of nodes doesn't depend on the batch size, and the -Inf scores of the other classes don't turn
+	// the loss into NaN, as they would when masked
+	loss := g.Sub(g.ReduceSum(g.LogSumExp(x)), g.ReduceSum(g.Gather(x, cs, examples)))
+	if reduceMean {
+		loss = g.DivScalar(loss, g.NewScalar(float64(len(cs))))
+	}
+	return loss
+}
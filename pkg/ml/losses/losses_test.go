@@ -8,6 +8,7 @@ import (
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
 	"gonum.org/v1/gonum/floats"
+	"math"
 	"testing"
 )
 
@@ -61,6 +62,22 @@ func TestCrossEntropyLoss(t *testing.T) {
 	}
 }
 
+func TestCrossEntropyLoss_LargeScores(t *testing.T) {
+	g := ag.NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{1000, 0, -1000}), true)
+	loss := CrossEntropy(g, x, 1)
+
+	if !equalApprox(loss.Value().Scalar(), 1000) {
+		t.Error("The loss doesn't match the expected value")
+	}
+
+	g.Backward(loss)
+
+	if !floats.EqualApprox(x.Grad().Data(), []float64{1.0, -1.0, 0.0}, 1.0e-6) {
+		t.Error("The gradients don't match the expected values")
+	}
+}
+
 func TestZeroOneQuantization(t *testing.T) {
 	g := ag.NewGraph()
 	x := g.NewVariable(mat.NewVecDense([]float64{0.1, 0.2, 1.0, 0.4, -0.8, 0.3}), true)
@@ -135,3 +152,57 @@ func TestMSESeqLoss(t *testing.T) {
 func equalApprox(a, b float64) bool {
 	return floats.EqualWithinAbsOrRel(a, b, 1.0e-06, 1.0e-06)
 }
+
+func TestCrossEntropyBatch(t *testing.T) {
+	examples := []*mat.Dense{
+		mat.NewVecDense([]float64{-500, 0, 0.693147, 1.94591}),
+		mat.NewVecDense([]float64{0.2, -0.1, 0.4, 0.0}),
+		mat.NewVecDense([]float64{1000, 1001, 999, 1000}),
+	}
+	cs := []int{2, 0, 1}
+
+	// the losses of the examples processed separately
+	g := ag.NewGraph()
+	var expected ag.Node
+	var xs []ag.Node
+	for i, e := range examples {
+		x := g.NewVariable(e, true)
+		xs = append(xs, x)
+		expected = g.Add(expected, CrossEntropy(g, x, cs[i]))
+	}
+	g.Backward(expected)
+
+	gb := ag.NewGraph()
+	x := gb.NewVariable(mat.Batch(examples[0], examples[1], examples[2]), true)
+	loss := CrossEntropyBatch(gb, x, cs, false)
+	if !equalApprox(loss.Value().Scalar(), expected.Value().Scalar()) {
+		t.Error("The loss doesn't match the expected value")
+	}
+	gb.Backward(loss)
+	for i, gx := range mat.Unbatch(x.Grad()) {
+		if !floats.EqualApprox(gx.Data(), xs[i].Grad().Data(), 1.0e-6) {
+			t.Errorf("The gradients of the example %d don't match the expected values", i)
+		}
+	}
+
+	// the number of nodes doesn't depend on the batch size
+	small := gb.NewVariable(mat.Batch(examples[0], examples[1]), true)
+	smallLoss := CrossEntropyBatch(gb, small, cs[:2], false)
+	if smallLoss.Id()-small.Id() != loss.Id()-x.Id() {
+		t.Error("The number of nodes of the loss should not depend on the batch size")
+	}
+}
+
+func TestCrossEntropyBatch_NegInf(t *testing.T) {
+	g := ag.NewGraph()
+	x := g.NewVariable(mat.NewDense(3, 2, []float64{
+		0.2, math.Inf(-1),
+		math.Inf(-1), 0.4,
+		0.1, 0.3,
+	}), true)
+	loss := CrossEntropyBatch(g, x, []int{0, 2}, false)
+	g.Backward(loss)
+	if math.IsNaN(loss.Value().Scalar()) || floats.HasNaN(x.Grad().Data()) {
+		t.Error("The -Inf scores of the other classes should not turn the loss into NaN")
+	}
+}
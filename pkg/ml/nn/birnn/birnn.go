@@ -6,10 +6,11 @@ package birnn
 
 import (
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/utils"
 	"io"
 	"log"
-	"sync"
 )
 
 type MergeType int
@@ -93,17 +94,19 @@ func (p *Processor) SetMode(mode nn.ProcessingMode) {
 func (p *Processor) Forward(xs ...ag.Node) []ag.Node {
 	var pos []ag.Node
 	var neg []ag.Node
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
+	if determinism.Enabled() {
 		pos = p.Positive.Forward(xs...)
-	}()
-	go func() {
-		defer wg.Done()
 		neg = p.Negative.Forward(reversed(xs)...)
-	}()
-	wg.Wait()
+	} else {
+		var g utils.Group
+		g.Go(func() {
+			pos = p.Positive.Forward(xs...)
+		})
+		g.Go(func() {
+			neg = p.Negative.Forward(reversed(xs)...)
+		})
+		g.Wait()
+	}
 	out := make([]ag.Node, len(pos))
 	for i := 0; i < len(xs); i++ {
 		out[i] = p.merge(pos[i], neg[len(out)-1-i])
@@ -19,18 +19,30 @@ var (
 )
 
 type Model struct {
-	Convolution    *convolution.Model
-	FinalLayer     *perceptron.Model
-	maxPoolingRows int
-	maxPoolingCols int
+	Convolution *convolution.Model
+	FinalLayer  *perceptron.Model
+	// Pooling is the pooling strategy applied to the feature maps: ag.OpMaxPooling (default), ag.OpMinPooling or
+	// ag.OpAvgPooling over the windows of the model, or ag.OpGlobalMaxPooling or ag.OpGlobalAvgPooling over
+	// each whole feature map. The zero value (ag.OpIdentity) stands for the default.
+	Pooling     ag.OpName
+	poolingRows int
+	poolingCols int
 }
 
+// NewModel returns a new model with max pooling over windows of maxPoolingRows x maxPoolingCols.
 func NewModel(convolution *convolution.Model, maxPoolingRows, maxPoolingCols int, finalLayer *perceptron.Model) *Model {
+	return NewModelWithPooling(convolution, ag.OpMaxPooling, maxPoolingRows, maxPoolingCols, finalLayer)
+}
+
+// NewModelWithPooling returns a new model with the given pooling strategy (see Model.Pooling). The size of the
+// windows is ignored by the global pooling.
+func NewModelWithPooling(convolution *convolution.Model, pooling ag.OpName, poolingRows, poolingCols int, finalLayer *perceptron.Model) *Model {
 	return &Model{
-		Convolution:    convolution,
-		FinalLayer:     finalLayer,
-		maxPoolingRows: maxPoolingRows,
-		maxPoolingCols: maxPoolingCols,
+		Convolution: convolution,
+		FinalLayer:  finalLayer,
+		Pooling:     pooling,
+		poolingRows: poolingRows,
+		poolingCols: poolingCols,
 	}
 }
 
@@ -86,15 +98,28 @@ func (p *Processor) SetMode(mode nn.ProcessingMode) {
 
 func (p *Processor) Forward(xs ...ag.Node) []ag.Node {
 	filters := p.Convolution.Forward(xs...)
-	poolingFilters := p.maxPooling(filters...)
+	poolingFilters := p.pooling(filters...)
 	concatFilters := p.g.Concat(p.vectorize(poolingFilters...)...)
 	return p.Perceptron.Forward(concatFilters)
 }
 
-func (p *Processor) maxPooling(xs ...ag.Node) []ag.Node {
+func (p *Processor) pooling(xs ...ag.Node) []ag.Node {
 	ret := make([]ag.Node, len(xs))
 	for i, x := range xs {
-		ret[i] = p.g.MaxPooling(x, p.model.maxPoolingRows, p.model.maxPoolingCols)
+		switch p.model.Pooling {
+		case ag.OpMaxPooling, ag.OpIdentity:
+			ret[i] = p.g.MaxPooling(x, p.model.poolingRows, p.model.poolingCols)
+		case ag.OpMinPooling:
+			ret[i] = p.g.MinPooling(x, p.model.poolingRows, p.model.poolingCols)
+		case ag.OpAvgPooling:
+			ret[i] = p.g.AvgPooling(x, p.model.poolingRows, p.model.poolingCols)
+		case ag.OpGlobalMaxPooling:
+			ret[i] = p.g.GlobalMaxPooling(x)
+		case ag.OpGlobalAvgPooling:
+			ret[i] = p.g.GlobalAvgPooling(x)
+		default:
+			panic("cnn: invalid pooling strategy")
+		}
 	}
 	return ret
 }
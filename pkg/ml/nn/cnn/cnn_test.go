@@ -0,0 +1,65 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cnn
+
+import (
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/convolution"
+	"github.com/nlpodyssey/spago/pkg/ml/nn/perceptron"
+	"gonum.org/v1/gonum/floats"
+	"testing"
+)
+
+func newTestModel(pooling ag.OpName, in int) *Model {
+	conv := convolution.New(2, 2, 1, 1, 1, 2, ag.OpIdentity)
+	conv.K[0].Value().SetData([]float64{0.5, -0.3, 0.2, 0.8})
+	conv.K[1].Value().SetData([]float64{-0.4, 0.1, 0.7, -0.2})
+	final := perceptron.New(in, 1, ag.OpIdentity)
+	final.W.Value().SetData(make([]float64, in))
+	final.W.Value().AddScalarInPlace(1.0)
+	return NewModelWithPooling(conv, pooling, 2, 2, final)
+}
+
+func TestModel_Pooling(t *testing.T) {
+	input := mat.NewDense(5, 5, []float64{
+		0.1, -0.2, 0.3, 0.4, -0.5,
+		0.6, 0.7, -0.8, 0.9, 0.0,
+		-0.1, 0.2, 0.3, -0.4, 0.5,
+		0.6, -0.7, 0.8, 0.9, -0.1,
+		0.2, 0.3, -0.4, 0.5, 0.6,
+	})
+	// the feature maps of the two kernels are
+	//   0.79 -0.69  0.59  0.53    0.22  0.76 -0.82  0.42
+	//   0.23  0.87 -0.93  0.77   -0.28 -0.28  0.70 -0.74
+	//  -0.55  0.51  1.15 -0.25    0.62 -0.70  0.22  0.86
+	//   0.79 -0.85  0.45  1.06   -0.23  0.65 -0.61 -0.14
+	// whose values sum to 4.47 and 0.65, and the output is the sum of the pooled values
+	for _, test := range []struct {
+		pooling  ag.OpName
+		in       int
+		expected float64
+	}{
+		{ag.OpMaxPooling, 8, 0.87 + 0.77 + 0.79 + 1.15 + 0.76 + 0.70 + 0.65 + 0.86},
+		{ag.OpIdentity, 8, 0.87 + 0.77 + 0.79 + 1.15 + 0.76 + 0.70 + 0.65 + 0.86}, // the zero value is max pooling
+		{ag.OpMinPooling, 8, -0.69 - 0.93 - 0.85 - 0.25 - 0.28 - 0.82 - 0.70 - 0.61},
+		{ag.OpAvgPooling, 8, (4.47 + 0.65) / 4},
+		{ag.OpGlobalMaxPooling, 2, 1.15 + 0.86},
+		{ag.OpGlobalAvgPooling, 2, (4.47 + 0.65) / 16},
+	} {
+		model := newTestModel(test.pooling, test.in)
+		g := ag.NewGraph()
+		x := g.NewVariable(input, true)
+		y := model.NewProc(g).Forward(x)[0]
+
+		if !floats.EqualApprox(y.Value().Data(), []float64{test.expected}, 1.0e-6) {
+			t.Errorf("%s: the output doesn't match the expected values", test.pooling)
+		}
+		g.Backward(y)
+		if !x.HasGrad() {
+			t.Errorf("%s: the gradients should flow into the input", test.pooling)
+		}
+	}
+}
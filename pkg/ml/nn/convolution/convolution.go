@@ -7,10 +7,11 @@ package convolution
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/utils"
 	"io"
 	"log"
-	"sync"
 )
 
 var (
@@ -112,7 +113,7 @@ func (p *Processor) init(opt []interface{}) {
 }
 
 func (p *Processor) Forward(xs ...ag.Node) []ag.Node {
-	if p.ConcurrentOutputChannel && p.model.outputChannels > 1 {
+	if p.ConcurrentOutputChannel && p.model.outputChannels > 1 && !determinism.Enabled() {
 		return p.fwdConcurrent(xs)
 	} else {
 		return p.fwdSerial(xs)
@@ -129,15 +130,14 @@ func (p *Processor) fwdSerial(xs []ag.Node) []ag.Node {
 
 func (p *Processor) fwdConcurrent(xs []ag.Node) []ag.Node {
 	ys := make([]ag.Node, p.model.outputChannels)
-	var wg sync.WaitGroup
-	wg.Add(p.model.outputChannels)
+	var g utils.Group
 	for i := 0; i < p.model.outputChannels; i++ {
-		go func(i int) {
-			defer wg.Done()
+		i := i
+		g.Go(func() {
 			ys[i] = p.forward(xs, i)
-		}(i)
+		})
 	}
-	wg.Wait()
+	g.Wait()
 	return ys
 }
 
@@ -44,7 +44,7 @@ type Processor struct {
 	model            *Model
 	mode             nn.ProcessingMode
 	g                *ag.Graph
-	transitionScores [][]ag.Node
+	transitionScores ag.Node
 }
 
 func (m *Model) NewProc(g *ag.Graph, opt ...interface{}) nn.Processor {
@@ -53,7 +53,7 @@ func (m *Model) NewProc(g *ag.Graph, opt ...interface{}) nn.Processor {
 		mode:             nn.Training,
 		opt:              opt,
 		g:                g,
-		transitionScores: nn.Separate(g, g.NewWrap(m.TransitionScores)),
+		transitionScores: g.NewWrap(m.TransitionScores),
 	}
 	p.init(opt)
 	return p
@@ -87,61 +87,54 @@ func (p *Processor) NegativeLogLoss(emissionScores []ag.Node, target []int) ag.N
 
 func (p *Processor) goldScore(emissionScores []ag.Node, target []int) ag.Node {
 	goldScore := p.g.At(emissionScores[0], target[0], 0)
-	goldScore = p.g.Add(goldScore, p.transitionScores[0][target[0]+1]) // start transition
-	prevIndex := target[0] + 1
 	for i := 1; i < len(target); i++ {
 		goldScore = p.g.Add(goldScore, p.g.AtVec(emissionScores[i], target[i]))
-		goldScore = p.g.Add(goldScore, p.transitionScores[prevIndex][target[i]+1])
-		prevIndex = target[i] + 1
 	}
-	goldScore = p.g.Add(goldScore, p.transitionScores[prevIndex][0]) // end transition
-	return goldScore
+	// the transitions from the start (0) to the first label, between the labels, and from the last label to the end
+	prev := make([]int, len(target)+1)
+	next := make([]int, len(target)+1)
+	for i, label := range target {
+		next[i] = label + 1
+		prev[i+1] = label + 1
+	}
+	return p.g.Add(goldScore, p.g.ReduceSum(p.g.Gather(p.transitionScores, prev, next)))
 }
 
+// totalScore computes the log-sum-exp of the scores of all the sequences of labels with the forward algorithm.
 func (p *Processor) totalScore(predicted []ag.Node) ag.Node {
-	totalVector := p.totalScoreStart(predicted[0])
-	for i := 1; i < len(predicted); i++ {
-		totalVector = p.totalScoreStep(totalVector, nn.SeparateVec(p.g, predicted[i]))
+	size := p.model.TransitionScores.Value().Rows() - 1
+	start, end := p.transitionsFrom(0, size), p.transitionsTo(0, size)
+	// transitions contains the scores of the transitions from the label i (row) to the label j (column)
+	rows, cols := make([]int, 0, size*size), make([]int, 0, size*size)
+	for i := 1; i <= size; i++ {
+		for j := 1; j <= size; j++ {
+			rows, cols = append(rows, i), append(cols, j)
+		}
 	}
-	totalVector = p.totalScoreEnd(totalVector)
-	return p.g.Log(p.g.ReduceSum(p.g.Concat(totalVector...)))
-
-}
+	transitions := p.g.Reshape(p.g.Gather(p.transitionScores, rows, cols), size, size)
 
-func (p *Processor) totalScoreStart(stepVec ag.Node) []ag.Node {
-	size := p.model.TransitionScores.Value().Rows() - 1
-	firstTransitionScores := p.transitionScores[0]
-	scores := make([]ag.Node, size)
-	for i := 0; i < size; i++ {
-		scores[i] = p.g.Add(p.g.AtVec(stepVec, i), firstTransitionScores[i+1])
+	alpha := p.g.Add(predicted[0], start)
+	for i := 1; i < len(predicted); i++ {
+		// the scores of the paths ending in each label, broadcasting alpha over the columns
+		alpha = p.g.Add(p.g.T(p.g.LogSumExp(p.g.Add(alpha, transitions))), predicted[i])
 	}
-	return scores
+	return p.g.LogSumExp(p.g.Add(alpha, end))
 }
 
-func (p *Processor) totalScoreEnd(stepVec []ag.Node) []ag.Node {
-	size := p.model.TransitionScores.Value().Rows() - 1
-	scores := make([]ag.Node, size)
-	for i := 0; i < size; i++ {
-		vecTrans := p.g.Add(stepVec[i], p.transitionScores[i+1][0])
-		scores[i] = p.g.Add(scores[i], p.g.Exp(vecTrans))
+// transitionsFrom returns the scores of the transitions from the given label to each of the labels.
+func (p *Processor) transitionsFrom(label, size int) ag.Node {
+	rows, cols := make([]int, size), make([]int, size)
+	for i := range rows {
+		rows[i], cols[i] = label, i+1
 	}
-	return scores
+	return p.g.Gather(p.transitionScores, rows, cols)
 }
 
-func (p *Processor) totalScoreStep(totalVec []ag.Node, stepVec []ag.Node) []ag.Node {
-	size := p.model.TransitionScores.Value().Rows() - 1
-	scores := make([]ag.Node, size)
-	for i := 0; i < size; i++ {
-		nodei := totalVec[i]
-		transitionScores := p.transitionScores[i+1]
-		for j := 0; j < size; j++ {
-			vecSum := p.g.Add(nodei, stepVec[j])
-			vecTrans := p.g.Add(vecSum, transitionScores[j+1])
-			scores[j] = p.g.Add(scores[j], p.g.Exp(vecTrans))
-		}
-	}
-	for i := 0; i < size; i++ {
-		scores[i] = p.g.Log(scores[i])
+// transitionsTo returns the scores of the transitions from each of the labels to the given label.
+func (p *Processor) transitionsTo(label, size int) ag.Node {
+	rows, cols := make([]int, size), make([]int, size)
+	for i := range rows {
+		rows[i], cols[i] = i+1, label
 	}
-	return scores
+	return p.g.Gather(p.transitionScores, rows, cols)
 }
@@ -97,8 +97,8 @@ func (p *Processor) Forward(xs ...ag.Node) []ag.Node {
 // h = f(wIn (dot) x + bIn)
 // y = t * h + (1 - t) * x
 func (p *Processor) forward(x ag.Node) ag.Node {
-	t := p.g.Sigmoid(nn.Affine(p.g, p.bT, p.wT, x))
-	h := p.g.Invoke(p.model.Activation, nn.Affine(p.g, p.bIn, p.wIn, x))
+	t := p.g.Affine(ag.OpSigmoid, p.bT, p.wT, x)
+	h := p.g.Affine(p.model.Activation, p.bIn, p.wIn, x)
 	y := p.g.Add(p.g.Prod(t, h), p.g.Prod(p.g.ReverseSub(t, p.g.NewScalar(1.0)), x))
 	return y
 }
@@ -61,6 +61,18 @@ func ClearSupport(m Model) {
 	})
 }
 
+// SetDType converts the values of all model's parameters (including sub-params) to the given data type.
+// Using mat.Float32 halves the memory required by the parameters, and the size of the serialized model, at the cost
+// of precision. See ag.DType to set the data type of the inputs of a graph.
+// The support structures of the parameters are cleared, so it should be called before the optimization starts.
+func SetDType(m Model, t mat.DType) {
+	m.ForEachParam(func(param *Param) {
+		if mat.DTypeOf(param.Value()) != t {
+			param.ReplaceValue(mat.Convert(param.Value(), t))
+		}
+	})
+}
+
 // Serialize dumps the model to the writer.
 func Serialize(model Model, w io.Writer) (n int, err error) {
 	model.ForEachParam(func(param *Param) {
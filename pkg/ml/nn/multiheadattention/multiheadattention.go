@@ -73,18 +73,23 @@ type Processor struct {
 	wO    ag.Node
 	g     *ag.Graph
 	Heads []*Head // list of self-attention layers
+	// Mask tells which keys the queries can't attend to, e.g. for causal attention or padded batches (optional)
+	Mask *nn.AttentionMask
+	// Normalization turns the attention scores into probabilities (ag.OpSoftmax by default)
+	Normalization nn.AttentionNormalization
 }
 
 func (m *Model) NewProc(g *ag.Graph, opt ...interface{}) nn.Processor {
 	p := &Processor{
-		model: m,
-		mode:  nn.Training,
-		opt:   opt,
-		wQ:    nn.AttachParamsToGraph(g, m.WQ...),
-		wK:    nn.AttachParamsToGraph(g, m.WK...),
-		wV:    nn.AttachParamsToGraph(g, m.WV...),
-		wO:    g.NewWrap(m.WO),
-		g:     g,
+		model:         m,
+		mode:          nn.Training,
+		opt:           opt,
+		wQ:            nn.AttachParamsToGraph(g, m.WQ...),
+		wK:            nn.AttachParamsToGraph(g, m.WK...),
+		wV:            nn.AttachParamsToGraph(g, m.WV...),
+		wO:            g.NewWrap(m.WO),
+		g:             g,
+		Normalization: nn.AttentionNormalization(ag.OpSoftmax),
 	}
 	p.init(opt)
 	return p
@@ -96,9 +101,17 @@ func (p *Processor) RequiresFullSeq() bool          { return true }
 func (p *Processor) Mode() nn.ProcessingMode        { return p.mode }
 func (p *Processor) SetMode(mode nn.ProcessingMode) { p.mode = mode }
 
+// init accepts the attention mask (*nn.AttentionMask) and the normalization (nn.AttentionNormalization) as options.
 func (p *Processor) init(opt []interface{}) {
-	if len(opt) > 0 {
-		log.Fatal("multiheadattention: invalid init options")
+	for _, t := range opt {
+		switch t := t.(type) {
+		case *nn.AttentionMask:
+			p.Mask = t
+		case nn.AttentionNormalization:
+			p.Normalization = t
+		default:
+			log.Fatal("multiheadattention: invalid init options")
+		}
 	}
 }
 
@@ -114,6 +127,8 @@ func newHead(context []ag.Node, probs []mat.Matrix) *Head {
 	}
 }
 
+// Forward performs the forward step for each input and returns the result.
+// The inputs can also be mini-batch matrices, with one sequence per column (see mat.Batch).
 func (p *Processor) Forward(xs ...ag.Node) []ag.Node {
 	ys := make([]ag.Node, len(xs))
 	p.Heads = p.multiHeadAttention(xs)
@@ -141,7 +156,10 @@ func (p *Processor) multiHeadAttention(xs []ag.Node) []*Head {
 
 func (p *Processor) selfAttention(xs []ag.Node, hi int) (context []ag.Node, probs []mat.Matrix) {
 	qs, ks, vs := p.linearProjection(xs, hi)
-	return nn.ScaledDotProductAttention(p.g, qs, ks, vs, math.Sqrt(float64(p.model.dk)))
+	return nn.ScaledDotProductAttention(p.g, qs, ks, vs, math.Sqrt(float64(p.model.dk)), nn.AttentionOptions{
+		Mask:          p.Mask,
+		Normalization: p.Normalization,
+	})
 }
 
 func (p *Processor) linearProjection(xs []ag.Node, hi int) (qs, ks, vs []ag.Node) {
@@ -0,0 +1,86 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"fmt"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// GradNumericError reports a NaN or Inf value in the gradients accumulated by a parameter (see CheckGradNumerics).
+type GradNumericError struct {
+	// Param is the name of the parameter
+	Param   string
+	Rows    int
+	Columns int
+}
+
+// Error returns the message of the error.
+func (e *GradNumericError) Error() string {
+	return fmt.Sprintf("nn: NaN or Inf in the gradients of the parameter %q (%dx%d)", e.Param, e.Rows, e.Columns)
+}
+
+// Is reports whether the target is ag.ErrNumeric.
+func (e *GradNumericError) Is(target error) bool {
+	return target == ag.ErrNumeric
+}
+
+var (
+	// gradNumericsEnabled is checked first, to avoid locking when the detection is disabled
+	gradNumericsEnabled int32
+	gradNumericsMu      sync.Mutex
+	gradNumericsHandler func(err *GradNumericError)
+)
+
+// CheckGradNumerics enables or disables the detection of NaN and Inf values in the gradients accumulated by all the
+// parameters (see Param.PropagateGrad), including the ones propagated outside of a graph.
+// Each anomaly is reported to the handler once, when the gradients of a parameter become non-finite, and again only
+// after they have been cleared (see Param.ZeroGrad). The handler can be called concurrently; if it is nil, the
+// parameter panics with the *GradNumericError instead (see ag.Try). Use ag.CheckNumerics to find the operator
+// that propagated the gradients.
+func CheckGradNumerics(enable bool, handler func(err *GradNumericError)) {
+	gradNumericsMu.Lock()
+	defer gradNumericsMu.Unlock()
+	gradNumericsHandler = handler
+	if enable {
+		atomic.StoreInt32(&gradNumericsEnabled, 1)
+	} else {
+		atomic.StoreInt32(&gradNumericsEnabled, 0)
+	}
+}
+
+// checkGrad checks the gradients of the parameter, if required, reporting only their transition to non-finite.
+func checkGrad(p *Param) {
+	if atomic.LoadInt32(&gradNumericsEnabled) == 0 {
+		return
+	}
+	gradNumericsMu.Lock()
+	handler := gradNumericsHandler
+	gradNumericsMu.Unlock()
+	p.mu.Lock()
+	if p.nonFinite {
+		p.mu.Unlock()
+		return // already reported
+	}
+	for _, v := range p.grad.Data() {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			p.nonFinite = true
+			break
+		}
+	}
+	finite := !p.nonFinite
+	p.mu.Unlock()
+	if finite {
+		return
+	}
+	err := &GradNumericError{Param: p.name, Rows: p.value.Rows(), Columns: p.value.Columns()}
+	if handler == nil {
+		panic(err)
+	}
+	handler(err)
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"math"
+	"testing"
+)
+
+func TestCheckGradNumerics(t *testing.T) {
+	CheckGradNumerics(true, nil)
+	defer CheckGradNumerics(false, nil)
+
+	p := NewParam(mat.NewVecDense([]float64{0.1, 0.2}))
+	p.SetName("w")
+	p.PropagateGrad(mat.NewVecDense([]float64{1.0, 2.0}))
+
+	err := ag.Try(func() {
+		p.PropagateGrad(mat.NewVecDense([]float64{math.Inf(1), 2.0}))
+	})
+	if !errors.Is(err, ag.ErrNumeric) {
+		t.Fatalf("Expected a numeric error, found %v", err)
+	}
+	if err.Error() != `nn: NaN or Inf in the gradients of the parameter "w" (2x1)` {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+func TestCheckGradNumerics_FirstTransition(t *testing.T) {
+	var errs []*GradNumericError
+	CheckGradNumerics(true, func(err *GradNumericError) {
+		errs = append(errs, err)
+	})
+	defer CheckGradNumerics(false, nil)
+
+	p := NewParam(mat.NewVecDense([]float64{0.1, 0.2}))
+	p.PropagateGrad(mat.NewVecDense([]float64{1.0, 2.0}))
+	p.PropagateGrad(mat.NewVecDense([]float64{math.NaN(), 2.0}))
+	p.PropagateGrad(mat.NewVecDense([]float64{1.0, 2.0}))
+	p.PropagateGrad(mat.NewVecDense([]float64{1.0, math.Inf(-1)}))
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, found %d", len(errs))
+	}
+	p.ZeroGrad()
+	p.PropagateGrad(mat.NewVecDense([]float64{math.NaN(), 2.0}))
+	if len(errs) != 2 {
+		t.Errorf("Expected a new error after ZeroGrad, found %d", len(errs))
+	}
+}
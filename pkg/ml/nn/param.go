@@ -38,8 +38,9 @@ func ToType(s string) ParamsType {
 }
 
 var (
-	_ fn.Operand   = &Param{}
-	_ ag.GradValue = &Param{}
+	_ fn.Operand           = &Param{}
+	_ ag.GradValue         = &Param{}
+	_ fn.RowGradPropagator = &Param{}
 )
 
 type Param struct {
@@ -51,6 +52,8 @@ type Param struct {
 	support      *gd.Support // additional data used by the gradient-descend optimization methods
 	hasGrad      bool
 	requiresGrad bool
+	hooks        ag.Hooks // called on the propagation of the gradients (see RegisterHook)
+	nonFinite    bool     // whether the gradients are not finite, once reported (see CheckGradNumerics)
 }
 
 type ParamOption func(*Param)
@@ -121,20 +124,60 @@ func (r *Param) Grad() mat.Matrix {
 	return r.grad
 }
 
-// PropagateGrad accumulate the gradients
+// PropagateGrad accumulates the gradients, after passing them to the hooks (see RegisterHook).
 func (r *Param) PropagateGrad(grad mat.Matrix) {
 	if !r.requiresGrad {
 		return
 	}
+	if grad = r.hooks.Apply(grad); grad == nil {
+		return
+	}
+	r.accumulateGrad(func(acc mat.Matrix) {
+		acc.AddInPlace(grad)
+	})
+	checkGrad(r)
+}
+
+// PropagateRowGrad accumulates the gradients of the rows at the indices (see fn.RowGradPropagator), e.g. the ones
+// of the embeddings selected from a table, without allocating dense gradients of the dimensions of the parameter.
+// If the parameter has hooks, they are called with dense gradients instead.
+func (r *Param) PropagateRowGrad(indices []int, gx mat.Matrix) {
+	if !r.requiresGrad {
+		return
+	}
+	if r.hooks.Len() > 0 {
+		g := mat.GetEmptyDenseWorkspace(r.value.Dims())
+		defer mat.ReleaseDense(g)
+		fn.AddRowsInPlace(g, indices, gx)
+		r.PropagateGrad(g)
+		return
+	}
+	r.accumulateGrad(func(acc mat.Matrix) {
+		fn.AddRowsInPlace(acc, indices, gx)
+	})
+	checkGrad(r)
+}
+
+// accumulateGrad adds the gradients to the accumulated ones by means of the add function, holding the lock, which is
+// released even if the function panics (e.g. with a *mat.ShapeError recovered by ag.Try).
+func (r *Param) accumulateGrad(add func(acc mat.Matrix)) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if r.grad == nil {
 		r.grad = mat.GetEmptyDenseWorkspace(r.value.Dims()) // this could reduce the number of allocations
 	}
-	r.grad.AddInPlace(grad)
+	add(r.grad)
 	r.hasGrad = true
 }
 
+// RegisterHook registers a function called with the gradients propagated to the parameter, before they are
+// accumulated (see ag.GradHook), e.g. to log their norm or to clip them. The hooks are called in order of registration,
+// on each contribution to the gradients rather than on the accumulated ones, e.g. once per time-step for the
+// parameters of a recurrent network; use the clipping of the optimizer (e.g. gd.ClipGradByNorm) for the accumulated ones.
+func (r *Param) RegisterHook(hook ag.GradHook) {
+	r.hooks.Register(hook)
+}
+
 // HasGrad returns true if there are accumulated gradients.
 func (r *Param) HasGrad() bool {
 	return r.hasGrad
@@ -150,9 +193,10 @@ func (r *Param) ZeroGrad() {
 	if r.grad == nil {
 		return
 	}
-	defer mat.ReleaseDense(r.grad.(*mat.Dense)) // release memory
+	defer mat.ReleaseMatrix(r.grad) // release memory
 	r.grad = nil
 	r.hasGrad = false
+	r.nonFinite = false
 }
 
 // ApplyDelta updates the value of the underlying storage applying the delta.
@@ -0,0 +1,100 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"errors"
+	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"gonum.org/v1/gonum/floats"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParam_RegisterHook(t *testing.T) {
+	p := NewParam(mat.NewVecDense([]float64{0.1, -0.2}))
+	p.RegisterHook(func(grad mat.Matrix) mat.Matrix {
+		clipped := grad.ZerosLike()
+		clipped.Apply(func(_, _ int, v float64) float64 {
+			return math.Max(-1.0, math.Min(1.0, v))
+		}, grad)
+		return clipped
+	})
+	backward := func(k float64) {
+		g := ag.NewGraph()
+		w := g.NewWrap(p)
+		w.RegisterHook(func(grad mat.Matrix) mat.Matrix {
+			return grad.ProdScalar(2.0)
+		})
+		g.Backward(g.ReduceSum(g.ProdScalar(w, g.NewScalar(k))))
+	}
+
+	backward(0.3)
+	if !floats.EqualApprox(p.Grad().Data(), []float64{0.6, 0.6}, 1.0e-6) {
+		t.Error("The gradients don't match the expected values")
+	}
+	p.ZeroGrad()
+	backward(3.0)
+	if !floats.EqualApprox(p.Grad().Data(), []float64{1.0, 1.0}, 1.0e-6) {
+		t.Error("The gradients have not been clipped")
+	}
+}
+
+func TestParam_RegisterHookPartialGradients(t *testing.T) {
+	p := NewParam(mat.NewVecDense([]float64{0.1, -0.2}))
+	var calls int
+	p.RegisterHook(func(grad mat.Matrix) mat.Matrix {
+		calls++
+		return grad
+	})
+	g := ag.NewGraph()
+	w := g.NewWrap(p)
+	g.Backward(g.ReduceSum(g.Add(g.ProdScalar(w, g.NewScalar(2.0)), g.ProdScalar(w, g.NewScalar(3.0)))))
+	if calls != 2 {
+		t.Errorf("The hooks should be called on each contribution, found %d calls", calls)
+	}
+	if !floats.EqualApprox(p.Grad().Data(), []float64{5.0, 5.0}, 1.0e-6) {
+		t.Error("The gradients don't match the expected values")
+	}
+}
+
+func TestParam_PropagateRowGrad(t *testing.T) {
+	p := NewParam(mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}))
+	g := ag.NewGraph()
+	w := g.NewWrap(p)
+	g.Backward(g.ReduceSum(g.Add(
+		g.ReduceSum(g.IndexSelect(w, 2, 0, 2)),
+		g.Gather(w, []int{0}, []int{1}),
+	)))
+	if !floats.EqualApprox(p.Grad().Data(), []float64{1, 2, 0, 0, 2, 2}, 1.0e-6) {
+		t.Errorf("The gradients don't match the expected values, got %v", p.Grad().Data())
+	}
+}
+
+func TestParam_PropagateGradShapeError(t *testing.T) {
+	p := NewParam(mat.NewVecDense([]float64{0.1, -0.2}))
+	err := ag.Try(func() {
+		p.PropagateGrad(mat.NewVecDense([]float64{1.0, 2.0, 3.0}))
+	})
+	if !errors.Is(err, mat.ErrShapeMismatch) {
+		t.Fatalf("Expected a shape mismatch, found %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.PropagateGrad(mat.NewVecDense([]float64{1.0, 2.0}))
+		p.ApplyDelta(p.Grad())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("The parameter should not stay locked after a failed propagation")
+	}
+	if !floats.EqualApprox(p.Value().Data(), []float64{-0.9, -2.2}, 1.0e-6) {
+		t.Error("The values don't match the expected values")
+	}
+}
@@ -7,10 +7,11 @@ package perceptron
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/utils"
 	"io"
 	"log"
-	"sync"
 )
 
 var (
@@ -96,8 +97,10 @@ func (p *Processor) RequiresFullSeq() bool          { return false }
 func (p *Processor) Mode() nn.ProcessingMode        { return p.mode }
 func (p *Processor) SetMode(mode nn.ProcessingMode) { p.mode = mode }
 
+// Forward performs the forward step for each input and returns the result.
+// Each input can be either a vector or a mini-batch matrix, with one example per column (see mat.Batch).
 func (p *Processor) Forward(xs ...ag.Node) []ag.Node {
-	if p.Concurrency && len(xs) > 1 {
+	if p.Concurrency && len(xs) > 1 && !determinism.Enabled() {
 		return p.fwdConcurrent(xs)
 	} else {
 		return p.fwdSerial(xs)
@@ -114,19 +117,18 @@ func (p *Processor) fwdSerial(xs []ag.Node) []ag.Node {
 
 func (p *Processor) fwdConcurrent(xs []ag.Node) []ag.Node {
 	ys := make([]ag.Node, len(xs))
-	var wg sync.WaitGroup
-	wg.Add(len(xs))
+	var g utils.Group
 	for i := range xs {
-		go func(i int) {
-			defer wg.Done()
+		i := i
+		g.Go(func() {
 			ys[i] = p.forward(xs[i])
-		}(i)
+		})
 	}
-	wg.Wait()
+	g.Wait()
 	return ys
 }
 
 // y = f(w (dot) x + b)
 func (p *Processor) forward(x ag.Node) ag.Node {
-	return p.g.Invoke(p.model.Activation, nn.Affine(p.g, p.b, p.w, x))
+	return p.g.Affine(p.model.Activation, p.b, p.w, x)
 }
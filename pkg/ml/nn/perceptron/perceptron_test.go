@@ -5,10 +5,17 @@
 package perceptron
 
 import (
+	"errors"
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/ag/fn"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
 	"github.com/nlpodyssey/spago/pkg/ml/losses"
+	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd"
+	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/sgd"
 	"gonum.org/v1/gonum/floats"
+	"reflect"
 	"testing"
 )
 
@@ -69,3 +76,85 @@ func newTestModel() *Model {
 
 	return model
 }
+
+func TestModel_ForwardFloat32(t *testing.T) {
+	model := newTestModel()
+	nn.SetDType(model, mat.Float32)
+	g := ag.NewGraph()
+
+	// == Forward
+	x := g.NewVariable(mat.NewVecDense([]float64{-0.8, -0.9, -0.9, 1.0}), true)
+	y := model.NewProc(g).Forward(x)[0]
+	if !floats.EqualApprox(y.Value().Data(), []float64{-0.39693, -0.79688, 0.0, 0.70137, -0.18775}, 1.0e-05) {
+		t.Error("The output doesn't match the expected values")
+	}
+
+	// == Backward
+	gold := g.NewVariable(mat.NewVecDense([]float64{0.0, 0.5, -0.4, -0.9, 0.9}), false)
+	loss := losses.MSE(g, y, gold, false)
+	g.Backward(loss)
+	if !floats.EqualApprox(model.B.Grad().Data(), []float64{
+		-0.33439, -0.47334, 0.4, 0.81362, -1.0494,
+	}, 1.0e-05) {
+		t.Error("B doesn't match the expected values")
+	}
+	g.Clear()
+}
+
+var opCustomTanh = ag.RegisterOp("PerceptronTestTanh", func(xs ...fn.Operand) fn.Function {
+	return fn.NewTanh(xs[0])
+})
+
+func TestModel_CustomActivation(t *testing.T) {
+	model := newTestModel()
+	model.SetActivation(opCustomTanh)
+	g := ag.NewGraph()
+	x := g.NewVariable(mat.NewVecDense([]float64{-0.8, -0.9, -0.9, 1.0}), true)
+	y := model.NewProc(g).Forward(x)[0]
+	if !floats.EqualApprox(y.Value().Data(), []float64{-0.39693, -0.79688, 0.0, 0.70137, -0.18775}, 1.0e-05) {
+		t.Error("The output doesn't match the expected values")
+	}
+}
+
+func TestModel_Deterministic(t *testing.T) {
+	defer determinism.Disable()
+	train := func() []float64 {
+		determinism.Enable(42)
+		model := newTestModel()
+		optimizer := gd.NewOptimizer(sgd.New(sgd.NewConfig(0.1, 0.9, true)), gd.ClipGradByNorm(0.5, 2.0))
+		nn.TrackParamsForOptimization(model, optimizer)
+		for step := 0; step < 5; step++ {
+			g := ag.NewGraph()
+			xs := make([]ag.Node, 16)
+			for i := range xs {
+				xs[i] = g.NewVariable(mat.NewVecDense([]float64{0.1 * float64(i), -0.2, 0.3, float64(step)}), false)
+			}
+			var loss ag.Node
+			for _, y := range model.NewProc(g).Forward(xs...) { // concurrent by default
+				loss = g.Add(loss, g.ReduceSum(g.Dropout(y, 0.5)))
+			}
+			g.Backward(loss)
+			optimizer.Optimize()
+		}
+		return append(model.W.Value().Data(), model.B.Value().Data()...)
+	}
+
+	if first, second := train(), train(); !reflect.DeepEqual(first, second) {
+		t.Error("The training is not reproducible")
+	}
+}
+
+func TestProcessor_ForwardConcurrentTry(t *testing.T) {
+	model := newTestModel()
+	g := ag.NewGraph()
+	proc := model.NewProc(g)
+	x1 := g.NewVariable(mat.NewVecDense([]float64{-0.8, -0.9, -0.9, 1.0}), false)
+	x2 := g.NewVariable(mat.NewVecDense([]float64{0.1, 0.2}), false) // wrong size
+
+	err := ag.Try(func() {
+		proc.Forward(x1, x2)
+	})
+	if !errors.Is(err, mat.ErrShapeMismatch) {
+		t.Errorf("Expected a shape mismatch, found %v", err)
+	}
+}
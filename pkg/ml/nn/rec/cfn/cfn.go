@@ -141,8 +141,8 @@ func (p *Processor) LastState() *State {
 func (p *Processor) forward(x ag.Node) (s *State) {
 	s = new(State)
 	yPrev := p.prev()
-	s.InG = p.g.Sigmoid(nn.Affine(p.g, p.bIn, p.wIn, x, p.wInRec, yPrev))
-	s.ForG = p.g.Sigmoid(nn.Affine(p.g, p.bFor, p.wFor, x, p.wForRec, yPrev))
+	s.InG = p.g.Affine(ag.OpSigmoid, p.bIn, p.wIn, x, p.wInRec, yPrev)
+	s.ForG = p.g.Affine(ag.OpSigmoid, p.bFor, p.wFor, x, p.wForRec, yPrev)
 	s.Cand = p.g.Tanh(nn.Linear(p.g, p.wCand, x))
 	s.Y = p.g.Prod(s.InG, s.Cand)
 	if yPrev != nil {
@@ -147,9 +147,9 @@ func (p *Processor) LastState() *State {
 func (p *Processor) forward(x ag.Node) (s *State) {
 	s = new(State)
 	yPrev := p.prev()
-	s.R = p.g.Sigmoid(nn.Affine(p.g, p.bRes, p.wRes, x, p.wResRec, yPrev))
-	s.P = p.g.Sigmoid(nn.Affine(p.g, p.bPart, p.wPart, x, p.wPartRec, yPrev))
-	s.C = p.g.Tanh(nn.Affine(p.g, p.bCand, p.wCand, x, p.wCandRec, tryProd(p.g, yPrev, s.R)))
+	s.R = p.g.Affine(ag.OpSigmoid, p.bRes, p.wRes, x, p.wResRec, yPrev)
+	s.P = p.g.Affine(ag.OpSigmoid, p.bPart, p.wPart, x, p.wPartRec, yPrev)
+	s.C = p.g.Affine(ag.OpTanh, p.bCand, p.wCand, x, p.wCandRec, tryProd(p.g, yPrev, s.R))
 	s.Y = p.g.Prod(s.P, s.C)
 	if yPrev != nil {
 		s.Y = p.g.Add(s.Y, p.g.Prod(p.g.ReverseSub(s.P, p.g.NewScalar(1.0)), yPrev))
@@ -7,7 +7,9 @@ package lstm
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
 	"github.com/nlpodyssey/spago/pkg/ml/nn"
+	"github.com/nlpodyssey/spago/pkg/utils"
 	"io"
 	"log"
 )
@@ -142,6 +144,9 @@ func (p *Processor) RequiresFullSeq() bool          { return false }
 func (p *Processor) Mode() nn.ProcessingMode        { return p.mode }
 func (p *Processor) SetMode(mode nn.ProcessingMode) { p.mode = mode }
 
+// Forward performs the forward step for each input and returns the result.
+// The inputs can also be mini-batch matrices, with one sequence per column (see mat.Batch), provided that all of
+// them have the same batch size, as well as the initial hidden state, if any.
 func (p *Processor) Forward(xs ...ag.Node) []ag.Node {
 	ys := make([]ag.Node, len(xs))
 	for i, x := range xs {
@@ -161,7 +166,7 @@ func (p *Processor) LastState() *State {
 }
 
 func (p *Processor) forward(x ag.Node) *State {
-	if p.GateConcurrency {
+	if p.GateConcurrency && !determinism.Enabled() {
 		return p.fwdConcurrent(x)
 	} else {
 		return p.fwdSerial(x)
@@ -178,10 +183,10 @@ func (p *Processor) forward(x ag.Node) *State {
 func (p *Processor) fwdSerial(x ag.Node) (s *State) {
 	s = new(State)
 	yPrev, cellPrev := p.prev()
-	s.InG = p.g.Sigmoid(nn.Affine(p.g, p.bIn, p.wIn, x, p.wInRec, yPrev))
-	s.OutG = p.g.Sigmoid(nn.Affine(p.g, p.bOut, p.wOut, x, p.wOutRec, yPrev))
-	s.ForG = p.g.Sigmoid(nn.Affine(p.g, p.bFor, p.wFor, x, p.wForRec, yPrev))
-	s.Cand = p.g.Tanh(nn.Affine(p.g, p.bCand, p.wCand, x, p.wCandRec, yPrev))
+	s.InG = p.g.Affine(ag.OpSigmoid, p.bIn, p.wIn, x, p.wInRec, yPrev)
+	s.OutG = p.g.Affine(ag.OpSigmoid, p.bOut, p.wOut, x, p.wOutRec, yPrev)
+	s.ForG = p.g.Affine(ag.OpSigmoid, p.bFor, p.wFor, x, p.wForRec, yPrev)
+	s.Cand = p.g.Affine(ag.OpTanh, p.bCand, p.wCand, x, p.wCandRec, yPrev)
 	if cellPrev != nil {
 		s.Cell = p.g.Add(p.g.Prod(s.InG, s.Cand), p.g.Prod(s.ForG, cellPrev))
 	} else {
@@ -202,38 +207,20 @@ func (p *Processor) fwdConcurrent(x ag.Node) (s *State) {
 	s = new(State)
 	yPrev, cellPrev := p.prev()
 
-	cInG := make(chan ag.Node)
-	go func() {
-		cInG <- p.g.Sigmoid(nn.Affine(p.g, p.bIn, p.wIn, x, p.wInRec, yPrev))
-	}()
-
-	cOutG := make(chan ag.Node)
-	go func() {
-		cOutG <- p.g.Sigmoid(nn.Affine(p.g, p.bOut, p.wOut, x, p.wOutRec, yPrev))
-	}()
-
-	cForG := make(chan ag.Node)
-	go func() {
-		cForG <- p.g.Sigmoid(nn.Affine(p.g, p.bFor, p.wFor, x, p.wForRec, yPrev))
-	}()
-
-	cCand := make(chan ag.Node)
-	go func() {
-		cCand <- p.g.Tanh(nn.Affine(p.g, p.bCand, p.wCand, x, p.wCandRec, yPrev))
-	}()
-
-	for i := 0; i < 4; i++ {
-		select {
-		case msg := <-cInG:
-			s.InG = msg
-		case msg := <-cOutG:
-			s.OutG = msg
-		case msg := <-cForG:
-			s.ForG = msg
-		case msg := <-cCand:
-			s.Cand = msg
-		}
-	}
+	var g utils.Group
+	g.Go(func() {
+		s.InG = p.g.Affine(ag.OpSigmoid, p.bIn, p.wIn, x, p.wInRec, yPrev)
+	})
+	g.Go(func() {
+		s.OutG = p.g.Affine(ag.OpSigmoid, p.bOut, p.wOut, x, p.wOutRec, yPrev)
+	})
+	g.Go(func() {
+		s.ForG = p.g.Affine(ag.OpSigmoid, p.bFor, p.wFor, x, p.wForRec, yPrev)
+	})
+	g.Go(func() {
+		s.Cand = p.g.Affine(ag.OpTanh, p.bCand, p.wCand, x, p.wCandRec, yPrev)
+	})
+	g.Wait()
 
 	if cellPrev != nil {
 		s.Cell = p.g.Add(p.g.Prod(s.InG, s.Cand), p.g.Prod(s.ForG, cellPrev))
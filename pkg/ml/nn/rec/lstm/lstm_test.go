@@ -588,3 +588,30 @@ func newTestModel2() *Model {
 	model.BCand.Value().SetData([]float64{0.4, 0.3})
 	return model
 }
+
+func TestModel_ForwardBatch(t *testing.T) {
+	model := newTestModel()
+	xs := []mat.Matrix{
+		mat.NewVecDense([]float64{-0.8, -0.9, -0.9, 1.0}),
+		mat.NewVecDense([]float64{0.8, -0.3, 0.5, 0.3}),
+	}
+
+	// the outputs of each sequence processed separately
+	expected := make([][]float64, len(xs))
+	for i, x := range xs {
+		g := ag.NewGraph()
+		proc := model.NewProc(g)
+		y := proc.Forward(g.NewVariable(x, false), g.NewVariable(x, false))[1]
+		expected[i] = y.Value().Data()
+	}
+
+	g := ag.NewGraph()
+	proc := model.NewProc(g)
+	batch := g.NewVariable(mat.Batch(xs...), false)
+	ys := proc.Forward(batch, batch)
+	for i, y := range mat.Unbatch(ys[1].Value()) {
+		if !floats.EqualApprox(y.Data(), expected[i], 1.0e-6) {
+			t.Errorf("The output of the sequence %d doesn't match the expected values", i)
+		}
+	}
+}
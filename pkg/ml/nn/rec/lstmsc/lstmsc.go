@@ -217,10 +217,10 @@ func (p *Processor) forward(x ag.Node) (s *State) {
 		}
 	}
 
-	s.InG = p.g.Sigmoid(nn.Affine(p.g, p.bIn, p.wIn, x, p.wInRec, yPrevNew))
-	s.OutG = p.g.Sigmoid(nn.Affine(p.g, p.bOut, p.wOut, x, p.wOutRec, yPrevNew))
-	s.ForG = p.g.Sigmoid(nn.Affine(p.g, p.bFor, p.wFor, x, p.wForRec, yPrevNew))
-	s.Cand = p.g.Tanh(nn.Affine(p.g, p.bCand, p.wCand, x, p.wCandRec, yPrevNew))
+	s.InG = p.g.Affine(ag.OpSigmoid, p.bIn, p.wIn, x, p.wInRec, yPrevNew)
+	s.OutG = p.g.Affine(ag.OpSigmoid, p.bOut, p.wOut, x, p.wOutRec, yPrevNew)
+	s.ForG = p.g.Affine(ag.OpSigmoid, p.bFor, p.wFor, x, p.wForRec, yPrevNew)
+	s.Cand = p.g.Affine(ag.OpTanh, p.bCand, p.wCand, x, p.wCandRec, yPrevNew)
 	if cellPrevNew != nil {
 		s.Cell = p.g.Add(p.g.Prod(s.InG, s.Cand), p.g.Prod(s.ForG, cellPrevNew))
 	} else {
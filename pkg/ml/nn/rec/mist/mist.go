@@ -147,8 +147,8 @@ func (p *Processor) forward(x ag.Node) (s *State) {
 	s = new(State)
 	yPrev := p.yPrev()
 	a := p.g.Softmax(nn.Affine(p.g, p.ba, p.wax, x, p.wah, yPrev))
-	r := p.g.Sigmoid(nn.Affine(p.g, p.br, p.wrx, x, p.wrh, yPrev)) // TODO: evaluate whether to calculate this only in case of previous states
-	s.Y = p.g.Tanh(nn.Affine(p.g, p.b, p.wx, x, p.wh, p.tryProd(r, p.weightHistory(a))))
+	r := p.g.Affine(ag.OpSigmoid, p.br, p.wrx, x, p.wrh, yPrev) // TODO: evaluate whether to calculate this only in case of previous states
+	s.Y = p.g.Affine(ag.OpTanh, p.b, p.wx, x, p.wh, p.tryProd(r, p.weightHistory(a)))
 	return
 }
 
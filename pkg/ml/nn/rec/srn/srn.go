@@ -116,7 +116,7 @@ func (p *Processor) LastState() *State {
 func (p *Processor) forward(x ag.Node) (s *State) {
 	s = new(State)
 	yPrev := p.prev()
-	s.Y = p.g.Tanh(nn.Affine(p.g, p.b, p.w, x, p.wRec, yPrev))
+	s.Y = p.g.Affine(ag.OpTanh, p.b, p.w, x, p.wRec, yPrev)
 	return
 }
 
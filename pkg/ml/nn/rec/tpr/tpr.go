@@ -149,8 +149,8 @@ func (p *Processor) forward(x ag.Node) (st *State) {
 		yPrev = sPrev.Y
 	}
 	st = new(State)
-	st.AR = p.g.Sigmoid(nn.Affine(p.g, p.bR, p.wInR, x, p.wRecR, yPrev))
-	st.AS = p.g.Sigmoid(nn.Affine(p.g, p.bS, p.wInS, x, p.wRecS, yPrev))
+	st.AR = p.g.Affine(ag.OpSigmoid, p.bR, p.wInR, x, p.wRecR, yPrev)
+	st.AS = p.g.Affine(ag.OpSigmoid, p.bS, p.wInS, x, p.wRecS, yPrev)
 	st.R = nn.Linear(p.g, p.r, st.AR)
 	st.S = nn.Linear(p.g, p.s, st.AS)
 	b := nn.Linear(p.g, st.S, p.g.T(st.R))
@@ -7,8 +7,9 @@ package nn
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
 	"github.com/nlpodyssey/spago/pkg/ml/ag"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
+	"github.com/nlpodyssey/spago/pkg/utils"
 	"math"
-	"sync"
 )
 
 // Linear performs a linear transformation of the type Wx.
@@ -21,24 +22,12 @@ func Linear(g *ag.Graph, w, x ag.Node) ag.Node {
 // The remaining nodes of the form "Wx" are multiplied together in pairs, then added.
 // The pairs except the first whose "x" is nil are not considered.
 // y = b + W1x1 + W2x2 + ... + WnXn
+// The transformation is computed by a single fused operator (see ag.Graph.Affine).
 func Affine(g *ag.Graph, xs ...ag.Node) ag.Node {
 	if len(xs)%2 == 0 {
 		panic("nn: the number of arguments of the affine transformation should be odd")
 	}
-
-	// Optimize bounds checks
-	x := xs[2]
-	w := xs[1]
-	y := g.Add(xs[0], Linear(g, w, x)) // b + Wx
-
-	for i := 3; i < len(xs)-1; i += 2 {
-		w := xs[i]
-		x := xs[i+1]
-		if x != nil {
-			y = g.Add(y, Linear(g, w, x))
-		}
-	}
-	return y
+	return g.Affine(ag.OpIdentity, xs...)
 }
 
 // BiLinear performs a bilinear transformation of the type (x_1 W x_2)
@@ -75,18 +64,114 @@ func Conv2D(g *ag.Graph, w, x ag.Node, xStride, yStride int) ag.Node {
 	return g.Reshape(g.Concat(outList...), dimx, dimy)
 }
 
+// AttentionMask tells which keys the queries can't attend to (see ScaledDotProductAttention).
+type AttentionMask struct {
+	// Causal prevents each query from attending to the keys that follow it (i.e. the query i attends to the keys
+	// 0..i only), as in autoregressive decoders. It requires as many queries as keys.
+	Causal bool
+	// Keys, if not nil, masks the keys whose value is not zero (e.g. the padding) for all the queries. It has one row
+	// for each key and either one column for each example of the mini-batch or a single column for all of them
+	// (see NewPaddingMask).
+	Keys mat.Matrix
+}
+
+// maskedScore is the score of the masked keys, whose attention is zero after the normalization. It is finite so that
+// it is not reported as an anomaly (see ag.CheckNumerics).
+const maskedScore = -1.0e9
+
+// NewPaddingMask returns the mask of the keys of a mini-batch of sequences padded to the same length, given the
+// lengths of the sequences: the key j of the example b is masked if j >= lengths[b] (see AttentionMask.Keys).
+func NewPaddingMask(keys int, lengths ...int) mat.Matrix {
+	mask := mat.NewEmptyDense(keys, len(lengths))
+	for b, length := range lengths {
+		for j := length; j < keys; j++ {
+			mask.Set(j, b, 1.0)
+		}
+	}
+	return mask
+}
+
+// at returns the mask of the scores of the query i, with one row for each key and the given number of columns,
+// or nil if no key is masked.
+func (m *AttentionMask) at(i, keys, cols int) mat.Matrix {
+	if m == nil || !m.Causal && m.Keys == nil {
+		return nil
+	}
+	if m.Keys != nil && (m.Keys.Rows() != keys || m.Keys.Columns() != 1 && m.Keys.Columns() != cols) {
+		panic(&mat.ShapeError{Op: "AttentionMask", Shapes: [][]int{{m.Keys.Rows(), m.Keys.Columns()}, {keys, cols}}})
+	}
+	mask := mat.NewEmptyDense(keys, cols)
+	masked := false
+	for j := 0; j < keys; j++ {
+		for b := 0; b < cols; b++ {
+			if m.Causal && j > i || m.Keys != nil && m.Keys.At(j, b%m.Keys.Columns()) != 0 {
+				mask.Set(j, b, 1.0)
+				masked = true
+			}
+		}
+	}
+	if !masked {
+		return nil
+	}
+	return mask
+}
+
+// AttentionNormalization selects the operator turning the scores of each query into the attention probabilities,
+// in place of the softmax (e.g. ag.OpSparsemax or ag.OpEntmax15). The operator must be applied to each column
+// independently and give zero probability to the masked scores.
+type AttentionNormalization ag.OpName
+
+// AttentionOptions are the options of ScaledDotProductAttention.
+type AttentionOptions struct {
+	// Mask tells which keys the queries can't attend to, e.g. for causal attention or padded batches (optional).
+	Mask *AttentionMask
+	// Normalization turns the scores into the attention probabilities (the softmax if zero).
+	Normalization AttentionNormalization
+}
+
+// attentionOptions returns the optional mask, if any, and the function computing the attention probabilities.
+func attentionOptions(g *ag.Graph, opts []AttentionOptions) (mask *AttentionMask, normalize func(x ag.Node) ag.Node) {
+	if len(opts) > 1 {
+		panic("nn: too many attention options")
+	}
+	normalize = g.Softmax
+	if len(opts) == 0 {
+		return
+	}
+	if op := ag.OpName(opts[0].Normalization); op != ag.OpIdentity {
+		normalize = func(x ag.Node) ag.Node { return g.Invoke(op, x) }
+	}
+	return opts[0].Mask, normalize
+}
+
+// maskScores sets the scores of the masked keys of the query i to maskedScore.
+func maskScores(g *ag.Graph, scores ag.Node, mask *AttentionMask, i int) ag.Node {
+	if m := mask.at(i, scores.Value().Rows(), scores.Value().Columns()); m != nil {
+		return g.MaskedFill(scores, m, maskedScore)
+	}
+	return scores
+}
+
 // ScaledDotProductAttention is a self-attention mechanism relating different positions of a single sequence in order to compute a representation of the same sequence.
 // This method requires that the query, the key and the value vectors have already been obtained from the input sequence.
 // The scaled factor is the square root of the dimension of the key vectors.
-func ScaledDotProductAttention(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor float64) (context []ag.Node, probs []mat.Matrix) {
+// The queries, the keys and the values can also be mini-batch matrices, with one example per column (see mat.Batch);
+// in this case the attention probabilities are matrices too, with one column per example.
+// The optional AttentionOptions tell which keys the queries can't attend to (e.g. for causal attention or padded
+// batches, where each query must be able to attend to at least one key) and which operator replaces the softmax.
+func ScaledDotProductAttention(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor float64, opts ...AttentionOptions) (context []ag.Node, probs []mat.Matrix) {
+	m, normalize := attentionOptions(g, opts)
+	if isBatch(qs) {
+		return scaledDotProductAttentionBatch(g, qs, ks, vs, scaledFactor, m, normalize, false)
+	}
 	context = make([]ag.Node, len(qs))
 	probs = make([]mat.Matrix, len(qs))
 	keys := g.Stack(ks...)
 	values := g.T(g.Stack(vs...))
 	divTerm := g.NewScalar(scaledFactor)
 	for i, q := range qs {
-		attScores := g.DivScalar(g.Mul(keys, q), divTerm)
-		attProbs := g.Softmax(attScores)
+		attScores := maskScores(g, g.DivScalar(g.Mul(keys, q), divTerm), m, i)
+		attProbs := normalize(attScores)
 		context[i] = g.Mul(values, attProbs)
 		probs[i] = attProbs.Value()
 	}
@@ -94,27 +179,79 @@ func ScaledDotProductAttention(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor f
 }
 
 // ScaledDotProductAttentionConcurrent does the same thing as ScaledDotProductAttention but processes input concurrently.
-func ScaledDotProductAttentionConcurrent(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor float64) (context []ag.Node, probs []mat.Matrix) {
+// In deterministic mode the input is processed serially (see determinism.Enable).
+func ScaledDotProductAttentionConcurrent(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor float64, opts ...AttentionOptions) (context []ag.Node, probs []mat.Matrix) {
+	if determinism.Enabled() {
+		return ScaledDotProductAttention(g, qs, ks, vs, scaledFactor, opts...)
+	}
+	m, normalize := attentionOptions(g, opts)
+	if isBatch(qs) {
+		return scaledDotProductAttentionBatch(g, qs, ks, vs, scaledFactor, m, normalize, true)
+	}
 	context = make([]ag.Node, len(qs))
 	probs = make([]mat.Matrix, len(qs))
 	keys := g.Stack(ks...)
 	values := g.T(g.Stack(vs...))
 	divTerm := g.NewScalar(scaledFactor)
-	var wg sync.WaitGroup
-	wg.Add(len(qs))
+	var group utils.Group
 	for i, q := range qs {
-		go func(i int, q ag.Node) {
-			defer wg.Done()
-			attScores := g.DivScalar(g.Mul(keys, q), divTerm)
-			attProbs := g.Softmax(attScores)
+		i, q := i, q
+		group.Go(func() {
+			attScores := maskScores(g, g.DivScalar(g.Mul(keys, q), divTerm), m, i)
+			attProbs := normalize(attScores)
 			context[i] = g.Mul(values, attProbs)
 			probs[i] = attProbs.Value()
-		}(i, q)
+		})
+	}
+	group.Wait()
+	return
+}
+
+// scaledDotProductAttentionBatch performs the scaled dot-product attention over mini-batch matrices.
+// The scores of each query are computed column-wise, so that the number of nodes doesn't depend on the batch size.
+func scaledDotProductAttentionBatch(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor float64, mask *AttentionMask, normalize func(x ag.Node) ag.Node, concurrent bool) (context []ag.Node, probs []mat.Matrix) {
+	context = make([]ag.Node, len(qs))
+	probs = make([]mat.Matrix, len(qs))
+	divTerm := g.NewScalar(scaledFactor)
+	sumRows := g.NewVariable(mat.NewInitDense(1, ks[0].Value().Rows(), 1.0), false) // 1 x dk
+	attend := func(i int, q ag.Node) {
+		if len(ks) == 1 { // a single key always gets the whole attention
+			context[i] = g.Identity(vs[0])
+			probs[i] = mat.NewInitDense(1, q.Value().Columns(), 1.0)
+			return
+		}
+		scores := make([]ag.Node, len(ks))
+		for j, k := range ks {
+			scores[j] = g.Mul(sumRows, g.Prod(q, k)) // column-wise dot product
+		}
+		attProbs := normalize(maskScores(g, g.DivScalar(g.Concat(scores...), divTerm), mask, i))
+		for j, v := range vs {
+			context[i] = g.Add(context[i], g.Prod(v, g.RowView(attProbs, j))) // broadcast over the rows
+		}
+		probs[i] = attProbs.Value()
 	}
-	wg.Wait()
+	if !concurrent {
+		for i, q := range qs {
+			attend(i, q)
+		}
+		return
+	}
+	var group utils.Group
+	for i, q := range qs {
+		i, q := i, q
+		group.Go(func() {
+			attend(i, q)
+		})
+	}
+	group.Wait()
 	return
 }
 
+// isBatch returns whether the nodes contain mini-batch matrices, with one example per column.
+func isBatch(xs []ag.Node) bool {
+	return len(xs) > 0 && xs[0].Value().Columns() > 1
+}
+
 // Separate returns a matrix of Node(s) represented as a slice of slice containing the elements extracted from the input.
 // The dimensions of the resulting matrix are the same of the input.
 func Separate(g *ag.Graph, x ag.Node) [][]ag.Node {
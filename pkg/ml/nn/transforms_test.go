@@ -223,3 +223,182 @@ func TestScaledDotProductAttention2(t *testing.T) {
 		t.Error("vs[2] doesn't match the expected values")
 	}
 }
+
+func TestScaledDotProductAttentionBatch(t *testing.T) {
+	examples := [][]mat.Matrix{
+		{
+			mat.NewVecDense([]float64{0.1, -0.3, 0.5}),
+			mat.NewVecDense([]float64{0.7, 0.2, -0.4}),
+			mat.NewVecDense([]float64{-0.6, 0.8, 0.3}),
+		},
+		{
+			mat.NewVecDense([]float64{0.9, 0.1, -0.2}),
+			mat.NewVecDense([]float64{-0.5, 0.4, 0.6}),
+			mat.NewVecDense([]float64{0.2, -0.7, 0.8}),
+		},
+	}
+	scaledFactor := math.Sqrt(3)
+
+	// the results of each example processed separately
+	expected := make([][]float64, len(examples))
+	for b, xs := range examples {
+		g := ag.NewGraph()
+		nodes := make([]ag.Node, len(xs))
+		for i, x := range xs {
+			nodes[i] = g.NewVariable(x, false)
+		}
+		context, _ := ScaledDotProductAttention(g, nodes, nodes, nodes, scaledFactor)
+		for _, c := range context {
+			expected[b] = append(expected[b], c.Value().Data()...)
+		}
+	}
+
+	g := ag.NewGraph()
+	batch := make([]ag.Node, len(examples[0]))
+	for i := range batch {
+		batch[i] = g.NewVariable(mat.Batch(examples[0][i], examples[1][i]), true)
+	}
+	context, probs := ScaledDotProductAttention(g, batch, batch, batch, scaledFactor)
+
+	for b := range examples {
+		var actual []float64
+		for _, c := range context {
+			actual = append(actual, mat.Unbatch(c.Value())[b].Data()...)
+		}
+		if !floats.EqualApprox(actual, expected[b], 1.0e-6) {
+			t.Errorf("The context of the example %d doesn't match the expected values", b)
+		}
+	}
+	if r, c := probs[0].Dims(); r != 3 || c != 2 {
+		t.Error("The dimensions of the attention probabilities don't match the expected values")
+	}
+}
+
+func TestScaledDotProductAttention_CausalMask(t *testing.T) {
+	for _, attention := range []func(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor float64, opts ...AttentionOptions) ([]ag.Node, []mat.Matrix){
+		ScaledDotProductAttention,
+		ScaledDotProductAttentionConcurrent,
+	} {
+		g := ag.NewGraph()
+		qs := []ag.Node{
+			g.NewVariable(mat.NewVecDense([]float64{0.22, 0.3}), true),
+			g.NewVariable(mat.NewVecDense([]float64{-0.17, 0.24}), true),
+			g.NewVariable(mat.NewVecDense([]float64{-0.15, 0.23}), true),
+		}
+		ks := []ag.Node{
+			g.NewVariable(mat.NewVecDense([]float64{1.66, 0.12}), true),
+			g.NewVariable(mat.NewVecDense([]float64{0.88, -0.02}), true),
+			g.NewVariable(mat.NewVecDense([]float64{-0.3, -0.46}), true),
+		}
+		vs := []ag.Node{
+			g.NewVariable(mat.NewVecDense([]float64{0.83, 0.7, -0.25, -0.58}), true),
+			g.NewVariable(mat.NewVecDense([]float64{0.0, 0.2, 0.57, -2.08}), true),
+			g.NewVariable(mat.NewVecDense([]float64{-0.07, 0.0, 0.29, 0.5}), true),
+		}
+
+		context, probs := attention(g, qs, ks, vs, math.Sqrt(2), AttentionOptions{Mask: &AttentionMask{Causal: true}})
+
+		if !floats.EqualApprox(probs[0].Data(), []float64{1.0, 0.0, 0.0}, 1.0e-6) {
+			t.Error("Probs[0] doesn't match the expected values")
+		}
+		if !floats.EqualApprox(probs[1].Data(), []float64{0.482507, 0.517493, 0.0}, 1.0e-6) {
+			t.Error("Probs[1] doesn't match the expected values")
+		}
+		if !floats.EqualApprox(probs[2].Data(), []float64{0.314262, 0.333682, 0.352055}, 1.0e-6) {
+			t.Error("Probs[2] doesn't match the expected values")
+		}
+		if !floats.EqualApprox(context[0].Value().Data(), vs[0].Value().Data(), 1.0e-6) {
+			t.Error("Context[0] doesn't match the expected values")
+		}
+
+		g.Backward(context[0], mat.NewVecDense([]float64{0.7, -0.3, -0.7, -0.5}))
+		if ks[1].HasGrad() && floats.Norm(ks[1].Grad().Data(), 2) > 1.0e-12 {
+			t.Error("The gradients should not flow into the masked keys")
+		}
+	}
+}
+
+func TestScaledDotProductAttention_PaddingMask(t *testing.T) {
+	examples := [][]mat.Matrix{
+		{
+			mat.NewVecDense([]float64{0.1, -0.3, 0.5}),
+			mat.NewVecDense([]float64{0.7, 0.2, -0.4}),
+			mat.NewVecDense([]float64{-0.6, 0.8, 0.3}),
+		},
+		{
+			mat.NewVecDense([]float64{0.9, 0.1, -0.2}),
+			mat.NewVecDense([]float64{-0.5, 0.4, 0.6}),
+			mat.NewVecDense([]float64{0.0, 0.0, 0.0}), // padding
+		},
+	}
+	lengths := []int{3, 2}
+	scaledFactor := math.Sqrt(3)
+
+	// the results of each example processed separately, without the padding keys
+	expected := make([][]float64, len(examples))
+	for b, xs := range examples {
+		g := ag.NewGraph()
+		nodes := make([]ag.Node, len(xs))
+		for i, x := range xs {
+			nodes[i] = g.NewVariable(x, false)
+		}
+		context, _ := ScaledDotProductAttention(g, nodes, nodes[:lengths[b]], nodes[:lengths[b]], scaledFactor)
+		for _, c := range context {
+			expected[b] = append(expected[b], c.Value().Data()...)
+		}
+	}
+
+	g := ag.NewGraph()
+	batch := make([]ag.Node, len(examples[0]))
+	for i := range batch {
+		batch[i] = g.NewVariable(mat.Batch(examples[0][i], examples[1][i]), true)
+	}
+	mask := &AttentionMask{Keys: NewPaddingMask(3, lengths...)}
+	context, _ := ScaledDotProductAttention(g, batch, batch, batch, scaledFactor, AttentionOptions{Mask: mask})
+
+	for b := range examples {
+		var actual []float64
+		for _, c := range context {
+			actual = append(actual, mat.Unbatch(c.Value())[b].Data()...)
+		}
+		if !floats.EqualApprox(actual, expected[b], 1.0e-6) {
+			t.Errorf("The context of the example %d doesn't match the expected values", b)
+		}
+	}
+}
+
+func TestScaledDotProductAttention_Sparsemax(t *testing.T) {
+	for _, attention := range []func(g *ag.Graph, qs, ks, vs []ag.Node, scaledFactor float64, opts ...AttentionOptions) ([]ag.Node, []mat.Matrix){
+		ScaledDotProductAttention,
+		ScaledDotProductAttentionConcurrent,
+	} {
+		g := ag.NewGraph()
+		qs := []ag.Node{
+			g.NewVariable(mat.NewVecDense([]float64{-0.15, 0.23}), true),
+		}
+		ks := []ag.Node{
+			g.NewVariable(mat.NewVecDense([]float64{1.66, 0.12}), true),
+			g.NewVariable(mat.NewVecDense([]float64{0.88, -0.02}), true),
+			g.NewVariable(mat.NewVecDense([]float64{-0.3, -0.46}), true),
+		}
+		vs := []ag.Node{
+			g.NewVariable(mat.NewVecDense([]float64{0.83, 0.7, -0.25, -0.58}), true),
+			g.NewVariable(mat.NewVecDense([]float64{0.0, 0.2, 0.57, -2.08}), true),
+			g.NewVariable(mat.NewVecDense([]float64{-0.07, 0.0, 0.29, 0.5}), true),
+		}
+
+		context, probs := attention(g, qs, ks, vs, 0.1, AttentionOptions{Normalization: AttentionNormalization(ag.OpSparsemax)})
+
+		if !floats.EqualApprox(probs[0].Data(), []float64{0.0, 0.121, 0.879}, 1.0e-6) {
+			t.Error("Probs[0] doesn't match the expected values")
+		}
+
+		g.Backward(context[0], mat.NewVecDense([]float64{0.7, -0.3, -0.7, -0.5}))
+		if ks[0].HasGrad() && floats.Norm(ks[0].Grad().Data(), 2) > 1.0e-12 {
+			t.Error("The gradients should not flow into the keys out of the support")
+		}
+		if !ks[1].HasGrad() || floats.Norm(ks[1].Grad().Data(), 2) < 1.0e-12 {
+			t.Error("The gradients should flow into the keys in the support")
+		}
+	}
+}
@@ -6,7 +6,7 @@ package de
 
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
-	"golang.org/x/exp/rand"
+	"github.com/nlpodyssey/spago/pkg/mat/rand"
 	"math"
 )
 
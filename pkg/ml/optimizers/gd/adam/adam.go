@@ -94,9 +94,9 @@ func (o *Adam) calcDelta(grads mat.Matrix, supp []mat.Matrix) mat.Matrix {
 	updateV(grads, supp, o.Beta1)
 	updateM(grads, supp, o.Beta2)
 	buf := supp[m].Sqrt().AddScalarInPlace(o.Epsilon)
-	defer mat.ReleaseDense(buf.(*mat.Dense))
+	defer mat.ReleaseMatrix(buf)
 	suppDiv := supp[v].Div(buf)
-	defer mat.ReleaseDense(suppDiv.(*mat.Dense))
+	defer mat.ReleaseMatrix(suppDiv)
 	supp[buf3].ProdMatrixScalarInPlace(suppDiv, o.Alpha)
 	return supp[buf3]
 }
@@ -112,7 +112,7 @@ func updateV(grads mat.Matrix, supp []mat.Matrix, beta1 float64) {
 func updateM(grads mat.Matrix, supp []mat.Matrix, beta2 float64) {
 	supp[m].ProdScalarInPlace(beta2)
 	sqGrad := grads.Prod(grads)
-	defer mat.ReleaseDense(sqGrad.(*mat.Dense))
+	defer mat.ReleaseMatrix(sqGrad)
 	supp[buf2].ProdMatrixScalarInPlace(sqGrad, 1.0-beta2)
 	supp[m].AddInPlace(supp[buf2])
 }
@@ -6,7 +6,9 @@ package gd
 
 import (
 	"github.com/nlpodyssey/spago/pkg/mat"
+	"github.com/nlpodyssey/spago/pkg/ml/determinism"
 	"github.com/nlpodyssey/spago/pkg/ml/optimizers/gd/clipper"
+	"sort"
 	"sync"
 )
 
@@ -16,8 +18,10 @@ type GradientDescent struct {
 	method OptimizationMethod
 	// gradient clipper
 	gradClipper clipper.GradClipper
-	// set of observed optimizable parameters
-	observed map[Optimizable]bool
+	// set of observed optimizable parameters, with the order of tracking
+	observed map[Optimizable]int
+	// nextOrder is the order of the next tracked parameter
+	nextOrder int
 }
 
 type Option func(*GradientDescent)
@@ -41,7 +45,7 @@ func ClipGradByNorm(max, normType float64) Option {
 func NewOptimizer(method OptimizationMethod, opts ...Option) *GradientDescent {
 	optimizer := &GradientDescent{
 		method:   method,
-		observed: make(map[Optimizable]bool),
+		observed: make(map[Optimizable]int),
 	}
 	for _, opt := range opts {
 		opt(optimizer)
@@ -52,8 +56,9 @@ func NewOptimizer(method OptimizationMethod, opts ...Option) *GradientDescent {
 // Track tracks the parameters to optimize.
 func (o *GradientDescent) Track(vs ...Optimizable) {
 	for _, v := range vs {
-		if v.RequiresGrad() {
-			o.observed[v] = true
+		if _, ok := o.observed[v]; !ok && v.RequiresGrad() {
+			o.observed[v] = o.nextOrder
+			o.nextOrder++
 		}
 	}
 }
@@ -80,9 +85,24 @@ func (o *GradientDescent) Optimize() {
 	o.ZeroGrad()
 }
 
+// params returns the observed parameters. In deterministic mode they are sorted in the order of tracking,
+// otherwise their order is undefined.
+func (o *GradientDescent) params() []Optimizable {
+	params := make([]Optimizable, 0, len(o.observed))
+	for param := range o.observed {
+		params = append(params, param)
+	}
+	if determinism.Enabled() {
+		sort.Slice(params, func(i, j int) bool {
+			return o.observed[params[i]] < o.observed[params[j]]
+		})
+	}
+	return params
+}
+
 // updateParamsSerial applies the optimization method to all the observed parameters.
 func (o *GradientDescent) updateParamsSerial() {
-	for param := range o.observed {
+	for _, param := range o.params() {
 		if param.HasGrad() {
 			delta := o.method.Delta(param) // important: don't release delta here
 			param.ApplyDelta(delta)
@@ -90,9 +110,14 @@ func (o *GradientDescent) updateParamsSerial() {
 	}
 }
 
-// updateParams applies the optimization method to all the observed parameters concurrently.
+// updateParams applies the optimization method to all the observed parameters concurrently, or serially
+// in deterministic mode (see determinism.Enable).
 // TODO: distribute the workload proportionately to the number of available CPUs?
 func (o *GradientDescent) updateParams() {
+	if determinism.Enabled() {
+		o.updateParamsSerial()
+		return
+	}
 	var wg sync.WaitGroup
 	for key := range o.observed {
 		if key.HasGrad() {
@@ -113,7 +138,7 @@ func (o *GradientDescent) clipGrads() {
 		return
 	}
 	var gs []mat.Matrix
-	for param := range o.observed {
+	for _, param := range o.params() {
 		if param.HasGrad() { // don't consider grad at zero
 			gs = append(gs, param.Grad())
 		}
@@ -33,7 +33,7 @@ func GenerateBatches(size, batchFactor int, class func(i int) int) [][]int {
 	}
 	k := 0
 	for k < size {
-		class := rand.WeightedChoice(distribution) // this uses the global random
+		class := rand.WeightedChoice(distribution) // this uses the global random (see rand.SetGlobal)
 		if len(groupsByClass[class]) > 0 {
 			var exampleIndex int
 			exampleIndex, groupsByClass[class] = groupsByClass[class][0], groupsByClass[class][1:] // pop
@@ -0,0 +1,45 @@
+// Copyright 2020 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import "sync"
+
+// Group runs functions on their own goroutines and waits for them, like a sync.WaitGroup.
+// A panic raised by a function is recovered and raised again by Wait on the caller's goroutine, where it can be
+// recovered in turn (e.g. by ag.Try), instead of terminating the process. The zero value is ready to use.
+type Group struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	failure interface{}
+}
+
+// Go calls the function on a new goroutine.
+func (g *Group) Go(f func()) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer g.recover()
+		f()
+	}()
+}
+
+// recover records the first panic raised by the functions.
+func (g *Group) recover() {
+	if r := recover(); r != nil {
+		g.mu.Lock()
+		if g.failure == nil {
+			g.failure = r
+		}
+		g.mu.Unlock()
+	}
+}
+
+// Wait blocks until all the functions have returned, then raises the first panic of the functions again, if any.
+func (g *Group) Wait() {
+	g.wg.Wait()
+	if g.failure != nil {
+		panic(g.failure)
+	}
+}